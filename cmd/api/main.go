@@ -10,6 +10,8 @@ import (
 	"agentic_valuation/pkg/core/agent"
 	coreDebate "agentic_valuation/pkg/core/debate"
 	"agentic_valuation/pkg/core/prompt"
+	"agentic_valuation/pkg/core/sensitivity"
+	coreValuation "agentic_valuation/pkg/core/valuation"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -73,6 +75,8 @@ func main() {
 	// Initialize Debate Manager with Agent Manager
 	fmt.Println("Initializing Debate Manager...")
 	coreDebate.GetManager().SetAgentManager(agentMgr)
+	coreDebate.GetManager().SetExpectationSolver(coreValuation.DebateExpectations)
+	coreDebate.GetManager().SetSensitivityRanker(sensitivity.DebateTornado)
 
 	// Multi-Agent Debate endpoints
 	fmt.Println("Registering Debate Endpoints...")
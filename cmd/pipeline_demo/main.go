@@ -6,6 +6,7 @@ import (
 	"agentic_valuation/pkg/core/edgar"
 	"agentic_valuation/pkg/core/projection"
 	"agentic_valuation/pkg/core/prompt"
+	"agentic_valuation/pkg/core/sensitivity"
 	"agentic_valuation/pkg/core/valuation"
 	"bytes"
 	"context"
@@ -135,6 +136,8 @@ func main() {
 	// [MODIFIED] IsSimulation = false to trigger real LLM calls
 	ticker := "TSLA" // NOTE: Extracted ticker from path
 	orc := debate.NewOrchestrator("demo-session", ticker, report.Company, fmt.Sprintf("%d", report.FiscalYear), false, "automatic", mgr, nil)
+	orc.Expectations = valuation.DebateExpectations
+	orc.Sensitivity = sensitivity.DebateTornado
 
 	// Build Material Pool from Real Data
 	// This makes the loaded AAPL data available to the agents
@@ -259,18 +262,26 @@ func main() {
 	// =========================================================================
 	engine := projection.NewProjectionEngine(&projection.StandardSkeleton{})
 
-	projections := make([]*projection.ProjectedFinancials, 5)
-	currIS := prevIS
-	currBS := prevBS
+	// Depreciate PP&E by vintage so the D&A build can be audited
+	assumptions.FixedAssets = &projection.FixedAssetPolicy{HalfYear: true}
+	// Split book from cash taxes (NOLs, deferred taxes) so the DCF discounts cash taxes
+	assumptions.Tax = &projection.TaxPolicy{}
+
+	projections, err := engine.ProjectHorizon(projection.HorizonInput{
+		History:     projection.History{IncomeStatement: prevIS, BalanceSheet: prevBS, FiscalYear: 2024},
+		Assumptions: assumptions,
+		Years:       5,
+	})
+	if err != nil {
+		fmt.Printf("Error projecting statements: %v\n", err)
+		return
+	}
 
 	fmt.Println("\n[STEP] 4. Detailed Financial Statement Articulation")
 	fmt.Println("---------------------------------------------------------")
 
-	for i := 0; i < 5; i++ {
-		proj := engine.ProjectYear(currIS, currBS, nil, assumptions, 2025+i)
-		projections[i] = proj
-
-		fmt.Printf("\n--- Year %d ---\n", 2025+i)
+	for _, proj := range projections {
+		fmt.Printf("\n--- Year %d ---\n", proj.Year)
 
 		// Income Statement detail
 		is := proj.IncomeStatement
@@ -294,10 +305,10 @@ func main() {
 		cashPos := *bs.CurrentAssets.CashAndEquivalents.Value
 		fmt.Printf("BS: Cash Plug $%.1f | Assets $%.1f | Debt (LT) $%.1f | Equity $%.1f\n",
 			cashPos, assets, liab, equity)
-
-		currIS = proj.IncomeStatement
-		currBS = proj.BalanceSheet
 	}
+	fmt.Println()
+	fmt.Println(projection.FixedAssetMarkdown(projections))
+	fmt.Println(projection.TaxMarkdown(projections))
 
 	// =========================================================================
 	// STEP 5: DYNAMIC WACC RE-CALCULATION
@@ -311,20 +322,22 @@ func main() {
 		DebtToEquityRatio: assumptions.TargetDebtEquity, // Base target
 	}
 
-	// Generate Series
-	waccSeries := valuation.GenerateDynamicWACCSeries(waccInput, projections)
+	// Solve per-year WACC against market-value leverage and coverage-based debt pricing
+	termStructure, err := valuation.SolveWACCTermStructure(valuation.WACCTermStructureInput{
+		Base:           waccInput,
+		Projections:    projections,
+		TerminalGrowth: assumptions.TerminalGrowth,
+		RatingGrid:     valuation.DefaultRatingGrid,
+	})
+	if err != nil {
+		fmt.Printf("Error solving WACC term structure: %v\n", err)
+		return
+	}
+	waccSeries := termStructure.Rates()
 
 	fmt.Println("\n[STEP] 5. Dynamic WACC Calculation (Iterative Process)")
 	fmt.Println("---------------------------------------------------------")
-	fmt.Printf("%-6s | %-10s | %-10s | %-10s | %-10s\n", "Year", "Debt", "Equity", "D/E Ratio", "WACC")
-	for i, w := range waccSeries {
-		// Re-extract d/e for display
-		p := projections[i]
-		d := *p.BalanceSheet.NoncurrentLiabilities.LongTermDebt.Value
-		e := *p.BalanceSheet.Equity.RetainedEarningsDeficit.Value + *p.BalanceSheet.Equity.CommonStockAPIC.Value
-		fmt.Printf("%-6d | $%-9.1f | $%-9.1f | %-10.3f | %.2f%%\n",
-			2025+i, d, e, d/e, w*100)
-	}
+	fmt.Println(termStructure.Markdown())
 
 	// =========================================================================
 	// STEP 6: MASTER VALUATION SUITE
@@ -338,20 +351,25 @@ func main() {
 	masterInput := valuation.MasterValuationInput{
 		Projections:       projections,
 		SharesOutstanding: assumptions.SharesOutstanding,
-		// Net Debt = Debt - Cash
+		// Net Debt = Debt - Cash (fallback); the itemized bridge takes precedence
 		NetDebt:          (debt - cash),
+		Bridge:           valuation.NewEquityBridge(prevBS),
 		CurrentBookValue: equity,
 		WACC:             0.08, // Initial guess, overridden below
 		PeriodWACCs:      waccSeries,
 		CostOfEquity:     0.10, // Initial guess
 		TerminalGrowth:   assumptions.TerminalGrowth,
 		TaxRate:          assumptions.TaxRate,
+		PreTaxCostOfDebt: assumptions.PreTaxCostOfDebt, // APV unlevers WACC with Ke and Kd
 	}
 
 	initialWACCRes := valuation.CalculateWACC(waccInput)
 	masterInput.CostOfEquity = initialWACCRes.CostOfEquity
 
-	results := valuation.RunAllValuations(masterInput)
+	results, warnings := valuation.RunAllValuations(masterInput)
+	for _, w := range warnings {
+		fmt.Printf("⚠️  %s\n", w)
+	}
 
 	// --- CLI VISUALIZATION (CLAUDE CODE STYLE) ---
 	fmt.Println("\n🔮 VALUATION MODEL OUTPUT")
@@ -364,6 +382,7 @@ func main() {
 		TerminalGrowth:    assumptions.TerminalGrowth,
 		SharesOutstanding: assumptions.SharesOutstanding,
 		NetDebt:           masterInput.NetDebt,
+		Bridge:            masterInput.Bridge,
 		TaxRate:           assumptions.TaxRate,
 		PeriodWACCs:       waccSeries, // Pass dynamic WACC series if supported
 	}
@@ -384,7 +403,9 @@ func main() {
 
 		fmt.Printf("   ---------------------------------------------")
 		fmt.Printf("\n   💵 Enterprise Value:    $%.2f M\n", dcfDetail.EnterpriseValue)
-		fmt.Printf("   ➖ Net Debt:            $%.2f M\n", dcfInput.NetDebt)
+		for _, line := range dcfDetail.Bridge {
+			fmt.Printf("   ➖ %-20s $%.2f M\n", line.Label+":", line.Amount)
+		}
 		fmt.Printf("   =============================================\n")
 		fmt.Printf("   💎 EQUITY VALUE:        $%.2f M\n", dcfDetail.EquityValue)
 		fmt.Printf("   ÷  Shares Outstanding:  %.2f M\n", dcfInput.SharesOutstanding/1000000)
@@ -398,7 +419,7 @@ func main() {
 	// Just list others concisely
 	fmt.Println("\n📋 OTHER MODEL COMPARISONS")
 	for _, res := range results {
-		if res.ModelName != "Free Cash Flow for All Debt and Equity Valuation" {
+		if res.ModelName != valuation.ModelFCFF {
 			fmt.Printf("   - %-40s : $%.2f\n", res.ModelName, res.SharePrice)
 		}
 	}
@@ -3,8 +3,13 @@ package valuation
 import (
 	"agentic_valuation/pkg/core/agent"
 	"agentic_valuation/pkg/core/calc"
+	"agentic_valuation/pkg/core/comps"
 	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/projection"
+	"agentic_valuation/pkg/core/scenario"
+	"agentic_valuation/pkg/core/sensitivity"
 	"agentic_valuation/pkg/core/store"
+	corevaluation "agentic_valuation/pkg/core/valuation"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -25,10 +30,41 @@ func InitHandler(mgr *agent.Manager) {
 }
 
 type ValuationRequest struct {
-	Ticker string `json:"ticker"`
-	Year   int    `json:"year"`
+	Ticker        string                `json:"ticker"`
+	Year          int                   `json:"year"`
+	FootballField *FootballFieldRequest `json:"football_field,omitempty"`
 }
 
+// FootballFieldRequest selects the stored outputs the bars are built from.
+// Weights are keyed by bar model name; omit them to weight every bar equally.
+type FootballFieldRequest struct {
+	CaseID       string                         `json:"case_id,omitempty"` // Stored scenario results: DCF and equity model bars
+	Peers        []string                       `json:"peers,omitempty"`   // Tickers with stored golden records: trading comps bar
+	Comps        comps.Config                   `json:"comps_config,omitempty"`
+	Transactions []corevaluation.PeerComparable `json:"transactions,omitempty"`   // Precedent deals: transactions bar on the filing's metrics
+	Sensitivity  *SensitivityGridRequest        `json:"sensitivity,omitempty"`    // Grid DCF bar (replaces the case's scenario DCF bar)
+	LBO          *corevaluation.LBOInput        `json:"lbo,omitempty"`            // Sponsor case: LBO bar at TargetIRR ± LBOIRRSpread
+	LBOIRRSpread float64                        `json:"lbo_irr_spread,omitempty"` // Default 5pp
+	Weights      map[string]float64             `json:"weights,omitempty"`
+}
+
+// SensitivityGridRequest is a deterministic case projected from the filing and
+// flexed over two drivers; the DCF bar spans every cell of the grid.
+// Valuation's shares and net debt default to the filing's.
+type SensitivityGridRequest struct {
+	Assumptions projection.ProjectionAssumptions   `json:"assumptions"`
+	Valuation   corevaluation.MasterValuationInput `json:"valuation"`
+	Years       int                                `json:"years,omitempty"` // Default 5
+	Row         sensitivity.Range                  `json:"row"`
+	Col         sensitivity.Range                  `json:"col"`
+}
+
+// defaultLBOIRRSpread widens the LBO bar around the sponsor's target IRR
+const defaultLBOIRRSpread = 0.05
+
+// defaultSensitivityYears is the projection horizon of the sensitivity grid
+const defaultSensitivityYears = 5
+
 type ValuationResponse struct {
 	Financials  *edgar.FSAPDataResponse  `json:"financials"`
 	Analysis    *calc.CommonSizeAnalysis `json:"analysis"`
@@ -36,6 +72,8 @@ type ValuationResponse struct {
 	Penman      map[string]interface{}   `json:"penman"`
 	Forensics   map[string]interface{}   `json:"forensics"`
 	Aggregation map[string]interface{}   `json:"aggregation"`
+
+	FootballField *corevaluation.FootballField `json:"football_field,omitempty"`
 }
 
 func floatPtr(f float64) *float64 { return &f }
@@ -101,7 +139,7 @@ func HandleValuationReport(w http.ResponseWriter, r *http.Request) {
 		// `CommonSizeAnalysis` is derived.
 		// So we use cachedData as input to Analysis.
 
-		processAndRespond(w, cachedData, ticker, req.Year)
+		processAndRespond(w, cachedData, ticker, req.Year, req.FootballField)
 		return
 	}
 
@@ -158,10 +196,10 @@ func HandleValuationReport(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 6. Process and Respond
-	processAndRespond(w, extracted, ticker, req.Year)
+	processAndRespond(w, extracted, ticker, req.Year, req.FootballField)
 }
 
-func processAndRespond(w http.ResponseWriter, extracted *edgar.FSAPDataResponse, ticker string, year int) {
+func processAndRespond(w http.ResponseWriter, extracted *edgar.FSAPDataResponse, ticker string, year int, ffReq *FootballFieldRequest) {
 	// Context for sub-agents
 	// ctx := context.Background() // Unused
 	llmProvider := agentManager.GetProvider("data_extraction")
@@ -276,10 +314,144 @@ func processAndRespond(w http.ResponseWriter, extracted *edgar.FSAPDataResponse,
 		},
 	}
 
+	// Football field: build the bars from stored outputs and blend them against the year-end price
+	if ffReq != nil {
+		bars, err := footballBars(context.Background(), ffReq, extracted, ticker, year)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Football field failed: %v", err), http.StatusBadRequest)
+			return
+		}
+		ff, err := corevaluation.BuildFootballField(bars, ffReq.Weights, corevaluation.CurrentSharePrice(extracted.SupplementalData))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Football field failed: %v", err), http.StatusBadRequest)
+			return
+		}
+		resp.FootballField = ff
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
 
+// footballBars builds the bars server-side: DCF and equity models from the case's
+// stored scenario results (the grid DCF bar replaces the scenario one when both are
+// requested), trading comps from stored golden records, precedent transactions on
+// the filing's metrics, and the LBO from the sponsor case bridged with the filing's
+// balance sheet
+func footballBars(ctx context.Context, req *FootballFieldRequest, extracted *edgar.FSAPDataResponse, ticker string, year int) ([]corevaluation.FootballBar, error) {
+	var bars []corevaluation.FootballBar
+
+	if req.CaseID != "" {
+		records, err := store.NewScenarioRepo(store.GetPool()).LoadScenarios(ctx, req.CaseID)
+		if err != nil {
+			return nil, err
+		}
+		res, err := scenario.ResultFromRecords(req.CaseID, records)
+		if err != nil {
+			return nil, err
+		}
+		bars = append(bars, res.FootballBars()...)
+	}
+
+	if len(req.Peers) > 0 {
+		repo := store.NewAnalysisRepo()
+		target, _, err := repo.Load(ctx, ticker)
+		if err != nil {
+			return nil, err
+		}
+		peers := make([]comps.Peer, 0, len(req.Peers))
+		for _, t := range req.Peers {
+			rec, _, err := repo.Load(ctx, strings.ToUpper(t))
+			if err != nil {
+				return nil, err
+			}
+			peers = append(peers, comps.Peer{Ticker: rec.Ticker, Record: rec})
+		}
+		res, err := comps.Analyze(comps.Target{Record: target, FiscalYear: year}, peers, req.Comps)
+		if err != nil {
+			return nil, err
+		}
+		bars = append(bars, res.FootballBar())
+	}
+
+	if len(req.Transactions) > 0 {
+		deals := make([]corevaluation.PeerComparable, len(req.Transactions))
+		for i, d := range req.Transactions {
+			d.IsTransaction = true
+			deals[i] = d
+		}
+		period := &projection.ProjectedFinancials{IncomeStatement: &extracted.IncomeStatement, CashFlow: &extracted.CashFlowStatement}
+		target := corevaluation.MetricInputFrom(period, &extracted.BalanceSheet, filingShares(extracted))
+		bars = append(bars, corevaluation.RelativeBar(corevaluation.FootballTransactions, corevaluation.CalculateTransactions(target, deals)))
+	}
+
+	if req.Sensitivity != nil {
+		bar, err := sensitivityBar(req.Sensitivity, extracted, year)
+		if err != nil {
+			return nil, err
+		}
+		replaced := false
+		for i := range bars {
+			if bars[i].Model == bar.Model {
+				bars[i], replaced = bar, true
+			}
+		}
+		if !replaced {
+			bars = append(bars, bar)
+		}
+	}
+
+	if req.LBO != nil {
+		spread := req.LBOIRRSpread
+		if spread == 0 {
+			spread = defaultLBOIRRSpread
+		}
+		netDebt := corevaluation.NewEquityBridge(&extracted.BalanceSheet).Total()
+		bar, err := corevaluation.LBOIRRBar(*req.LBO, spread, netDebt, filingShares(extracted))
+		if err != nil {
+			return nil, err
+		}
+		bars = append(bars, bar)
+	}
+	return bars, nil
+}
+
+// sensitivityBar projects the grid's case from the filing and spans its DCF cells
+func sensitivityBar(g *SensitivityGridRequest, extracted *edgar.FSAPDataResponse, year int) (corevaluation.FootballBar, error) {
+	years := g.Years
+	if years == 0 {
+		years = defaultSensitivityYears
+	}
+	hist := projection.History{IncomeStatement: &extracted.IncomeStatement, BalanceSheet: &extracted.BalanceSheet, FiscalYear: year}
+	projections, err := projection.NewProjectionEngine(nil).ProjectHorizon(projection.HorizonInput{History: hist, Assumptions: g.Assumptions, Years: years})
+	if err != nil {
+		return corevaluation.FootballBar{}, err
+	}
+	input := g.Valuation
+	input.Projections = projections
+	if input.SharesOutstanding == 0 {
+		input.SharesOutstanding = filingShares(extracted)
+	}
+	if input.Bridge == nil && input.NetDebt == 0 {
+		input.Bridge = corevaluation.NewEquityBridge(&extracted.BalanceSheet)
+	}
+	grid, err := sensitivity.TwoWay(sensitivity.Base{Valuation: input, Assumptions: g.Assumptions, History: hist}, g.Row, g.Col)
+	if err != nil {
+		return corevaluation.FootballBar{}, err
+	}
+	return grid.FootballBar(sensitivity.ModelDCF), nil
+}
+
+// filingShares is the filing's diluted share count, else basic
+func filingShares(extracted *edgar.FSAPDataResponse) float64 {
+	for _, v := range []*edgar.FSAPValue{extracted.SupplementalData.SharesOutstandingDiluted, extracted.SupplementalData.SharesOutstandingBasic} {
+		if v != nil && v.Value != nil && *v.Value > 0 {
+			return *v.Value
+		}
+	}
+	return 0
+}
+
 func explodeHistory(data *edgar.FSAPDataResponse) []*edgar.FSAPDataResponse {
 	years := []int{data.FiscalYear - 1, data.FiscalYear - 2}
 	history := make([]*edgar.FSAPDataResponse, 0)
@@ -1,6 +1,9 @@
 package assumption
 
 import (
+	"errors"
+	"math"
+	"strings"
 	"testing"
 
 	"agentic_valuation/pkg/core/edgar"
@@ -231,3 +234,192 @@ func TestNodeIntegrationWithProjection(t *testing.T) {
 		t.Error("'custom-driver' should NOT be recognized as skeleton ID")
 	}
 }
+
+func TestAssumptionSet_Schedule(t *testing.T) {
+	as := NewAssumptionSet("case-123", "base")
+	_ = as.AddNode(&Node{ID: "growth", Variable: "revenue_growth", Unit: "%", YearlyValues: []float64{20, 10, 5}})
+	_ = as.AddNode(&Node{ID: "dso", Variable: "dso", Unit: "days", YearlyValues: []float64{40, 38}})
+	_ = as.AddNode(&Node{ID: "flat", Variable: "cogs_percent", Unit: "%", Value: 60})
+	_ = as.AddNode(&Node{ID: "other", Variable: "ebit_margin", Unit: "%", YearlyValues: []float64{30}})
+
+	schedule, err := as.Schedule()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(schedule) != 2 {
+		t.Fatalf("expected revenue_growth and dso to be scheduled, got %v", schedule)
+	}
+	if got := schedule["revenue_growth"]; got[0] != 0.20 || got[2] != 0.05 {
+		t.Errorf("percent path not converted to decimals: %v", got)
+	}
+	if got := schedule["dso"]; got[1] != 38 {
+		t.Errorf("dso path %v, want days unchanged", got)
+	}
+
+	// An alias binding the same driver is ambiguous
+	_ = as.AddNode(&Node{ID: "growth-alt", Variable: "rev_growth", Unit: "%", YearlyValues: []float64{15}})
+	if _, err := as.Schedule(); err == nil || !strings.Contains(err.Error(), "revenue_growth") {
+		t.Errorf("expected a conflict on revenue_growth, got %v", err)
+	}
+	if _, _, err := as.ToProjection(projection.ProjectionAssumptions{}); err == nil {
+		t.Error("expected ToProjection to reject the conflict")
+	}
+}
+
+func TestAssumptionSet_FormulaNode(t *testing.T) {
+	as := NewAssumptionSet("case-123", "base")
+	_ = as.AddNode(&Node{ID: "stores", Variable: "store_count"})
+
+	// An assumption node's variable is not in the projection graph
+	node := &Node{ID: "revenue-build", StrategyName: "Formula", Formula: "store_count * revenue[t-1]"}
+	err := as.AddNode(node)
+	var ferr *projection.FormulaError
+	if !errors.As(err, &ferr) || ferr.Pos != 0 {
+		t.Fatalf("expected an unknown 'store_count' at position 0, got %v", err)
+	}
+
+	if err := as.Skeleton.AddDriver("revenue", &projection.Node{ID: "store_count", Type: projection.NodeTypeDriver}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	node.Formula = "store_count * sales_per_store"
+	if err := as.AddNode(node); !errors.As(err, &ferr) || ferr.Pos != 14 {
+		t.Fatalf("expected an unknown 'sales_per_store' at position 14, got %v", err)
+	}
+
+	node.Formula = "store_count * revenue[t-1] / 1000"
+	if err := as.AddNode(node); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	node.Formula = "store_count *"
+	if err := as.UpdateNode(node); !errors.As(err, &ferr) || ferr.Pos != 13 {
+		t.Errorf("expected a parse error at position 13, got %v", err)
+	}
+}
+
+func TestNode_ExpandTrend(t *testing.T) {
+	target := 4.0
+	tests := []struct {
+		node Node
+		want []float64
+	}{
+		{Node{TrendType: TrendConstant, Value: 10}, []float64{10, 10, 10, 10}},
+		{Node{TrendType: TrendLinear, Value: 10, TrendTarget: &target}, []float64{8.5, 7, 5.5, 4}},
+		{Node{TrendType: TrendExponential, Value: 100, TrendRate: 0.1}, []float64{110, 121, 133.1, 146.41}},
+		{Node{TrendType: TrendManual, Value: 10, YearlyValues: []float64{9, 8}}, []float64{9, 8, 8, 8}},
+		{Node{Value: 3}, []float64{3, 3, 3, 3}},
+	}
+	for _, tt := range tests {
+		node := tt.node
+		node.ProjectionYears = 4
+		if err := node.ExpandTrend(); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.node.TrendType, err)
+		}
+		for i, want := range tt.want {
+			if math.Abs(node.YearlyValues[i]-want) > 1e-9 {
+				t.Errorf("%s: year %d = %.4f, want %.4f", tt.node.TrendType, i+1, node.YearlyValues[i], want)
+			}
+		}
+	}
+
+	// The S-curve ends on the target and moves most in the middle of the horizon
+	s := Node{TrendType: TrendSCurve, Value: 0, TrendTarget: &target, ProjectionYears: 6}
+	if err := s.ExpandTrend(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(s.YearlyValues[5]-4) > 1e-9 || s.YearlyValues[3]-s.YearlyValues[2] <= s.YearlyValues[0] {
+		t.Errorf("unexpected S-curve: %v", s.YearlyValues)
+	}
+
+	bad := Node{ID: "x", TrendType: "Sideways", ProjectionYears: 2}
+	if err := bad.ExpandTrend(); err == nil {
+		t.Error("expected an unknown trend type to be rejected")
+	}
+}
+
+func TestAssumptionSet_ExpandTrendsAggregates(t *testing.T) {
+	as := NewAssumptionSet("case-123", "base")
+	segments := "segments"
+	total := "revenue"
+	nodes := []*Node{
+		{ID: total, AggregationType: AggregateSum, ChildrenIDs: []string{segments, "services"}},
+		{ID: segments, AggregationType: AggregateWeighted},
+		{ID: "hardware", ParentID: &segments, Value: 100, Weight: 3, TrendType: TrendExponential, TrendRate: 0.1, ProjectionYears: 2},
+		{ID: "software", ParentID: &segments, Value: 20, Weight: 1, TrendType: TrendConstant, ProjectionYears: 2},
+		{ID: "services", Value: 50, TrendType: TrendConstant, ProjectionYears: 3},
+	}
+	for _, n := range nodes {
+		if err := as.AddNode(n); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := as.ExpandTrends(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	seg := as.Nodes[segments]
+	if seg.Value != 80 || math.Abs(seg.YearlyValues[0]-87.5) > 1e-9 || math.Abs(seg.YearlyValues[1]-95.75) > 1e-9 {
+		t.Errorf("unexpected weighted segments: %v %v", seg.Value, seg.YearlyValues)
+	}
+	rev := as.Nodes[total]
+	if len(rev.YearlyValues) != 3 || rev.Value != 130 || math.Abs(rev.YearlyValues[2]-145.75) > 1e-9 {
+		t.Errorf("unexpected revenue rollup: %v %v", rev.Value, rev.YearlyValues)
+	}
+
+	as.Nodes["services"].ParentID = &total
+	as.Nodes[total].ParentID = &segments
+	as.Nodes[segments].ChildrenIDs = []string{total}
+	if err := as.Aggregate(); err == nil {
+		t.Error("expected a hierarchy cycle to be rejected")
+	}
+}
+
+func TestAssumptionSet_ProjectionRoundTrip(t *testing.T) {
+	as := NewAssumptionSet("case-123", "base")
+	target := 4.0
+	if err := as.AddNode(&Node{ID: "rev-growth", Variable: "revenue_growth", Unit: "%", Value: 10, TrendType: TrendLinear, TrendTarget: &target, ProjectionYears: 3}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := as.AddNode(&Node{ID: "ebit-margin", Variable: "ebit_margin", Unit: "%", Value: 30}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	a, schedule, err := as.ToProjection(projection.ProjectionAssumptions{COGSPercent: 0.6})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.RevenueGrowth != 0.10 || a.COGSPercent != 0.6 {
+		t.Errorf("unexpected drivers: growth %v, COGS %v", a.RevenueGrowth, a.COGSPercent)
+	}
+	if path := schedule["revenue_growth"]; len(path) != 3 || math.Abs(path[0]-0.08) > 1e-9 || math.Abs(path[2]-0.04) > 1e-9 {
+		t.Errorf("unexpected growth schedule: %v", path)
+	}
+	if len(as.Nodes["rev-growth"].YearlyValues) != 0 {
+		t.Errorf("ToProjection expanded the set in place: %v", as.Nodes["rev-growth"].YearlyValues)
+	}
+
+	// The engine baseline flows back: bound nodes re-expand, new drivers get nodes
+	back := NewAssumptionSet("case-123", "base")
+	if err := back.FromProjection(a, schedule, 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	growth, ok := back.NodeByVariable("revenue_growth")
+	if !ok || growth.Unit != "%" || growth.TrendType != TrendManual || math.Abs(growth.YearlyValues[2]-4) > 1e-9 {
+		t.Fatalf("unexpected growth node: %+v", growth)
+	}
+	cogs, ok := back.NodeByVariable("cogs_percent")
+	if !ok || cogs.Value != 60 || cogs.Label != "Cogs Percent" || len(cogs.YearlyValues) != 3 {
+		t.Errorf("unexpected COGS node: %+v", cogs)
+	}
+	if _, ok := back.NodeByVariable("tax_rate"); ok {
+		t.Error("zero drivers should not create nodes")
+	}
+
+	a.COGSPercent = 0.55
+	if err := back.FromProjection(a, nil, 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(cogs.Value-55) > 1e-9 || math.Abs(cogs.YearlyValues[2]-55) > 1e-9 || math.Abs(growth.YearlyValues[2]-4) > 1e-9 {
+		t.Errorf("baseline update: COGS %v %v, growth path %v", cogs.Value, cogs.YearlyValues, growth.YearlyValues)
+	}
+}
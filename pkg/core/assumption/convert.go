@@ -0,0 +1,147 @@
+package assumption
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"agentic_valuation/pkg/core/projection"
+)
+
+// =============================================================================
+// PROJECTION SYNC
+// Frontend edits flow into the engine as ProjectionAssumptions plus a
+// DriverSchedule; engine baselines flow back as driver-bound nodes.
+// =============================================================================
+
+// ToProjection overlays every driver-bound node on the base: the current value as
+// the driver and the trend-expanded yearly path as its schedule. Trends are
+// expanded on a copy, so the set itself is not modified.
+func (as *AssumptionSet) ToProjection(base projection.ProjectionAssumptions) (projection.ProjectionAssumptions, projection.DriverSchedule, error) {
+	a := base.Clone()
+	expanded, err := as.Clone()
+	if err != nil {
+		return a, nil, err
+	}
+	if err := expanded.ExpandTrends(); err != nil {
+		return a, nil, err
+	}
+	bound, err := expanded.driverNodes()
+	if err != nil {
+		return a, nil, err
+	}
+	for _, name := range sortedDrivers(bound) {
+		node := bound[name]
+		if err := a.SetDriver(name, node.Decimal()); err != nil {
+			return a, nil, fmt.Errorf("node '%s': %w", node.ID, err)
+		}
+	}
+	schedule, err := expanded.Schedule()
+	return a, schedule, err
+}
+
+// FromProjection writes an engine baseline into the set over a horizon of years.
+// Nodes bound to a driver take its value and re-expand their trend; a scheduled
+// driver sets a Manual path. Drivers without a node get a Constant node when
+// non-zero or scheduled. Manual nodes keep their entered path and parents are
+// re-aggregated from their children.
+func (as *AssumptionSet) FromProjection(a projection.ProjectionAssumptions, schedule projection.DriverSchedule, years int) error {
+	paths := make(map[string][]float64, len(schedule))
+	for name, path := range schedule {
+		canonical, ok := projection.ResolveDriverName(name)
+		if !ok {
+			return fmt.Errorf("unknown projection driver '%s'", name)
+		}
+		paths[canonical] = path
+	}
+	bound, err := as.driverNodes()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range projection.DriverNames() {
+		v, _ := a.GetDriver(name)
+		path := paths[name]
+		node, ok := bound[name]
+		if !ok {
+			if v == 0 && len(path) == 0 {
+				continue
+			}
+			node = &Node{
+				ID:        strings.ReplaceAll(name, "_", "-"),
+				Label:     driverLabel(name),
+				Variable:  name,
+				TrendType: TrendConstant,
+				Unit:      projection.DriverUnit(name),
+				IsAtomic:  true,
+			}
+			if err := as.AddNode(node); err != nil {
+				return err
+			}
+		} else if node.TrendType == TrendManual || len(as.childrenOf(node)) > 0 {
+			continue
+		}
+
+		node.Value = node.fromDecimal(v)
+		if years > 0 {
+			node.ProjectionYears = years
+		}
+		if len(path) > 0 {
+			node.TrendType = TrendManual
+			node.YearlyValues = make([]float64, len(path))
+			for i, p := range path {
+				node.YearlyValues[i] = node.fromDecimal(p)
+			}
+		}
+		if err := node.ExpandTrend(); err != nil {
+			return err
+		}
+	}
+	return as.Aggregate()
+}
+
+// driverNodes maps each canonical projection driver to the node bound to it.
+// Two nodes binding one driver (directly or by alias) is an error.
+func (as *AssumptionSet) driverNodes() (map[string]*Node, error) {
+	bound := make(map[string]*Node)
+	for _, id := range as.sortedIDs() {
+		node := as.Nodes[id]
+		canonical, ok := projection.ResolveDriverName(node.Variable)
+		if !ok {
+			continue
+		}
+		if other, taken := bound[canonical]; taken {
+			return nil, fmt.Errorf("nodes '%s' and '%s' both bind driver '%s'", other.ID, id, canonical)
+		}
+		bound[canonical] = node
+	}
+	return bound, nil
+}
+
+func sortedDrivers(bound map[string]*Node) []string {
+	names := make([]string, 0, len(bound))
+	for name := range bound {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fromDecimal converts a decimal driver value into the node's unit
+func (n *Node) fromDecimal(v float64) float64 {
+	if n.Unit == "%" {
+		return v * 100
+	}
+	return v
+}
+
+// driverLabel titles a driver name (e.g. "revenue_growth" -> "Revenue Growth")
+func driverLabel(name string) string {
+	words := strings.Split(name, "_")
+	for i, w := range words {
+		if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,242 @@
+package assumption
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// TREND EXPANSION & AGGREGATION
+// Fills YearlyValues from each atomic node's trend, then rolls children up
+// into their parents. Values stay in the node's unit.
+// =============================================================================
+
+// Aggregation types for parent nodes
+const (
+	AggregateSum      = "sum"
+	AggregateAverage  = "average"
+	AggregateWeighted = "weighted"
+)
+
+// defaultSCurveSteepness is the logistic slope when TrendRate is not set
+const defaultSCurveSteepness = 1.0
+
+// ExpandTrend fills YearlyValues for ProjectionYears from Value:
+//   - Constant holds Value
+//   - Linear moves in equal steps to TrendTarget in the final year
+//   - S-Curve follows a logistic path to TrendTarget, steepest mid-horizon
+//   - Exponential compounds Value at TrendRate a year
+//   - Manual keeps the entered values, holding the last one to the horizon
+//
+// A node without a trend is Manual when it has yearly values and Constant otherwise.
+func (n *Node) ExpandTrend() error {
+	years := n.ProjectionYears
+	if years <= 0 {
+		years = len(n.YearlyValues)
+	}
+	target := n.Value
+	if n.TrendTarget != nil {
+		target = *n.TrendTarget
+	}
+	trend := n.TrendType
+	if trend == "" {
+		trend = TrendConstant
+		if len(n.YearlyValues) > 0 {
+			trend = TrendManual
+		}
+	}
+
+	path := make([]float64, years)
+	switch trend {
+	case TrendConstant:
+		for i := range path {
+			path[i] = n.Value
+		}
+	case TrendLinear:
+		for i := range path {
+			path[i] = n.Value + (target-n.Value)*float64(i+1)/float64(years)
+		}
+	case TrendSCurve:
+		k := n.TrendRate
+		if k <= 0 {
+			k = defaultSCurveSteepness
+		}
+		logistic := func(t float64) float64 { return 1 / (1 + math.Exp(-k*(t-float64(years)/2))) }
+		lo, hi := logistic(0), logistic(float64(years))
+		for i := range path {
+			path[i] = n.Value + (target-n.Value)*(logistic(float64(i+1))-lo)/(hi-lo)
+		}
+	case TrendExponential:
+		for i := range path {
+			path[i] = n.Value * math.Pow(1+n.TrendRate, float64(i+1))
+		}
+	case TrendManual:
+		last := n.Value
+		for i := range path {
+			if i < len(n.YearlyValues) {
+				last = n.YearlyValues[i]
+			}
+			path[i] = last
+		}
+	default:
+		return fmt.Errorf("node '%s': unknown trend type '%s'", n.ID, n.TrendType)
+	}
+	n.YearlyValues = path
+	n.UpdatedAt = time.Now()
+	return nil
+}
+
+// ExpandTrends expands every atomic node's trend and aggregates the parents
+func (as *AssumptionSet) ExpandTrends() error {
+	for _, id := range as.sortedIDs() {
+		node := as.Nodes[id]
+		if len(as.childrenOf(node)) > 0 {
+			continue
+		}
+		if err := node.ExpandTrend(); err != nil {
+			return err
+		}
+	}
+	return as.Aggregate()
+}
+
+// Aggregate computes every parent's Value and YearlyValues from its children
+// (ChildrenIDs and nodes naming it as ParentID) by its AggregationType, children
+// first. A parent without an aggregation type sums its children.
+func (as *AssumptionSet) Aggregate() error {
+	done := make(map[string]bool)
+	visiting := make(map[string]bool)
+	var visit func(id string) error
+	visit = func(id string) error {
+		if done[id] {
+			return nil
+		}
+		if visiting[id] {
+			return fmt.Errorf("node '%s' is its own ancestor", id)
+		}
+		visiting[id] = true
+		node, ok := as.Nodes[id]
+		if !ok {
+			return fmt.Errorf("node '%s' not found", id)
+		}
+		children := as.childrenOf(node)
+		for _, child := range children {
+			if err := visit(child); err != nil {
+				return err
+			}
+		}
+		if len(children) > 0 {
+			if err := as.aggregate(node, children); err != nil {
+				return err
+			}
+		}
+		visiting[id], done[id] = false, true
+		return nil
+	}
+	for _, id := range as.sortedIDs() {
+		if err := visit(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// aggregate combines the children's values into the parent, year by year up to
+// the longest child path (a shorter path holds its last value)
+func (as *AssumptionSet) aggregate(parent *Node, childIDs []string) error {
+	children := make([]*Node, len(childIDs))
+	years := 0
+	for i, id := range childIDs {
+		children[i] = as.Nodes[id]
+		if len(children[i].YearlyValues) > years {
+			years = len(children[i].YearlyValues)
+		}
+	}
+
+	var combine func(values []float64) float64
+	switch parent.AggregationType {
+	case "", AggregateSum:
+		combine = func(values []float64) float64 {
+			total := 0.0
+			for _, v := range values {
+				total += v
+			}
+			return total
+		}
+	case AggregateAverage:
+		combine = func(values []float64) float64 {
+			total := 0.0
+			for _, v := range values {
+				total += v
+			}
+			return total / float64(len(values))
+		}
+	case AggregateWeighted:
+		weights := 0.0
+		for _, c := range children {
+			weights += c.Weight
+		}
+		if weights == 0 {
+			return fmt.Errorf("node '%s': weighted aggregation needs child weights", parent.ID)
+		}
+		combine = func(values []float64) float64 {
+			total := 0.0
+			for i, v := range values {
+				total += children[i].Weight * v
+			}
+			return total / weights
+		}
+	default:
+		return fmt.Errorf("node '%s': unknown aggregation type '%s'", parent.ID, parent.AggregationType)
+	}
+
+	values := make([]float64, len(children))
+	for i, c := range children {
+		values[i] = c.Value
+	}
+	parent.Value = combine(values)
+	parent.YearlyValues = make([]float64, years)
+	for y := 0; y < years; y++ {
+		for i, c := range children {
+			values[i] = c.Value
+			if n := len(c.YearlyValues); n > 0 {
+				values[i] = c.YearlyValues[min(y, n-1)]
+			}
+		}
+		parent.YearlyValues[y] = combine(values)
+	}
+	parent.ProjectionYears = years
+	parent.UpdatedAt = time.Now()
+	return nil
+}
+
+// childrenOf lists a node's children in ID order
+func (as *AssumptionSet) childrenOf(node *Node) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, id := range node.ChildrenIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, child := range as.GetChildren(node.ID) {
+		if !seen[child.ID] {
+			seen[child.ID] = true
+			ids = append(ids, child.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (as *AssumptionSet) sortedIDs() []string {
+	ids := make([]string, 0, len(as.Nodes))
+	for id := range as.Nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
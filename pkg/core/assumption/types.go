@@ -73,7 +73,9 @@ type Node struct {
 	// Projection settings
 	TrendType       TrendType `json:"trend_type"`
 	ProjectionYears int       `json:"projection_years"`
-	YearlyValues    []float64 `json:"yearly_values"` // Projected values for each year
+	YearlyValues    []float64 `json:"yearly_values"`          // Projected values for each year
+	TrendTarget     *float64  `json:"trend_target,omitempty"` // Final-year value for Linear and S-Curve (nil = hold Value)
+	TrendRate       float64   `json:"trend_rate,omitempty"`   // Annual growth for Exponential (decimal); steepness for S-Curve
 
 	// Hierarchy
 	ParentID    *string  `json:"parent_id,omitempty"`
@@ -81,7 +83,8 @@ type Node struct {
 	IsAtomic    bool     `json:"is_atomic"` // Has trend logic (leaf node)
 
 	// Aggregation (for parent nodes)
-	AggregationType string `json:"aggregation_type,omitempty"` // "sum", "average", "weighted"
+	AggregationType string  `json:"aggregation_type,omitempty"` // "sum", "average", "weighted"
+	Weight          float64 `json:"weight,omitempty"`           // Weight under a "weighted" parent
 
 	// Monte Carlo distribution
 	Distribution DistributionType `json:"distribution"`
@@ -96,6 +99,7 @@ type Node struct {
 	// Strategy reference (links to projection.ProjectionStrategy)
 	StrategyName   string             `json:"strategy_name,omitempty"`
 	StrategyParams map[string]float64 `json:"strategy_params,omitempty"`
+	Formula        string             `json:"formula,omitempty"` // Expression when StrategyName is "Formula"
 
 	// Timestamps
 	CreatedAt time.Time `json:"created_at"`
@@ -143,6 +147,10 @@ func (as *AssumptionSet) AddNode(node *Node) error {
 		return fmt.Errorf("node '%s' already exists", node.ID)
 	}
 
+	if err := as.validateFormula(node); err != nil {
+		return err
+	}
+
 	node.CreatedAt = time.Now()
 	node.UpdatedAt = node.CreatedAt
 	as.Nodes[node.ID] = node
@@ -164,6 +172,9 @@ func (as *AssumptionSet) UpdateNode(node *Node) error {
 	if _, exists := as.Nodes[node.ID]; !exists {
 		return fmt.Errorf("node '%s' not found", node.ID)
 	}
+	if err := as.validateFormula(node); err != nil {
+		return err
+	}
 
 	node.UpdatedAt = time.Now()
 	as.Nodes[node.ID] = node
@@ -187,6 +198,23 @@ func (as *AssumptionSet) DeleteNode(id string) error {
 	return nil
 }
 
+// validateFormula parses a formula node's expression and checks its references
+// against what the projection graph evaluates: skeleton nodes and the drivers
+// attached to the set's skeleton. Other assumption nodes are not in the graph.
+func (as *AssumptionSet) validateFormula(node *Node) error {
+	if node.StrategyName != "Formula" {
+		return nil
+	}
+	formula, err := projection.ParseFormula(node.Formula)
+	if err != nil {
+		return fmt.Errorf("node '%s': %w", node.ID, err)
+	}
+	if err := as.Skeleton.ValidateFormula(formula); err != nil {
+		return fmt.Errorf("node '%s': %w", node.ID, err)
+	}
+	return nil
+}
+
 // GetChildren returns all child nodes of a parent
 func (as *AssumptionSet) GetChildren(parentID string) []*Node {
 	var children []*Node
@@ -212,3 +240,75 @@ func FromJSON(data []byte) (*AssumptionSet, error) {
 	as.Skeleton = projection.NewStandardSkeleton()
 	return &as, nil
 }
+
+// Clone returns a deep copy of the assumption set (e.g. to derive a scenario).
+// The skeleton is shared: projections only read it.
+func (as *AssumptionSet) Clone() (*AssumptionSet, error) {
+	data, err := as.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone assumption set: %w", err)
+	}
+	out, err := FromJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	if as.Skeleton != nil {
+		out.Skeleton = as.Skeleton
+	}
+	return out, nil
+}
+
+// NodeByVariable finds the node bound to a variable (e.g. "revenue_growth")
+func (as *AssumptionSet) NodeByVariable(variable string) (*Node, bool) {
+	for _, node := range as.Nodes {
+		if node.Variable == variable {
+			return node, true
+		}
+	}
+	return nil, false
+}
+
+// Decimal returns the node's current value as a decimal driver ("%" units are divided by 100)
+func (n *Node) Decimal() float64 {
+	if n.Unit == "%" {
+		return n.Value / 100
+	}
+	return n.Value
+}
+
+// SetDecimal writes a decimal driver value in the node's unit and flattens the projected path
+func (n *Node) SetDecimal(v float64) {
+	if n.Unit == "%" {
+		v *= 100
+	}
+	n.Value = v
+	for i := range n.YearlyValues {
+		n.YearlyValues[i] = v
+	}
+	n.UpdatedAt = time.Now()
+}
+
+// Schedule collects the yearly paths of nodes bound to projection drivers, keyed
+// by canonical driver name (decimal values; nodes without YearlyValues are left
+// to the base assumptions). Two nodes binding one driver is an error.
+func (as *AssumptionSet) Schedule() (projection.DriverSchedule, error) {
+	bound, err := as.driverNodes()
+	if err != nil {
+		return nil, err
+	}
+	schedule := make(projection.DriverSchedule)
+	for name, node := range bound {
+		if len(node.YearlyValues) == 0 {
+			continue
+		}
+		path := make([]float64, len(node.YearlyValues))
+		for i, v := range node.YearlyValues {
+			path[i] = v
+			if node.Unit == "%" {
+				path[i] = v / 100
+			}
+		}
+		schedule[name] = path
+	}
+	return schedule, nil
+}
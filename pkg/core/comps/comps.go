@@ -0,0 +1,109 @@
+package comps
+
+import (
+	"agentic_valuation/pkg/core/valuation"
+	"fmt"
+)
+
+// Analyze values the target against the peer set
+func Analyze(target Target, peers []Peer, cfg Config) (*Result, error) {
+	if target.Record == nil {
+		return nil, fmt.Errorf("target has no golden record")
+	}
+	if len(peers) == 0 {
+		return nil, fmt.Errorf("peer set is empty")
+	}
+	snap := target.Record.Timeline[target.FiscalYear]
+	if snap == nil {
+		return nil, fmt.Errorf("target has no fiscal year %d", target.FiscalYear)
+	}
+
+	shares := target.SharesOutstanding
+	if shares == 0 {
+		shares = recordShares(snap)
+	}
+	if shares <= 0 {
+		return nil, fmt.Errorf("target share count is required for per-share values")
+	}
+	bridge := target.Bridge
+	if bridge == nil {
+		bridge = valuation.NewEquityBridge(&snap.BalanceSheet)
+	}
+
+	res := &Result{TargetYear: target.FiscalYear, TargetMetrics: snapshotMetrics(snap)}
+	for _, p := range peers {
+		pm, err := buildPeerMultiples(p, target.FiscalYear, target.FiscalYearEndMonth)
+		if err != nil {
+			return nil, err
+		}
+		res.Peers = append(res.Peers, pm)
+	}
+
+	multiples := cfg.Multiples
+	if len(multiples) == 0 {
+		multiples = AllMultiples
+	}
+
+	totalWeight := 0.0
+	for _, m := range multiples {
+		stats := summarize(m, res.Peers, cfg.OutlierFence)
+		res.Stats = append(res.Stats, stats)
+
+		metric := res.TargetMetrics.metric(m)
+		if stats.Count == 0 || metric <= 0 {
+			continue
+		}
+		weight := 1.0
+		if cfg.Weights != nil {
+			weight = cfg.Weights[m]
+		}
+
+		toPrice := func(mult float64) float64 {
+			value := mult * metric
+			if m.isEnterprise() {
+				value = bridge.EquityValue(value)
+			}
+			return value / shares
+		}
+		res.Implied = append(res.Implied, ImpliedValue{
+			Multiple: m,
+			Low:      toPrice(stats.Q1),
+			Mid:      toPrice(stats.Median),
+			High:     toPrice(stats.Q3),
+			Weight:   weight,
+		})
+		totalWeight += weight
+	}
+
+	if totalWeight > 0 {
+		for i := range res.Implied {
+			iv := &res.Implied[i]
+			iv.Weight /= totalWeight
+			res.CompositeLow += iv.Weight * iv.Low
+			res.CompositePrice += iv.Weight * iv.Mid
+			res.CompositeHigh += iv.Weight * iv.High
+		}
+	}
+	return res, nil
+}
+
+// FootballBar is the composite Q1 / median / Q3 price range of the analysis
+func (r *Result) FootballBar() valuation.FootballBar {
+	return valuation.FootballBar{
+		Model: valuation.FootballComps,
+		Low:   r.CompositeLow,
+		Base:  r.CompositePrice,
+		High:  r.CompositeHigh,
+	}
+}
+
+// Median returns the peer median of one multiple, e.g. to value a segment
+// in valuation.CalculateSOTP
+func (r *Result) Median(m Multiple) (float64, bool) {
+	for _, s := range r.Stats {
+		if s.Multiple == m && s.Count > 0 {
+			return s.Median, true
+		}
+	}
+	return 0, false
+}
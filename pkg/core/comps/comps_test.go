@@ -0,0 +1,166 @@
+package comps
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/synthesis"
+)
+
+func val(v float64) *edgar.FSAPValue {
+	return &edgar.FSAPValue{Value: &v}
+}
+
+// year builds a snapshot with revenue, EBIT = 20% of revenue, D&A = 5%, NI = 10%,
+// net debt = debt - cash and a share price
+func year(fy int, revenue, price, shares, debt, cash float64) *synthesis.YearlySnapshot {
+	return &synthesis.YearlySnapshot{
+		FiscalYear: fy,
+		IncomeStatement: edgar.IncomeStatement{
+			GrossProfitSection:   &edgar.GrossProfitSection{Revenues: val(revenue)},
+			OperatingCostSection: &edgar.OperatingCostSection{OperatingIncome: val(0.2 * revenue)},
+			NetIncomeSection:     &edgar.NetIncomeSection{NetIncomeToCommon: val(0.1 * revenue)},
+		},
+		CashFlowStatement: edgar.CashFlowStatement{
+			OperatingActivities: &edgar.CFOperatingSection{DepreciationAmortization: val(0.05 * revenue)},
+		},
+		BalanceSheet: edgar.BalanceSheet{
+			CurrentAssets:         edgar.CurrentAssets{CashAndEquivalents: val(cash)},
+			NoncurrentLiabilities: edgar.NoncurrentLiabilities{LongTermDebt: val(debt)},
+			Equity:                edgar.Equity{CommonStockAPIC: val(0.5 * revenue)},
+		},
+		SupplementalData: edgar.SupplementalData{
+			SharePriceYearEnd:        &price,
+			SharesOutstandingDiluted: val(shares),
+		},
+	}
+}
+
+func record(ticker string, snaps ...*synthesis.YearlySnapshot) *synthesis.GoldenRecord {
+	rec := &synthesis.GoldenRecord{Ticker: ticker, Timeline: make(map[int]*synthesis.YearlySnapshot)}
+	for _, s := range snaps {
+		rec.Timeline[s.FiscalYear] = s
+	}
+	return rec
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9*math.Max(1, math.Abs(b))
+}
+
+func TestCalendarize_BlendsAdjacentYear(t *testing.T) {
+	rec := record("JUN", year(2024, 1000, 10, 100, 0, 0), year(2025, 1200, 10, 100, 0, 0))
+
+	// Target December, peer June: Jan-Dec 2024 = half of FY2024 + half of FY2025
+	m, _, notes, err := calendarize(rec, 2024, 12, 6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !almostEqual(m.Revenue, 1100) || len(notes) != 0 {
+		t.Errorf("calendarized revenue = %.2f (notes %v), want 1100", m.Revenue, notes)
+	}
+
+	// Target June, peer December: Jul 2023 - Jun 2024 needs FY2023, which is missing
+	m, _, notes, err = calendarize(rec, 2024, 6, 12)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Revenue != 1000 || len(notes) != 1 {
+		t.Errorf("expected uncalendarized fallback with a note, got %.2f %v", m.Revenue, notes)
+	}
+}
+
+func TestSummarize_TrimsOutliersAndInterpolates(t *testing.T) {
+	var peers []PeerMultiples
+	for i, v := range []float64{8, 9, 10, 11, 12, 60, -5} {
+		peers = append(peers, PeerMultiples{
+			Ticker:    string(rune('A' + i)),
+			Multiples: map[Multiple]float64{EVEBITDA: v},
+		})
+	}
+
+	stats := summarize(EVEBITDA, peers, 0)
+	if stats.Count != 5 || len(stats.Excluded) != 2 {
+		t.Fatalf("expected 5 kept and 2 excluded, got %d kept, excluded %v", stats.Count, stats.Excluded)
+	}
+	if stats.Median != 10 || stats.Q1 != 9 || stats.Q3 != 11 || stats.Mean != 10 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	noTrim := summarize(EVEBITDA, peers, -1)
+	if noTrim.Count != 6 || noTrim.Median != 10.5 {
+		t.Errorf("expected interpolated median 10.5 over 6 peers, got %+v", noTrim)
+	}
+}
+
+func TestAnalyze_CompositePrice(t *testing.T) {
+	target := Target{
+		Record:     record("TGT", year(2024, 1000, 0, 100, 300, 100)),
+		FiscalYear: 2024,
+	}
+	// Peers priced at 10x EBITDA with no net debt: EV = market cap = 2500
+	var peers []Peer
+	for _, tk := range []string{"P1", "P2", "P3"} {
+		peers = append(peers, Peer{Ticker: tk, Record: record(tk, year(2024, 1000, 25, 100, 0, 0))})
+	}
+
+	res, err := Analyze(target, peers, Config{Multiples: []Multiple{EVEBITDA, PE}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Implied) != 2 {
+		t.Fatalf("expected 2 implied values, got %d", len(res.Implied))
+	}
+
+	// EV/EBITDA 10x on 250 = 2500, less net debt 200 => 23/share; P/E 25x on 100 => 25/share
+	if !almostEqual(res.Implied[0].Mid, 23) || !almostEqual(res.Implied[1].Mid, 25) {
+		t.Errorf("unexpected implied prices: %+v", res.Implied)
+	}
+	if !almostEqual(res.CompositePrice, 24) {
+		t.Errorf("composite = %.4f, want 24 with equal weights", res.CompositePrice)
+	}
+
+	res, err = Analyze(target, peers, Config{Multiples: []Multiple{EVEBITDA, PE}, Weights: map[Multiple]float64{EVEBITDA: 3, PE: 1}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !almostEqual(res.CompositePrice, 0.75*23+0.25*25) {
+		t.Errorf("weighted composite = %.4f", res.CompositePrice)
+	}
+}
+
+func TestPeerSetStore_Persists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "peer_sets.json")
+	store, err := NewPeerSetStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.Save(PeerSet{Name: "Mega Cap Tech", Tickers: []string{"MSFT", "GOOGL"}, FiscalYearEnds: map[string]int{"MSFT": 6}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := store.Save(PeerSet{Name: "empty"}); err == nil {
+		t.Error("expected error for a peer set without tickers")
+	}
+
+	reloaded, err := NewPeerSetStore(path)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	set, ok := reloaded.Get("mega cap tech")
+	if !ok || len(set.Tickers) != 2 || set.FiscalYearEnds["MSFT"] != 6 {
+		t.Fatalf("peer set not restored: %+v", set)
+	}
+
+	if _, err := set.Peers(map[string]*synthesis.GoldenRecord{"MSFT": record("MSFT")}); err == nil {
+		t.Error("expected error for a ticker without a golden record")
+	}
+
+	if err := reloaded.Delete("Mega Cap Tech"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if len(reloaded.List()) != 0 {
+		t.Error("expected empty store after delete")
+	}
+}
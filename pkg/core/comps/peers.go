@@ -0,0 +1,144 @@
+package comps
+
+import (
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/synthesis"
+	"agentic_valuation/pkg/core/valuation"
+	"fmt"
+)
+
+// snapshotMetrics reads the multiple denominators from one fiscal year
+func snapshotMetrics(snap *synthesis.YearlySnapshot) Metrics {
+	is := &snap.IncomeStatement
+	var m Metrics
+	if is.GrossProfitSection != nil {
+		m.Revenue = getVal(is.GrossProfitSection.Revenues)
+	}
+	if is.OperatingCostSection != nil {
+		m.EBIT = getVal(is.OperatingCostSection.OperatingIncome)
+	}
+	if is.NetIncomeSection != nil {
+		m.NetIncome = getVal(is.NetIncomeSection.NetIncomeToCommon)
+	}
+	m.EBITDA = m.EBIT
+	if ops := snap.CashFlowStatement.OperatingActivities; ops != nil {
+		m.EBITDA += getVal(ops.DepreciationAmortization)
+	}
+
+	eq := snap.BalanceSheet.Equity
+	m.BookValue = getVal(eq.CommonStockAPIC) + getVal(eq.RetainedEarningsDeficit) +
+		getVal(eq.TreasuryStock) + getVal(eq.AccumOtherComprehensiveIncome)
+	return m
+}
+
+// calendarize restates a peer's flow metrics for the 12 months ending at the
+// target's fiscal year end, blending the two overlapping peer fiscal years.
+// Balance sheet items (book value) are taken from the latest year in the window.
+//
+// Example: target FYE December 2024, peer FYE June. The window Jan-Dec 2024
+// is 6/12 of peer FY2024 (Jul 23 - Jun 24) and 6/12 of peer FY2025.
+func calendarize(rec *synthesis.GoldenRecord, targetYear, targetMonth, peerMonth int) (Metrics, *synthesis.YearlySnapshot, []string, error) {
+	targetMonth, peerMonth = normalizeMonth(targetMonth), normalizeMonth(peerMonth)
+
+	base := rec.Timeline[targetYear]
+	if base == nil {
+		return Metrics{}, nil, nil, fmt.Errorf("no fiscal year %d in golden record", targetYear)
+	}
+
+	// Months of the window that fall in the adjacent peer fiscal year
+	offset := targetMonth - peerMonth
+	adjacentYear := targetYear + 1
+	if offset < 0 {
+		offset = -offset
+		adjacentYear = targetYear - 1
+	}
+	if offset == 0 {
+		return snapshotMetrics(base), base, nil, nil
+	}
+
+	adjacent := rec.Timeline[adjacentYear]
+	if adjacent == nil {
+		note := fmt.Sprintf("FY%d not available; using FY%d without calendarization (%d-month offset)", adjacentYear, targetYear, offset)
+		return snapshotMetrics(base), base, []string{note}, nil
+	}
+
+	w := float64(offset) / 12
+	a, b := snapshotMetrics(base), snapshotMetrics(adjacent)
+	blended := Metrics{
+		Revenue:   (1-w)*a.Revenue + w*b.Revenue,
+		EBITDA:    (1-w)*a.EBITDA + w*b.EBITDA,
+		EBIT:      (1-w)*a.EBIT + w*b.EBIT,
+		NetIncome: (1-w)*a.NetIncome + w*b.NetIncome,
+	}
+
+	latest := base
+	if adjacentYear > targetYear {
+		latest = adjacent
+	}
+	blended.BookValue = snapshotMetrics(latest).BookValue
+	return blended, latest, nil, nil
+}
+
+// buildPeerMultiples computes market value, EV and multiples for one peer
+func buildPeerMultiples(p Peer, targetYear, targetMonth int) (PeerMultiples, error) {
+	pm := PeerMultiples{Ticker: p.Ticker, Multiples: make(map[Multiple]float64)}
+	if p.Record == nil {
+		return pm, fmt.Errorf("peer %s has no golden record", p.Ticker)
+	}
+
+	metrics, snap, notes, err := calendarize(p.Record, targetYear, targetMonth, p.FiscalYearEndMonth)
+	if err != nil {
+		return pm, fmt.Errorf("peer %s: %w", p.Ticker, err)
+	}
+	pm.Metrics, pm.Notes = metrics, notes
+
+	price := p.SharePrice
+	if price == 0 && snap.SupplementalData.SharePriceYearEnd != nil {
+		price = *snap.SupplementalData.SharePriceYearEnd
+	}
+	shares := p.SharesOutstanding
+	if shares == 0 {
+		shares = recordShares(snap)
+	}
+	if price <= 0 || shares <= 0 {
+		return pm, fmt.Errorf("peer %s: share price and share count are required for market value", p.Ticker)
+	}
+
+	pm.MarketCap = price * shares
+	pm.EnterpriseValue = pm.MarketCap + valuation.NewEquityBridge(&snap.BalanceSheet).Total()
+
+	for _, m := range AllMultiples {
+		denom := metrics.metric(m)
+		if denom == 0 {
+			continue
+		}
+		numer := pm.MarketCap
+		if m.isEnterprise() {
+			numer = pm.EnterpriseValue
+		}
+		pm.Multiples[m] = numer / denom
+	}
+	return pm, nil
+}
+
+// recordShares prefers diluted over basic shares
+func recordShares(snap *synthesis.YearlySnapshot) float64 {
+	if v := getVal(snap.SupplementalData.SharesOutstandingDiluted); v > 0 {
+		return v
+	}
+	return getVal(snap.SupplementalData.SharesOutstandingBasic)
+}
+
+func normalizeMonth(m int) int {
+	if m < 1 || m > 12 {
+		return 12
+	}
+	return m
+}
+
+func getVal(v *edgar.FSAPValue) float64 {
+	if v != nil && v.Value != nil {
+		return *v.Value
+	}
+	return 0
+}
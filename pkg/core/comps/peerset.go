@@ -0,0 +1,136 @@
+package comps
+
+import (
+	"agentic_valuation/pkg/core/synthesis"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PeerSet is a named, reusable list of comparables
+type PeerSet struct {
+	Name           string         `json:"name"`
+	Description    string         `json:"description,omitempty"`
+	Tickers        []string       `json:"tickers"`
+	FiscalYearEnds map[string]int `json:"fiscal_year_end_months,omitempty"` // Ticker -> FYE month (default December)
+	Config         Config         `json:"config"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// Peers resolves the tickers against loaded golden records
+func (s *PeerSet) Peers(records map[string]*synthesis.GoldenRecord) ([]Peer, error) {
+	peers := make([]Peer, 0, len(s.Tickers))
+	for _, t := range s.Tickers {
+		rec := records[t]
+		if rec == nil {
+			return nil, fmt.Errorf("peer set '%s': no golden record for %s", s.Name, t)
+		}
+		peers = append(peers, Peer{Ticker: t, Record: rec, FiscalYearEndMonth: s.FiscalYearEnds[t]})
+	}
+	return peers, nil
+}
+
+// PeerSetStore keeps peer sets in memory and persists them as JSON
+type PeerSetStore struct {
+	mu   sync.RWMutex
+	sets map[string]*PeerSet // Lower-cased name -> set
+	path string
+}
+
+// NewPeerSetStore loads saved peer sets from path (an empty path keeps them in memory only)
+func NewPeerSetStore(path string) (*PeerSetStore, error) {
+	s := &PeerSetStore{sets: make(map[string]*PeerSet), path: path}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil // No file yet, ok
+		}
+		return nil, fmt.Errorf("failed to read peer sets: %w", err)
+	}
+
+	var sets []*PeerSet
+	if err := json.Unmarshal(data, &sets); err != nil {
+		return nil, fmt.Errorf("failed to parse peer sets: %w", err)
+	}
+	for _, set := range sets {
+		s.sets[strings.ToLower(set.Name)] = set
+	}
+	return s, nil
+}
+
+// Save adds or replaces a peer set and persists the store
+func (s *PeerSetStore) Save(set PeerSet) error {
+	set.Name = strings.TrimSpace(set.Name)
+	if set.Name == "" {
+		return fmt.Errorf("peer set name is required")
+	}
+	if len(set.Tickers) == 0 {
+		return fmt.Errorf("peer set '%s' has no tickers", set.Name)
+	}
+	set.UpdatedAt = time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sets[strings.ToLower(set.Name)] = &set
+	return s.persist()
+}
+
+// Get returns a peer set by name (case-insensitive)
+func (s *PeerSetStore) Get(name string) (*PeerSet, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	set, ok := s.sets[strings.ToLower(strings.TrimSpace(name))]
+	return set, ok
+}
+
+// List returns all peer sets sorted by name
+func (s *PeerSetStore) List() []*PeerSet {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sorted()
+}
+
+// Delete removes a peer set and persists the store
+func (s *PeerSetStore) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := strings.ToLower(strings.TrimSpace(name))
+	if _, ok := s.sets[key]; !ok {
+		return fmt.Errorf("peer set '%s' not found", name)
+	}
+	delete(s.sets, key)
+	return s.persist()
+}
+
+func (s *PeerSetStore) sorted() []*PeerSet {
+	out := make([]*PeerSet, 0, len(s.sets))
+	for _, set := range s.sets {
+		out = append(out, set)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// persist writes the store to disk; callers hold the lock
+func (s *PeerSetStore) persist() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s.sorted(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal peer sets: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create peer set dir: %w", err)
+	}
+	return os.WriteFile(s.path, data, 0644)
+}
@@ -0,0 +1,81 @@
+package comps
+
+import (
+	"agentic_valuation/pkg/core/valuation"
+	"sort"
+)
+
+const defaultOutlierFence = 1.5
+
+// minPeersForTrimming: with fewer peers the quartiles are too unstable to fence on
+const minPeersForTrimming = 4
+
+type tickerValue struct {
+	ticker string
+	value  float64
+}
+
+// summarize computes interpolated statistics for one multiple.
+// Non-positive multiples (negative earnings) are excluded, then values outside
+// [Q1 - k*IQR, Q3 + k*IQR] are trimmed.
+func summarize(mult Multiple, peers []PeerMultiples, fence float64) MultipleStats {
+	stats := MultipleStats{Multiple: mult}
+
+	var values []tickerValue
+	for _, p := range peers {
+		v, ok := p.Multiples[mult]
+		if !ok {
+			continue
+		}
+		if v <= 0 {
+			stats.Excluded = append(stats.Excluded, p.Ticker)
+			continue
+		}
+		values = append(values, tickerValue{p.Ticker, v})
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].value < values[j].value })
+
+	if fence == 0 {
+		fence = defaultOutlierFence
+	}
+	if fence > 0 && len(values) >= minPeersForTrimming {
+		sorted := floats(values)
+		q1, q3 := valuation.Quantile(sorted, 0.25), valuation.Quantile(sorted, 0.75)
+		lo, hi := q1-fence*(q3-q1), q3+fence*(q3-q1)
+		kept := values[:0]
+		for _, tv := range values {
+			if tv.value < lo || tv.value > hi {
+				stats.Excluded = append(stats.Excluded, tv.ticker)
+				continue
+			}
+			kept = append(kept, tv)
+		}
+		values = kept
+	}
+
+	sorted := floats(values)
+	stats.Count = len(sorted)
+	if stats.Count == 0 {
+		return stats
+	}
+
+	sum := 0.0
+	for _, v := range sorted {
+		sum += v
+	}
+	stats.Mean = sum / float64(stats.Count)
+	stats.Median = valuation.Quantile(sorted, 0.5)
+	stats.Q1 = valuation.Quantile(sorted, 0.25)
+	stats.Q3 = valuation.Quantile(sorted, 0.75)
+	stats.Min = sorted[0]
+	stats.Max = sorted[len(sorted)-1]
+	return stats
+}
+
+func floats(values []tickerValue) []float64 {
+	out := make([]float64, len(values))
+	for i, tv := range values {
+		out[i] = tv.value
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+// Package comps implements trading comparables: peer multiples built from
+// synthesis.GoldenRecord time-series, calendarized to the target's fiscal year,
+// summarized with interpolated statistics and outlier trimming, and turned into
+// a weighted composite share price. Peer sets can be saved and reused by name.
+package comps
+
+import (
+	"agentic_valuation/pkg/core/synthesis"
+	"agentic_valuation/pkg/core/valuation"
+)
+
+// Multiple identifies a valuation multiple
+type Multiple string
+
+const (
+	EVRevenue Multiple = "ev_revenue"
+	EVEBITDA  Multiple = "ev_ebitda"
+	EVEBIT    Multiple = "ev_ebit"
+	PE        Multiple = "pe"
+	PB        Multiple = "pb"
+)
+
+// AllMultiples is the default set, in report order
+var AllMultiples = []Multiple{EVRevenue, EVEBITDA, EVEBIT, PE, PB}
+
+// isEnterprise reports whether the multiple is EV-based (needs the equity bridge)
+func (m Multiple) isEnterprise() bool {
+	return m == EVRevenue || m == EVEBITDA || m == EVEBIT
+}
+
+// Metrics are the denominators of the multiples
+type Metrics struct {
+	Revenue   float64 `json:"revenue"`
+	EBITDA    float64 `json:"ebitda"`
+	EBIT      float64 `json:"ebit"`
+	NetIncome float64 `json:"net_income"`
+	BookValue float64 `json:"book_value"`
+}
+
+// metric returns the denominator for a multiple
+func (m Metrics) metric(mult Multiple) float64 {
+	switch mult {
+	case EVRevenue:
+		return m.Revenue
+	case EVEBITDA:
+		return m.EBITDA
+	case EVEBIT:
+		return m.EBIT
+	case PE:
+		return m.NetIncome
+	case PB:
+		return m.BookValue
+	}
+	return 0
+}
+
+// Target is the company being valued
+type Target struct {
+	Record             *synthesis.GoldenRecord
+	FiscalYear         int                     // Year the peers are calendarized to
+	FiscalYearEndMonth int                     // 1-12 (0 = December)
+	SharesOutstanding  float64                 // 0 = diluted shares from the record
+	Bridge             *valuation.EquityBridge // Optional: defaults to the bridge from the record's balance sheet
+}
+
+// Peer is one comparable company.
+// Share counts and prices must be in the same units as the statements (e.g. millions and $).
+type Peer struct {
+	Ticker             string
+	Record             *synthesis.GoldenRecord
+	FiscalYearEndMonth int     // 1-12 (0 = December)
+	SharePrice         float64 // 0 = SharePriceYearEnd from the record
+	SharesOutstanding  float64 // 0 = diluted shares from the record
+}
+
+// Config controls the analysis
+type Config struct {
+	Multiples    []Multiple           `json:"multiples,omitempty"`     // Default AllMultiples
+	Weights      map[Multiple]float64 `json:"weights,omitempty"`       // Composite weights (default equal)
+	OutlierFence float64              `json:"outlier_fence,omitempty"` // Tukey fence in IQRs (0 = 1.5, negative = no trimming)
+}
+
+// PeerMultiples are one peer's calendarized metrics and multiples
+type PeerMultiples struct {
+	Ticker          string               `json:"ticker"`
+	MarketCap       float64              `json:"market_cap"`
+	EnterpriseValue float64              `json:"enterprise_value"`
+	Metrics         Metrics              `json:"metrics"`
+	Multiples       map[Multiple]float64 `json:"multiples"`
+	Notes           []string             `json:"notes,omitempty"` // Calendarization fallbacks, missing data
+}
+
+// MultipleStats summarizes one multiple across the peer set
+type MultipleStats struct {
+	Multiple Multiple `json:"multiple"`
+	Count    int      `json:"count"` // Peers used after exclusions
+	Mean     float64  `json:"mean"`
+	Median   float64  `json:"median"`
+	Q1       float64  `json:"q1"`
+	Q3       float64  `json:"q3"`
+	Min      float64  `json:"min"`
+	Max      float64  `json:"max"`
+	Excluded []string `json:"excluded,omitempty"` // Tickers dropped as outliers or non-positive
+}
+
+// ImpliedValue is the target share price implied by one multiple
+type ImpliedValue struct {
+	Multiple Multiple `json:"multiple"`
+	Low      float64  `json:"low"`  // At Q1
+	Mid      float64  `json:"mid"`  // At median
+	High     float64  `json:"high"` // At Q3
+	Weight   float64  `json:"weight"`
+}
+
+// Result is the output of a comps run
+type Result struct {
+	TargetYear     int             `json:"target_year"`
+	TargetMetrics  Metrics         `json:"target_metrics"`
+	Peers          []PeerMultiples `json:"peers"`
+	Stats          []MultipleStats `json:"stats"`
+	Implied        []ImpliedValue  `json:"implied"`
+	CompositeLow   float64         `json:"composite_low"`
+	CompositePrice float64         `json:"composite_price"`
+	CompositeHigh  float64         `json:"composite_high"`
+}
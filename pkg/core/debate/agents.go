@@ -263,6 +263,8 @@ func (a *SkepticAgent) Generate(ctx context.Context, shared *SharedContext) (Deb
 	prompt := fmt.Sprintf("Review the debate so far for %s:\n\n%s\n\n"+
 		"Identify weaknesses, over-optimism, or missing risks. Challenge specific points made by others.",
 		shared.Company, contextSummary.String())
+	prompt += impliedExpectationsBrief(shared,
+		"Cite these figures: argue whether the price already bakes in more than the business can deliver.")
 
 	// Use Agent Manager to execute prompt with configured provider
 	content, err := a.agentManager.ExecutePrompt("skeptic", prompt, a.systemPrompt, nil)
@@ -298,6 +300,8 @@ func (a *OptimistAgent) Generate(ctx context.Context, shared *SharedContext) (De
 	prompt := fmt.Sprintf("Review the debate so far for %s:\n\n%s\n\n"+
 		"Highlight growth opportunities, defend against skeptic criticisms, and focus on upside potential.",
 		shared.Company, contextSummary.String())
+	prompt += impliedExpectationsBrief(shared,
+		"Cite these figures: argue where the business can beat what the price implies.")
 
 	content, err := a.agentManager.ExecutePrompt("optimist", prompt, a.systemPrompt, nil)
 	if err != nil {
@@ -501,3 +505,18 @@ func (a *SynthesizerAgent) Generate(ctx context.Context, shared *SharedContext)
 		Timestamp:  time.Now(),
 	}, nil
 }
+
+// impliedExpectationsBrief lists the reverse DCF figures for the Skeptic and Optimist prompts
+func impliedExpectationsBrief(shared *SharedContext, instruction string) string {
+	if len(shared.ImpliedExpectations) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("\n\nMarket-implied expectations (reverse DCF):\n")
+	for _, ie := range shared.ImpliedExpectations {
+		sb.WriteString(fmt.Sprintf("- At $%.2f the price implies %s = %.2f%% (baseline %.2f%%, baseline DCF value $%.2f)\n",
+			ie.MarketPrice, ie.Driver, ie.Implied*100, ie.Base*100, ie.BasePrice))
+	}
+	sb.WriteString(instruction)
+	return sb.String()
+}
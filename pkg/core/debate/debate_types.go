@@ -56,8 +56,33 @@ type SharedContext struct {
 
 	// Baseline Assumptions (Quantitative Pre-Debate)
 	BaselineAssumptions map[string]float64 `json:"baseline_assumptions,omitempty"`
+
+	// Market-implied figures from a reverse DCF (what the current price bakes in)
+	ImpliedExpectations []ImpliedExpectation `json:"implied_expectations,omitempty"`
+
+	// Value drivers ranked by their swing in the DCF value (Markdown table)
+	DriverSensitivity string `json:"driver_sensitivity,omitempty"`
+}
+
+// ImpliedExpectation is one driver back-solved from the market price
+// (filled from valuation.ReverseDCFResult by the ExpectationSolver)
+type ImpliedExpectation struct {
+	Driver      string  `json:"driver"`       // e.g. "revenue_growth", "wacc"
+	Implied     float64 `json:"implied"`      // Driver value the price implies (decimal)
+	Base        float64 `json:"base"`         // Driver value in the baseline assumptions
+	MarketPrice float64 `json:"market_price"` // Price that was reconciled
+	BasePrice   float64 `json:"base_price"`   // DCF value at the baseline assumptions
 }
 
+// ExpectationSolver runs a reverse DCF over the material pool at the Quant baselines.
+// It is injected because the valuation package imports debate (valuation.DebateExpectations).
+type ExpectationSolver func(pool *MaterialPool, baselines map[string]float64) ([]ImpliedExpectation, error)
+
+// SensitivityRanker ranks the value drivers of the material pool at the Quant baselines
+// and renders them as a Markdown table. It is injected for the same reason as
+// ExpectationSolver (sensitivity.DebateTornado).
+type SensitivityRanker func(pool *MaterialPool, baselines map[string]float64) (string, error)
+
 // MaterialPool aggregates all quantitative and qualitative intelligence
 // effectively replacing the fragmented fields above
 type MaterialPool struct {
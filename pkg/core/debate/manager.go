@@ -15,6 +15,8 @@ type DebateManager struct {
 	activeDebates map[string]*DebateOrchestrator
 	repo          *DebateRepo
 	agentManager  *agent.Manager
+	expectations  ExpectationSolver
+	sensitivity   SensitivityRanker
 	mu            sync.RWMutex
 }
 
@@ -44,6 +46,20 @@ func (m *DebateManager) SetAgentManager(mgr *agent.Manager) {
 	m.agentManager = mgr
 }
 
+// SetExpectationSolver injects the reverse DCF used to seed implied expectations
+func (m *DebateManager) SetExpectationSolver(solver ExpectationSolver) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expectations = solver
+}
+
+// SetSensitivityRanker injects the tornado used to rank value drivers in Phase 0
+func (m *DebateManager) SetSensitivityRanker(ranker SensitivityRanker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sensitivity = ranker
+}
+
 // StartDebate initializes a new debate and runs it in a background goroutine
 func (m *DebateManager) StartDebate(ticker, company, fiscalYear string, isSimulation bool, mode DebateMode) (string, error) {
 	m.mu.Lock()
@@ -51,6 +67,8 @@ func (m *DebateManager) StartDebate(ticker, company, fiscalYear string, isSimula
 
 	id := uuid.New().String()
 	orchestrator := NewOrchestrator(id, ticker, company, fiscalYear, isSimulation, mode, m.agentManager, m.repo)
+	orchestrator.Expectations = m.expectations
+	orchestrator.Sensitivity = m.sensitivity
 	m.activeDebates[id] = orchestrator
 
 	// Run debate in background
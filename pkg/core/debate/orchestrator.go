@@ -29,6 +29,8 @@ type DebateOrchestrator struct {
 
 	AgentManager *agent.Manager
 	Repo         *DebateRepo
+	Expectations ExpectationSolver // Optional: fills SharedContext.ImpliedExpectations in Phase 0
+	Sensitivity  SensitivityRanker // Optional: fills SharedContext.DriverSensitivity in Phase 0
 
 	// Interactive mode support
 	questionChan chan HumanQuestion // Channel for human questions
@@ -157,6 +159,24 @@ func (o *DebateOrchestrator) Run(ctx context.Context) {
 		baselines := quantAgent.GenerateBaselineAssumptions(o.SharedContext.MaterialPool)
 		o.SharedContext.BaselineAssumptions = baselines
 
+		// Market-implied drivers for the Skeptic and Optimist
+		if o.Expectations != nil {
+			implied, err := o.Expectations(o.SharedContext.MaterialPool, baselines)
+			if err != nil {
+				fmt.Printf("Reverse DCF Error: %v\n", err)
+			}
+			o.SharedContext.ImpliedExpectations = implied
+		}
+
+		// Value drivers ranked for the Quant findings
+		if o.Sensitivity != nil {
+			table, err := o.Sensitivity(o.SharedContext.MaterialPool, baselines)
+			if err != nil {
+				fmt.Printf("Sensitivity Error: %v\n", err)
+			}
+			o.SharedContext.DriverSensitivity = table
+		}
+
 		// 2. Broadcast Quant Agent Findings
 		msg, err := quantAgent.Generate(ctx, o.SharedContext)
 		if err == nil {
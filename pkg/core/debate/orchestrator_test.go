@@ -2,6 +2,7 @@ package debate
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -95,3 +96,55 @@ func TestDebateOrchestrator_Run_Simulation(t *testing.T) {
 		t.Error("FinalReport should be nil in this truncated run")
 	}
 }
+
+func TestDebateOrchestrator_Run_ImpliedExpectations(t *testing.T) {
+	orch := NewOrchestrator("sim-id", "TSLA", "Tesla Inc.", "2024", true, ModeAutomatic, &agent.Manager{}, &DebateRepo{})
+	orch.SharedContext.MaterialPool = &MaterialPool{
+		FinancialHistory: []*edgar.FSAPDataResponse{
+			{Company: "Tesla Inc.", HistoricalData: map[int]edgar.YearData{2023: {}}},
+		},
+	}
+	var gotBaselines map[string]float64
+	orch.Expectations = func(pool *MaterialPool, baselines map[string]float64) ([]ImpliedExpectation, error) {
+		gotBaselines = baselines
+		return []ImpliedExpectation{{Driver: "revenue_growth", Implied: 0.12, Base: 0.05, MarketPrice: 250, BasePrice: 180}}, nil
+	}
+
+	orch.Run(context.Background())
+
+	if len(gotBaselines) == 0 {
+		t.Error("solver should receive the Quant baselines")
+	}
+	if len(orch.SharedContext.ImpliedExpectations) == 0 {
+		t.Fatal("implied expectations should be set in Phase 0")
+	}
+	if brief := impliedExpectationsBrief(orch.SharedContext, ""); !strings.Contains(brief, "revenue_growth = 12.00%") {
+		t.Errorf("agents should see the implied figure, got %q", brief)
+	}
+}
+
+func TestDebateOrchestrator_Run_DriverSensitivity(t *testing.T) {
+	orch := NewOrchestrator("sim-id", "TSLA", "Tesla Inc.", "2024", true, ModeAutomatic, &agent.Manager{}, &DebateRepo{})
+	orch.SharedContext.MaterialPool = &MaterialPool{
+		FinancialHistory: []*edgar.FSAPDataResponse{
+			{Company: "Tesla Inc.", HistoricalData: map[int]edgar.YearData{2023: {}}},
+		},
+	}
+	table := "| revenue_growth | 0.0450 | 0.0550 | 170.00 | 190.00 | 20.00 |"
+	orch.Sensitivity = func(pool *MaterialPool, baselines map[string]float64) (string, error) {
+		return table, nil
+	}
+
+	orch.Run(context.Background())
+
+	if orch.SharedContext.DriverSensitivity != table {
+		t.Fatalf("driver sensitivity should be set in Phase 0, got %q", orch.SharedContext.DriverSensitivity)
+	}
+	found := false
+	for _, msg := range orch.History {
+		found = found || (msg.AgentRole == AgentRole("quant") && strings.Contains(msg.Content, table))
+	}
+	if !found {
+		t.Error("the Quant findings should carry the driver table")
+	}
+}
@@ -90,6 +90,9 @@ func (a *RoleQuant) Generate(ctx context.Context, shared *SharedContext) (Debate
 		defaults.StockBasedCompPercent*100,
 		defaults.DebtInterestRate*100,
 	)
+	if shared.DriverSensitivity != "" {
+		content += "\n\nThe value drivers that move the DCF most, each flexed on its own:\n\n" + shared.DriverSensitivity
+	}
 
 	return DebateMessage{
 		AgentRole: a.Role(),
@@ -220,7 +220,9 @@ Be concise. Extract numerical values, dates, and key terms.`
 
 Return JSON with relevant fields. Examples:
 - For SEGMENT: {"segments": [{"name": "...", "revenue": ..., "operating_income": ...}]}
-- For DEBT: {"debt_instruments": [{"type": "...", "principal": ..., "maturity": "..."}]}
+- For DEBT: {"debt_instruments": [{"type": "...", "principal": ..., "interest_rate": ..., "floating": true/false, "spread": ... (floating only), "rate_unit": "percent" | "decimal" | "bps" (unit of interest_rate and spread), "maturity": "...", "conversion_price": ... (convertibles only)}]}
+- For INCOME_TAXES: {"nol_carryforwards": [{"jurisdiction": "federal/state/foreign", "amount": ... (gross loss, not tax-effected), "expiration": "2030" or "indefinite"}]}
+- For STOCK_COMPENSATION: {"options_outstanding": ..., "weighted_average_exercise_price": ..., "rsus_outstanding": ...}
 - For LEASES: {"operating_leases": ..., "finance_leases": ..., "total_lease_liability": ...}
 - For others: Extract key numerical values and dates.
 
@@ -47,10 +47,11 @@ func NewParser() *Parser {
 
 // SubmissionsResponse from SEC API
 type SubmissionsResponse struct {
-	CIK     string   `json:"cik"`
-	Name    string   `json:"name"`
-	Tickers []string `json:"tickers"`
-	Filings Filings  `json:"filings"`
+	CIK           string   `json:"cik"`
+	Name          string   `json:"name"`
+	Tickers       []string `json:"tickers"`
+	FiscalYearEnd string   `json:"fiscalYearEnd"` // "MMDD"
+	Filings       Filings  `json:"filings"`
 }
 
 // Filings contains filing information
@@ -64,6 +65,15 @@ type RecentFilings struct {
 	FilingDate      []string `json:"filingDate"`
 	Form            []string `json:"form"`
 	PrimaryDocument []string `json:"primaryDocument"`
+	ReportDate      []string `json:"reportDate"`
+}
+
+// reportDate returns the period-of-report for entry i (empty if SEC omitted it)
+func (r RecentFilings) reportDate(i int) string {
+	if i < len(r.ReportDate) {
+		return r.ReportDate[i]
+	}
+	return ""
 }
 
 // LookupCIK resolves a ticker symbol to a CIK using SEC's company_tickers.json
@@ -215,6 +225,8 @@ func (p *Parser) GetFilingMetadataByYear(cik string, form string, fiscalYear int
 				Form:            f, // Store actual form (10-K or 10-KA)
 				FiscalYear:      fileFiscalYear,
 				FiscalPeriod:    determineFiscalPeriod(form),
+				FiscalYearEnd:   resp.FiscalYearEnd,
+				ReportDate:      resp.Filings.Recent.reportDate(i),
 				PrimaryDocument: primaryDoc,
 				FilingURL:       filingURL,
 				ParsedAt:        time.Now(),
@@ -276,6 +288,8 @@ func (p *Parser) GetFilingMetadataByAccession(cik string, accessionNumber string
 				IsAmended:       strings.Contains(form, "/A") || strings.HasSuffix(form, "A"),
 				FiscalYear:      fiscalYear,
 				FiscalPeriod:    determineFiscalPeriod(form),
+				FiscalYearEnd:   resp.FiscalYearEnd,
+				ReportDate:      resp.Filings.Recent.reportDate(i),
 				PrimaryDocument: primaryDoc,
 				FilingURL:       filingURL,
 				ParsedAt:        time.Now(),
@@ -45,9 +45,11 @@ type StrategyAnalysis struct {
 
 // CapitalAnalysis - Output from Capital Allocation Agent
 type CapitalAnalysis struct {
-	ShareBuybackProgram string `json:"share_buyback_program"`
-	DividendPolicy      string `json:"dividend_policy"`
-	MAStrategy          string `json:"ma_strategy"`
+	ShareBuybackProgram string  `json:"share_buyback_program"`
+	DividendPolicy      string  `json:"dividend_policy"`
+	MAStrategy          string  `json:"ma_strategy"`
+	BuybackRemaining    float64 `json:"buyback_remaining_authorization"` // Millions (0 = not stated)
+	BuybackPeriodYears  float64 `json:"buyback_period_years"`            // Years the remaining authorization covers (0 = not stated)
 }
 
 // SegmentAnalysis - Output from Segment Agent
@@ -165,7 +167,9 @@ Return JSON:
 {
   "share_buyback_program": "string summary",
   "dividend_policy": "string summary",
-  "ma_strategy": "string summary"
+  "ma_strategy": "string summary",
+  "buyback_remaining_authorization": 0 (remaining capacity in millions, 0 if not stated),
+  "buyback_period_years": 0 (years the program runs, 0 if not stated)
 }`, mdaText)
 
 	resp, err := a.provider.Generate(ctx, systemPrompt, userPrompt)
@@ -23,7 +23,9 @@ type FilingMetadata struct {
 	Form            string    `json:"form"`       // "10-K", "10-Q", "8-K"
 	IsAmended       bool      `json:"is_amended"` // True if this is a 10-K/A amendment
 	FiscalYear      int       `json:"fiscal_year"`
-	FiscalPeriod    string    `json:"fiscal_period"` // "FY", "Q1", "Q2", "Q3"
+	FiscalPeriod    string    `json:"fiscal_period"`             // "FY", "Q1", "Q2", "Q3"
+	FiscalYearEnd   string    `json:"fiscal_year_end,omitempty"` // "MMDD" from SEC submissions, e.g. "0928"
+	ReportDate      string    `json:"report_date,omitempty"`     // Period end of the filing, e.g. "2024-09-28"
 	PrimaryDocument string    `json:"primary_document"`
 	FilingURL       string    `json:"filing_url"`
 	ParsedAt        time.Time `json:"parsed_at"`
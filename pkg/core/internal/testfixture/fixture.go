@@ -0,0 +1,75 @@
+// Package testfixture holds the T-0 company the projection and valuation tests
+// build on, so every package projects from the same balance sheet.
+package testfixture
+
+import (
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/projection"
+	"agentic_valuation/pkg/core/valuation"
+)
+
+// Value wraps a number as an FSAP line item
+func Value(v float64) *edgar.FSAPValue {
+	return &edgar.FSAPValue{Value: &v}
+}
+
+// History is FY2024: 1,000 of revenue, 10 of interest, 200 of long-term debt and
+// 500 of book equity, with working capital at 36.5 days of revenue.
+func History() projection.History {
+	return projection.History{
+		FiscalYear: 2024,
+		IncomeStatement: &edgar.IncomeStatement{
+			GrossProfitSection:  &edgar.GrossProfitSection{Revenues: Value(1000)},
+			NonOperatingSection: &edgar.NonOperatingSection{InterestExpense: Value(-10)},
+		},
+		BalanceSheet: &edgar.BalanceSheet{
+			CurrentAssets: edgar.CurrentAssets{
+				CashAndEquivalents:    Value(100),
+				AccountsReceivableNet: Value(100),
+				Inventories:           Value(100),
+			},
+			NoncurrentAssets: edgar.NoncurrentAssets{
+				PPEAtCost:               Value(1000),
+				AccumulatedDepreciation: Value(-500),
+				PPENet:                  Value(500),
+			},
+			CurrentLiabilities:    edgar.CurrentLiabilities{AccountsPayable: Value(100)},
+			NoncurrentLiabilities: edgar.NoncurrentLiabilities{LongTermDebt: Value(200)},
+			Equity: edgar.Equity{
+				CommonStockAPIC:         Value(100),
+				RetainedEarningsDeficit: Value(400),
+			},
+		},
+	}
+}
+
+// Assumptions is the steady-state template projected from History
+func Assumptions() projection.ProjectionAssumptions {
+	return projection.ProjectionAssumptions{
+		RevenueGrowth:       0.05,
+		COGSPercent:         0.55,
+		SGAPercent:          0.15,
+		TaxRate:             0.25,
+		DSO:                 36.5,
+		DSI:                 36.5,
+		DPO:                 36.5,
+		CapexPercent:        0.04,
+		DepreciationPercent: 0.05,
+		DividendPayoutRatio: 0.4,
+		SharesOutstanding:   100,
+	}
+}
+
+// Valuation is the valuation input on History's 500 of book equity: 100 shares,
+// 100 of net debt, WACC 9% and Ke 10%. Callers set the projections.
+func Valuation() valuation.MasterValuationInput {
+	return valuation.MasterValuationInput{
+		CurrentBookValue:  500,
+		SharesOutstanding: 100,
+		NetDebt:           100,
+		WACC:              0.09,
+		CostOfEquity:      0.10,
+		TerminalGrowth:    0.02,
+		TaxRate:           0.25,
+	}
+}
@@ -85,6 +85,9 @@ func (p *PipelineOrchestrator) RunForCompany(ctx context.Context, ticker string,
 		for _, period := range existingRecord.Timeline {
 			existingAccessions[period.SourceFiling.AccessionNumber] = true
 		}
+		for _, quarter := range existingRecord.Quarters {
+			existingAccessions[quarter.SourceFiling.AccessionNumber] = true
+		}
 	} else {
 		// Log the error if it's not just "not found", otherwise just proceed
 		fmt.Printf("No existing analysis found for %s (or scan error). Performing full extraction.\n", ticker)
@@ -132,6 +135,8 @@ func (p *PipelineOrchestrator) RunForCompany(ctx context.Context, ticker string,
 			FilingMetadata: synthesis.SourceMetadata{
 				AccessionNumber: filing.AccessionNumber,
 				FilingDate:      filing.FilingDate,
+				Form:            filing.Form,
+				FiscalPeriod:    filing.FiscalPeriod,
 			},
 			FiscalYear: filing.FiscalYear,
 			Data:       data,
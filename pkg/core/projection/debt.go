@@ -0,0 +1,386 @@
+package projection
+
+import (
+	"agentic_valuation/pkg/core/edgar"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultRefinanceTenor is the term (years) of a refinancing when the tranche does not set one
+const DefaultRefinanceTenor = 5
+
+// DebtTranche is one borrowing of the term debt schedule
+type DebtTranche struct {
+	Name         string
+	Principal    float64 // Outstanding at the end of the base year
+	Rate         float64 // Fixed coupon (decimal)
+	Floating     bool    // Charged at RateCurve + Spread instead of Rate
+	Spread       float64 // Margin over the curve for floating tranches
+	MaturityYear int     // Fiscal year the remaining principal is repaid (0 = no contractual maturity)
+	Amortization float64 // Scheduled principal repaid each year before maturity
+
+	// Refinancing: at maturity the principal is repaid and reborrowed instead of retired
+	Refinance      bool
+	RefinanceRate  float64 // Fixed rate of the new borrowing (0 = roll on the existing terms)
+	RefinanceTenor int     // Years to the new maturity (0 = DefaultRefinanceTenor)
+}
+
+// RateCurve is the base rate (decimal) by fiscal year for floating tranches.
+// Years between points hold the earlier point; years outside the curve hold the nearest end.
+type RateCurve map[int]float64
+
+// DebtSchedule rolls term debt tranche by tranche from the base year.
+// Tranche principals should sum to LongTermDebt + CurrentMaturitiesLTD of the base
+// balance sheet (see Reconcile), otherwise the projected cash flow will not articulate.
+type DebtSchedule struct {
+	BaseYear int // Last actual fiscal year
+	Tranches []DebtTranche
+	Curve    RateCurve
+}
+
+// TrancheFlow is one tranche in a projected year
+type TrancheFlow struct {
+	Name      string
+	Opening   float64
+	Repayment float64 // Principal repaid (positive)
+	Issuance  float64 // Principal reborrowed on refinancing
+	Closing   float64
+	Rate      float64 // Rate in force at the start of the year
+	Interest  float64
+}
+
+// DebtYear is the schedule's balances and flows for one projected year
+type DebtYear struct {
+	Year           int
+	Opening        float64
+	Repayments     float64 // Principal repaid (positive)
+	Issuance       float64 // Refinancing proceeds
+	Closing        float64
+	CurrentPortion float64 // Principal contractually due the following year
+	Interest       float64 // Average balance x rate, tranche by tranche
+	Tranches       []TrancheFlow
+}
+
+// EffectiveRate is the year's interest over the average balance
+func (d DebtYear) EffectiveRate() float64 {
+	avg := (d.Opening + d.Closing) / 2
+	if avg == 0 {
+		return 0
+	}
+	return d.Interest / avg
+}
+
+// At returns the curve rate for a fiscal year
+func (c RateCurve) At(year int) float64 {
+	if len(c) == 0 {
+		return 0
+	}
+	years := make([]int, 0, len(c))
+	for y := range c {
+		years = append(years, y)
+	}
+	sort.Ints(years)
+	rate := c[years[0]]
+	for _, y := range years {
+		if y > year {
+			break
+		}
+		rate = c[y]
+	}
+	return rate
+}
+
+// Total is the principal outstanding at the end of the base year
+func (s *DebtSchedule) Total() float64 {
+	total := 0.0
+	for _, t := range s.Tranches {
+		total += t.Principal
+	}
+	return total
+}
+
+// Reconcile scales the tranches pro rata to the balance sheet's term debt. A schedule
+// without principal gets a single tranche with no maturity at defaultRate.
+func (s *DebtSchedule) Reconcile(bs *edgar.BalanceSheet, defaultRate float64) {
+	target := getValue(bs.NoncurrentLiabilities.LongTermDebt) + getValue(bs.CurrentLiabilities.CurrentMaturitiesLTD)
+	total := s.Total()
+	switch {
+	case total > 0:
+		scale := target / total
+		for i := range s.Tranches {
+			s.Tranches[i].Principal *= scale
+			s.Tranches[i].Amortization *= scale
+		}
+	case target > 0:
+		s.Tranches = append(s.Tranches, DebtTranche{Name: "Term debt", Principal: target, Rate: defaultRate})
+	}
+}
+
+// Clone deep-copies the schedule
+func (s *DebtSchedule) Clone() *DebtSchedule {
+	if s == nil {
+		return nil
+	}
+	out := &DebtSchedule{BaseYear: s.BaseYear, Tranches: append([]DebtTranche(nil), s.Tranches...)}
+	if s.Curve != nil {
+		out.Curve = make(RateCurve, len(s.Curve))
+		for y, r := range s.Curve {
+			out.Curve[y] = r
+		}
+	}
+	return out
+}
+
+// Roll returns the balances and flows of a projected fiscal year
+func (s *DebtSchedule) Roll(year int) DebtYear {
+	tranches := append([]DebtTranche(nil), s.Tranches...)
+	out := DebtYear{Year: year, Opening: s.Total(), Closing: s.Total()}
+	for y := s.BaseYear + 1; y <= year; y++ {
+		out = rollDebtYear(tranches, y, s.Curve)
+	}
+	for _, t := range tranches {
+		out.CurrentPortion += t.dueIn(year + 1)
+	}
+	return out
+}
+
+// rate is the tranche's rate in a fiscal year
+func (t DebtTranche) rate(year int, curve RateCurve) float64 {
+	if t.Floating {
+		return curve.At(year) + t.Spread
+	}
+	return t.Rate
+}
+
+// dueIn is the principal contractually repaid (not refinanced) in a fiscal year
+func (t DebtTranche) dueIn(year int) float64 {
+	if t.Principal <= 0 {
+		return 0
+	}
+	if t.MaturityYear > 0 && year >= t.MaturityYear {
+		if t.Refinance {
+			return 0
+		}
+		return t.Principal
+	}
+	return math.Min(t.Amortization, t.Principal)
+}
+
+// rollDebtYear moves every tranche through one year, updating them in place.
+// Principal moves mid-year on average, so a year's repayment accrues half a year of interest.
+func rollDebtYear(tranches []DebtTranche, year int, curve RateCurve) DebtYear {
+	out := DebtYear{Year: year}
+	for i := range tranches {
+		t := &tranches[i]
+		flow := TrancheFlow{Name: t.Name, Opening: t.Principal, Rate: t.rate(year, curve)}
+
+		if t.MaturityYear > 0 && year >= t.MaturityYear && t.Principal > 0 {
+			flow.Repayment = t.Principal
+			flow.Interest = flow.Rate * t.Principal / 2
+			if t.Refinance {
+				flow.Issuance = t.Principal
+				if t.RefinanceRate > 0 {
+					t.Rate, t.Floating = t.RefinanceRate, false
+				}
+				tenor := t.RefinanceTenor
+				if tenor <= 0 {
+					tenor = DefaultRefinanceTenor
+				}
+				t.MaturityYear = year + tenor
+				flow.Interest += t.rate(year, curve) * t.Principal / 2
+			}
+		} else {
+			flow.Repayment = math.Min(t.Amortization, t.Principal)
+			flow.Interest = flow.Rate * (t.Principal - flow.Repayment/2)
+		}
+		t.Principal += flow.Issuance - flow.Repayment
+		flow.Closing = t.Principal
+
+		out.Opening += flow.Opening
+		out.Repayments += flow.Repayment
+		out.Issuance += flow.Issuance
+		out.Closing += flow.Closing
+		out.Interest += flow.Interest
+		out.Tranches = append(out.Tranches, flow)
+	}
+	return out
+}
+
+// NewDebtSchedule seeds a schedule from the debt note and reconciles it to the balance sheet
+func NewDebtSchedule(notes []*edgar.ExtractedNote, bs *edgar.BalanceSheet, fiscalYear int, defaultRate float64) *DebtSchedule {
+	s := &DebtSchedule{BaseYear: fiscalYear, Tranches: DebtTranchesFromNotes(notes, fiscalYear)}
+	for i := range s.Tranches {
+		if s.Tranches[i].Rate == 0 && !s.Tranches[i].Floating {
+			s.Tranches[i].Rate = defaultRate
+		}
+	}
+	s.Reconcile(bs, defaultRate)
+	return s
+}
+
+var (
+	couponPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*%`)
+	yearPattern   = regexp.MustCompile(`\b(19|20)\d{2}\b`)
+)
+
+// DebtTranchesFromNotes reads tranches from debt notes: the extracted
+// debt_instruments first, then instrument rows of the note tables
+// ("4.375% Notes due 2029"), then a maturity table ("2026", "Thereafter").
+// Rates are read in the instrument's rate_unit; rates and maturities missing from the
+// structured data, or given without a unit, are parsed from labels.
+func DebtTranchesFromNotes(notes []*edgar.ExtractedNote, fiscalYear int) []DebtTranche {
+	var tranches []DebtTranche
+	var tables []edgar.NoteTable
+	for _, n := range notes {
+		if n == nil || n.NoteCategory != edgar.NoteCategoryDebt {
+			continue
+		}
+		tables = append(tables, n.Tables...)
+		instruments, _ := n.StructuredData["debt_instruments"].([]interface{})
+		for _, raw := range instruments {
+			inst, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			principal, _ := inst["principal"].(float64)
+			if principal <= 0 {
+				continue
+			}
+			name, _ := inst["type"].(string)
+			maturity, _ := inst["maturity"].(string)
+			t := DebtTranche{Name: name, Principal: principal, MaturityYear: parseYear(maturity)}
+			if t.MaturityYear == 0 {
+				t.MaturityYear = parseYear(name)
+			}
+			unit, _ := inst["rate_unit"].(string)
+			if rate, ok := rateIn(inst["interest_rate"], unit); ok {
+				t.Rate = rate
+			} else {
+				t.Rate = parseCoupon(name)
+			}
+			if floating, _ := inst["floating"].(bool); floating {
+				t.Floating = true
+				if spread, ok := rateIn(inst["spread"], unit); ok {
+					t.Spread = spread
+				} else {
+					t.Spread = parseCoupon(name) // "SOFR + 1.75%"
+				}
+			}
+			tranches = append(tranches, t)
+		}
+	}
+	if len(tranches) > 0 {
+		return tranches
+	}
+
+	// Instrument rows, then the maturity table
+	rows := latestRows(tables, fiscalYear)
+	for _, label := range sortedLabels(rows) {
+		lower := strings.ToLower(label)
+		if !strings.Contains(lower, "due") || !couponPattern.MatchString(label) {
+			continue
+		}
+		tranches = append(tranches, DebtTranche{
+			Name:         label,
+			Principal:    rows[label],
+			Rate:         parseCoupon(label),
+			MaturityYear: parseYear(label),
+		})
+	}
+	if len(tranches) > 0 {
+		return tranches
+	}
+
+	lastYear := fiscalYear
+	var thereafter float64
+	for _, label := range sortedLabels(rows) {
+		trimmed := strings.TrimSpace(label)
+		if strings.Contains(strings.ToLower(trimmed), "thereafter") {
+			thereafter += rows[label]
+			continue
+		}
+		year, err := strconv.Atoi(trimmed)
+		if err != nil || year <= fiscalYear || year > fiscalYear+50 {
+			continue
+		}
+		tranches = append(tranches, DebtTranche{Name: "Maturing " + trimmed, Principal: rows[label], MaturityYear: year})
+		if year > lastYear {
+			lastYear = year
+		}
+	}
+	if thereafter > 0 {
+		// The note gives no finer split: "thereafter" is due the year after the last listed
+		tranches = append(tranches, DebtTranche{Name: "Maturing thereafter", Principal: thereafter, MaturityYear: lastYear + 1})
+	}
+	return tranches
+}
+
+// latestRows maps each positive row label to its fiscal-year value (latest column when absent)
+func latestRows(tables []edgar.NoteTable, fiscalYear int) map[string]float64 {
+	values := make(map[string]float64)
+	years := make(map[string]int)
+	for _, table := range tables {
+		for _, row := range table.Rows {
+			if row.Value == nil || *row.Value <= 0 {
+				continue
+			}
+			seen, ok := years[row.RowLabel]
+			if ok && (seen == fiscalYear || (row.ColumnYear != fiscalYear && row.ColumnYear <= seen)) {
+				continue
+			}
+			values[row.RowLabel] = *row.Value
+			years[row.RowLabel] = row.ColumnYear
+		}
+	}
+	return values
+}
+
+func sortedLabels(m map[string]float64) []string {
+	labels := make([]string, 0, len(m))
+	for k := range m {
+		labels = append(labels, k)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// parseYear returns the last four-digit year in s (0 if none)
+func parseYear(s string) int {
+	matches := yearPattern.FindAllString(s, -1)
+	if len(matches) == 0 {
+		return 0
+	}
+	year, _ := strconv.Atoi(matches[len(matches)-1])
+	return year
+}
+
+// parseCoupon returns the first percentage in s as a decimal (0 if none)
+func parseCoupon(s string) float64 {
+	m := couponPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0
+	}
+	rate, _ := strconv.ParseFloat(m[1], 64)
+	return rate / 100
+}
+
+// rateIn converts an extracted rate to a decimal in the unit the extractor reported
+// ("percent", "decimal" or "bps"). A rate without a known unit is not guessed at.
+func rateIn(raw interface{}, unit string) (float64, bool) {
+	rate, ok := raw.(float64)
+	if !ok {
+		return 0, false
+	}
+	switch strings.ToLower(strings.TrimSpace(unit)) {
+	case "percent", "%":
+		return rate / 100, true
+	case "decimal":
+		return rate, true
+	case "bps":
+		return rate / 10000, true
+	}
+	return 0, false
+}
@@ -0,0 +1,244 @@
+package projection
+
+import (
+	"fmt"
+	"sort"
+)
+
+// =============================================================================
+// NAMED DRIVERS
+// Scalar access to ProjectionAssumptions by name, so that simulation,
+// sensitivity and scenario tooling can flex drivers without reflection.
+// =============================================================================
+
+// driverAccessor reads and writes a single scalar field of ProjectionAssumptions
+type driverAccessor struct {
+	unit string // "%" when the decimal is displayed and entered as a percentage
+	get  func(a *ProjectionAssumptions) float64
+	set  func(a *ProjectionAssumptions, v float64)
+}
+
+// assumptionDrivers maps canonical driver names (snake_case of the struct field) to accessors
+var assumptionDrivers = map[string]driverAccessor{
+	"revenue_growth": {
+		unit: "%",
+		get:  func(a *ProjectionAssumptions) float64 { return a.RevenueGrowth },
+		set:  func(a *ProjectionAssumptions, v float64) { a.RevenueGrowth = v },
+	},
+	"cogs_percent": {
+		unit: "%",
+		get:  func(a *ProjectionAssumptions) float64 { return a.COGSPercent },
+		set:  func(a *ProjectionAssumptions, v float64) { a.COGSPercent = v },
+	},
+	"selling_marketing_percent": {
+		unit: "%",
+		get:  func(a *ProjectionAssumptions) float64 { return a.SellingMarketingPercent },
+		set:  func(a *ProjectionAssumptions, v float64) { a.SellingMarketingPercent = v },
+	},
+	"general_admin_percent": {
+		unit: "%",
+		get:  func(a *ProjectionAssumptions) float64 { return a.GeneralAdminPercent },
+		set:  func(a *ProjectionAssumptions, v float64) { a.GeneralAdminPercent = v },
+	},
+	"sga_percent": {
+		unit: "%",
+		get:  func(a *ProjectionAssumptions) float64 { return a.SGAPercent },
+		set:  func(a *ProjectionAssumptions, v float64) { a.SGAPercent = v },
+	},
+	"rd_percent": {
+		unit: "%",
+		get:  func(a *ProjectionAssumptions) float64 { return a.RDPercent },
+		set:  func(a *ProjectionAssumptions, v float64) { a.RDPercent = v },
+	},
+	"tax_rate": {
+		unit: "%",
+		get:  func(a *ProjectionAssumptions) float64 { return a.TaxRate },
+		set:  func(a *ProjectionAssumptions, v float64) { a.TaxRate = v },
+	},
+	"dso": {
+		get: func(a *ProjectionAssumptions) float64 { return a.DSO },
+		set: func(a *ProjectionAssumptions, v float64) { a.DSO = v },
+	},
+	"dsi": {
+		get: func(a *ProjectionAssumptions) float64 { return a.DSI },
+		set: func(a *ProjectionAssumptions, v float64) { a.DSI = v },
+	},
+	"dpo": {
+		get: func(a *ProjectionAssumptions) float64 { return a.DPO },
+		set: func(a *ProjectionAssumptions, v float64) { a.DPO = v },
+	},
+	"capex_percent": {
+		unit: "%",
+		get:  func(a *ProjectionAssumptions) float64 { return a.CapexPercent },
+		set:  func(a *ProjectionAssumptions, v float64) { a.CapexPercent = v },
+	},
+	"useful_life_forecast": {
+		get: func(a *ProjectionAssumptions) float64 { return a.UsefulLifeForecast },
+		set: func(a *ProjectionAssumptions, v float64) { a.UsefulLifeForecast = v },
+	},
+	"depreciation_percent": {
+		unit: "%",
+		get:  func(a *ProjectionAssumptions) float64 { return a.DepreciationPercent },
+		set:  func(a *ProjectionAssumptions, v float64) { a.DepreciationPercent = v },
+	},
+	"terminal_growth": {
+		unit: "%",
+		get:  func(a *ProjectionAssumptions) float64 { return a.TerminalGrowth },
+		set:  func(a *ProjectionAssumptions, v float64) { a.TerminalGrowth = v },
+	},
+	"unlevered_beta": {
+		get: func(a *ProjectionAssumptions) float64 { return a.UnleveredBeta },
+		set: func(a *ProjectionAssumptions, v float64) { a.UnleveredBeta = v },
+	},
+	"risk_free_rate": {
+		unit: "%",
+		get:  func(a *ProjectionAssumptions) float64 { return a.RiskFreeRate },
+		set:  func(a *ProjectionAssumptions, v float64) { a.RiskFreeRate = v },
+	},
+	"market_risk_premium": {
+		unit: "%",
+		get:  func(a *ProjectionAssumptions) float64 { return a.MarketRiskPremium },
+		set:  func(a *ProjectionAssumptions, v float64) { a.MarketRiskPremium = v },
+	},
+	"pre_tax_cost_of_debt": {
+		unit: "%",
+		get:  func(a *ProjectionAssumptions) float64 { return a.PreTaxCostOfDebt },
+		set:  func(a *ProjectionAssumptions, v float64) { a.PreTaxCostOfDebt = v },
+	},
+	"target_debt_equity": {
+		get: func(a *ProjectionAssumptions) float64 { return a.TargetDebtEquity },
+		set: func(a *ProjectionAssumptions, v float64) { a.TargetDebtEquity = v },
+	},
+	"stock_based_comp_percent": {
+		unit: "%",
+		get:  func(a *ProjectionAssumptions) float64 { return a.StockBasedCompPercent },
+		set:  func(a *ProjectionAssumptions, v float64) { a.StockBasedCompPercent = v },
+	},
+	"dividend_payout_ratio": {
+		unit: "%",
+		get:  func(a *ProjectionAssumptions) float64 { return a.DividendPayoutRatio },
+		set:  func(a *ProjectionAssumptions, v float64) { a.DividendPayoutRatio = v },
+	},
+	"cash_interest_rate": {
+		unit: "%",
+		get:  func(a *ProjectionAssumptions) float64 { return a.CashInterestRate },
+		set:  func(a *ProjectionAssumptions, v float64) { a.CashInterestRate = v },
+	},
+	"debt_interest_rate": {
+		unit: "%",
+		get:  func(a *ProjectionAssumptions) float64 { return a.DebtInterestRate },
+		set:  func(a *ProjectionAssumptions, v float64) { a.DebtInterestRate = v },
+	},
+	"receivables_percent": {
+		unit: "%",
+		get:  func(a *ProjectionAssumptions) float64 { return a.ReceivablesPercent },
+		set:  func(a *ProjectionAssumptions, v float64) { a.ReceivablesPercent = v },
+	},
+	"inventory_percent": {
+		unit: "%",
+		get:  func(a *ProjectionAssumptions) float64 { return a.InventoryPercent },
+		set:  func(a *ProjectionAssumptions, v float64) { a.InventoryPercent = v },
+	},
+	"accounts_payable_percent": {
+		unit: "%",
+		get:  func(a *ProjectionAssumptions) float64 { return a.AccountsPayablePercent },
+		set:  func(a *ProjectionAssumptions, v float64) { a.AccountsPayablePercent = v },
+	},
+	"deferred_revenue_percent": {
+		unit: "%",
+		get:  func(a *ProjectionAssumptions) float64 { return a.DeferredRevenuePercent },
+		set:  func(a *ProjectionAssumptions, v float64) { a.DeferredRevenuePercent = v },
+	},
+	"shares_outstanding": {
+		get: func(a *ProjectionAssumptions) float64 { return a.SharesOutstanding },
+		set: func(a *ProjectionAssumptions, v float64) { a.SharesOutstanding = v },
+	},
+}
+
+// driverAliases maps the short keys used by the debate agents and the
+// Synthesizer table (see ConvertDebateReportToAssumptions) to canonical names
+var driverAliases = map[string]string{
+	"rev_growth":          "revenue_growth",
+	"cogs_pct":            "cogs_percent",
+	"sga_pct":             "sga_percent",
+	"rd_pct":              "rd_percent",
+	"capex_ratio":         "capex_percent",
+	"beta":                "unlevered_beta",
+	"equity_risk_premium": "market_risk_premium",
+	"cost_of_debt":        "pre_tax_cost_of_debt",
+	"target_leverage":     "target_debt_equity",
+}
+
+// ResolveDriverName returns the canonical driver name for a name or alias
+func ResolveDriverName(name string) (string, bool) {
+	if _, ok := assumptionDrivers[name]; ok {
+		return name, true
+	}
+	if canonical, ok := driverAliases[name]; ok {
+		return canonical, true
+	}
+	return "", false
+}
+
+// IsDriverName checks whether a name (or alias) addresses a ProjectionAssumptions field
+func IsDriverName(name string) bool {
+	_, ok := ResolveDriverName(name)
+	return ok
+}
+
+// DriverUnit returns the display unit of a driver ("%" for rates, "" otherwise)
+func DriverUnit(name string) string {
+	canonical, _ := ResolveDriverName(name)
+	return assumptionDrivers[canonical].unit
+}
+
+// DriverNames returns all canonical driver names in sorted order
+func DriverNames() []string {
+	names := make([]string, 0, len(assumptionDrivers))
+	for name := range assumptionDrivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetDriver reads a named driver from the assumptions
+func (a *ProjectionAssumptions) GetDriver(name string) (float64, error) {
+	canonical, ok := ResolveDriverName(name)
+	if !ok {
+		return 0, fmt.Errorf("unknown projection driver '%s'", name)
+	}
+	return assumptionDrivers[canonical].get(a), nil
+}
+
+// SetDriver writes a named driver on the assumptions
+func (a *ProjectionAssumptions) SetDriver(name string, value float64) error {
+	canonical, ok := ResolveDriverName(name)
+	if !ok {
+		return fmt.Errorf("unknown projection driver '%s'", name)
+	}
+	assumptionDrivers[canonical].set(a, value)
+	return nil
+}
+
+// Clone returns a deep copy of the assumptions (maps are copied, not shared)
+func (a ProjectionAssumptions) Clone() ProjectionAssumptions {
+	out := a
+	if a.NodeDrivers != nil {
+		out.NodeDrivers = make(map[string]float64, len(a.NodeDrivers))
+		for k, v := range a.NodeDrivers {
+			out.NodeDrivers[k] = v
+		}
+	}
+	if a.SegmentGrowth != nil {
+		out.SegmentGrowth = make(map[string]float64, len(a.SegmentGrowth))
+		for k, v := range a.SegmentGrowth {
+			out.SegmentGrowth[k] = v
+		}
+	}
+	out.DebtSchedule = a.DebtSchedule.Clone()
+	out.FixedAssets = a.FixedAssets.Clone()
+	out.Tax = a.Tax.Clone()
+	out.Shares = a.Shares.Clone()
+	return out
+}
@@ -3,6 +3,7 @@ package projection
 import (
 	"agentic_valuation/pkg/core/calc"
 	"agentic_valuation/pkg/core/edgar"
+	"fmt"
 	"math"
 )
 
@@ -10,6 +11,10 @@ import (
 // It ensures that Income Statement, Cash Flow, and Balance Sheet are mathematically consistent.
 type ProjectionEngine struct {
 	Skeleton *StandardSkeleton
+
+	// Financing circularity solver (zero = DefaultInterestTolerance / DefaultMaxInterestIterations)
+	InterestTolerance     float64
+	MaxInterestIterations int
 }
 
 // NewProjectionEngine creates a new articulation engine
@@ -19,7 +24,11 @@ func NewProjectionEngine(skeleton *StandardSkeleton) *ProjectionEngine {
 	}
 }
 
-// ProjectYear calculates T+1 financials based on T-0 (history) and assumptions
+// ProjectYear calculates T+1 financials based on T-0 (history) and assumptions.
+// With a skeleton, nodes whose strategy was set by the AI or a user are evaluated
+// as a dependency graph and drive the year; a graph that cannot be evaluated
+// leaves the assumption-driven year in place and is reported in GraphError. The
+// skeleton is only read: driver values carry between years within ProjectHorizon.
 func (e *ProjectionEngine) ProjectYear(
 	prevIS *edgar.IncomeStatement,
 	prevBS *edgar.BalanceSheet,
@@ -27,18 +36,162 @@ func (e *ProjectionEngine) ProjectYear(
 	assumptions ProjectionAssumptions,
 	targetYear int,
 ) *ProjectedFinancials {
+	graph, err := e.graph()
+	return e.projectGraphYear(graph, err, prevIS, prevBS, prevSegments, assumptions, targetYear)
+}
 
-	// 1. Income Statement
-	projIS, projSegments, projNI, projDividends, projRev := e.projectIncomeStatement(prevIS, prevBS, prevSegments, assumptions)
+// graph builds the skeleton's evaluation graph (nil without a skeleton)
+func (e *ProjectionEngine) graph() (*NodeGraph, error) {
+	if e.Skeleton == nil {
+		return nil, nil
+	}
+	return e.Skeleton.Graph()
+}
 
-	// Extract COGS for BS drivers (Inventory/AP often drive off COGS)
-	projCOGS := getValue(projIS.GrossProfitSection.CostOfGoodsSold)
+// projectGraphYear projects the year and lets the graph drive it, recording the
+// year's values in the graph for the following years
+func (e *ProjectionEngine) projectGraphYear(
+	graph *NodeGraph,
+	graphErr error,
+	prevIS *edgar.IncomeStatement,
+	prevBS *edgar.BalanceSheet,
+	prevSegments []edgar.StandardizedSegment,
+	assumptions ProjectionAssumptions,
+	targetYear int,
+) *ProjectedFinancials {
+	proj := e.projectYear(prevIS, prevBS, prevSegments, assumptions, targetYear)
+	if graph == nil {
+		proj.GraphError = graphErr
+		return proj
+	}
+	proj.Warnings = graph.Warnings()
+	graph.record(targetYear-1, prevIS, prevBS, nil, true)
+	graph.record(targetYear, proj.IncomeStatement, proj.BalanceSheet, proj.CashFlow, false)
+	if !graph.HasOverrides() {
+		proj.NodeValues = graph.yearValues(targetYear)
+		return proj
+	}
 
-	// 2. Balance Sheet
-	projBS, revolverNeeded, projDep, projCapex, projSBC := e.projectBalanceSheet(prevBS, assumptions, projRev, projCOGS, projNI, projDividends)
+	var last map[string]float64
+	for i := 0; i < maxGraphIterations; i++ {
+		values, err := graph.Evaluate(targetYear)
+		if err == nil && graph.sameValues(last, values) {
+			graph.storeDrivers(targetYear, values)
+			proj.NodeValues = graph.yearValues(targetYear)
+			return proj
+		}
+		var driven ProjectionAssumptions
+		if err == nil {
+			driven, err = applyGraph(graph, values, prevIS, prevSegments, assumptions)
+		}
+		if err != nil {
+			baseline := e.projectYear(prevIS, prevBS, prevSegments, assumptions, targetYear)
+			graph.record(targetYear, baseline.IncomeStatement, baseline.BalanceSheet, baseline.CashFlow, false)
+			baseline.GraphError = err
+			baseline.Warnings = proj.Warnings
+			baseline.NodeValues = graph.yearValues(targetYear)
+			return baseline
+		}
+		last = values
+		warnings := proj.Warnings
+		proj = e.projectYear(prevIS, prevBS, prevSegments, driven, targetYear)
+		proj.Warnings = warnings
+		graph.record(targetYear, proj.IncomeStatement, proj.BalanceSheet, proj.CashFlow, false)
+	}
+	proj.GraphError = fmt.Errorf("skeleton graph did not settle in %d passes", maxGraphIterations)
+	return proj
+}
+
+// projectYear builds the year's statements from the assumptions alone
+func (e *ProjectionEngine) projectYear(
+	prevIS *edgar.IncomeStatement,
+	prevBS *edgar.BalanceSheet,
+	prevSegments []edgar.StandardizedSegment,
+	assumptions ProjectionAssumptions,
+	targetYear int,
+) *ProjectedFinancials {
+
+	// Term debt follows its schedule when one is set
+	var debt *DebtYear
+	if assumptions.DebtSchedule != nil {
+		d := assumptions.DebtSchedule.Roll(targetYear)
+		debt = &d
+	}
+
+	// PP&E follows the vintage waterfall when a policy is set (capex does not depend on interest)
+	rev, _ := projectRevenue(prevIS, prevSegments, assumptions)
+	var ppe *PPERoll
+	if assumptions.FixedAssets != nil {
+		roll := assumptions.FixedAssets.Roll(prevBS, assumptions, rev*assumptions.CapexPercent, targetYear)
+		ppe = &roll
+	}
+
+	// Taxes follow the NOL and deferred tax roll when a policy is set
+	var taxes *taxBasis
+	if assumptions.Tax != nil {
+		taxes = &taxBasis{
+			policy:           assumptions.Tax,
+			year:             targetYear,
+			rate:             assumptions.TaxRate,
+			bookDepreciation: projectDepreciation(prevBS, assumptions, rev, ppe),
+		}
+	}
+
+	// The share count follows its roll-forward when a policy is set
+	var shareCount *shareBasis
+	if assumptions.Shares != nil {
+		shareCount = &shareBasis{
+			policy:            assumptions.Shares,
+			year:              targetYear,
+			sbc:               rev * assumptions.StockBasedCompPercent,
+			sharesOutstanding: assumptions.SharesOutstanding,
+		}
+	}
+
+	// 1-2. Income Statement and Balance Sheet, iterated until interest on the
+	// average balances agrees with the balances it produces
+	solve := openingInterest(prevIS, prevBS, assumptions, debt)
+	var (
+		projIS                                      *edgar.IncomeStatement
+		projSegments                                []edgar.StandardizedSegment
+		projBS                                      *edgar.BalanceSheet
+		tax                                         *TaxRoll
+		shares                                      *ShareRoll
+		projNI, projDividends, projRev              float64
+		revolverNeeded, projDep, projCapex, projSBC float64
+	)
+	for {
+		projIS, projSegments, projNI, projDividends, projRev = e.projectIncomeStatement(prevIS, prevSegments, assumptions, solve, ppe, taxes, shareCount)
+		tax = taxes.roll(getValue(projIS.NonOperatingSection.IncomeBeforeTax), solve.Net())
+		shares = shareCount.roll(projNI)
+
+		// Extract COGS for BS drivers (Inventory/AP often drive off COGS)
+		projCOGS := getValue(projIS.GrossProfitSection.CostOfGoodsSold)
+
+		projBS, revolverNeeded, projDep, projCapex, projSBC = e.projectBalanceSheet(prevBS, assumptions, projRev, projCOGS, projNI, projDividends, debt, ppe, tax, shares)
+
+		if assumptions.InterestConvention == InterestBeginningBalance {
+			solve.Converged = true
+			break
+		}
+		next := averageInterest(prevBS, projBS, solve, debt)
+		next.Iterations = solve.Iterations + 1
+		next.Residual = math.Abs(next.Net() - solve.Net())
+		if next.Residual < e.interestTolerance() {
+			// The statements were built with solve; keep its interest and report the residual
+			solve.Iterations, solve.Residual, solve.Converged = next.Iterations, next.Residual, true
+			break
+		}
+		if next.Iterations >= e.maxInterestIterations() {
+			// Unconverged: report how far the interest charged is from its balances
+			solve.Iterations, solve.Residual = next.Iterations, next.Residual
+			break
+		}
+		solve = next
+	}
 
 	// 3. Cash Flow
-	projCF := e.projectCashFlow(prevBS, projBS, projNI, projDep, projCapex, projSBC, revolverNeeded, projDividends)
+	projCF := e.projectCashFlow(prevBS, projBS, projNI, projDep, projCapex, projSBC, revolverNeeded, projDividends, debt, ppe, tax, shares)
 
 	return &ProjectedFinancials{
 		Year:            targetYear,
@@ -46,50 +199,26 @@ func (e *ProjectionEngine) ProjectYear(
 		BalanceSheet:    projBS,
 		CashFlow:        projCF,
 		Segments:        projSegments,
+		Interest:        &solve,
+		Debt:            debt,
+		FixedAssets:     ppe,
+		Tax:             tax,
+		Shares:          shares,
 	}
 }
 
 // projectIncomeStatement calculates the projected Income Statement
 func (e *ProjectionEngine) projectIncomeStatement(
 	prevIS *edgar.IncomeStatement,
-	prevBS *edgar.BalanceSheet,
 	prevSegments []edgar.StandardizedSegment,
 	assumptions ProjectionAssumptions,
+	interest InterestSolve,
+	ppe *PPERoll, // Fixed-asset waterfall (nil = none); supplies impairments
+	taxes *taxBasis, // NOL and deferred tax roll (nil = TaxRate on pre-tax income)
+	shareCount *shareBasis, // Share count roll-forward (nil = SharesOutstanding)
 ) (*edgar.IncomeStatement, []edgar.StandardizedSegment, float64, float64, float64) {
 
-	// -------------------------------------------------------------------------
-	// Revenue Logic: SOTP vs Aggregate
-	// -------------------------------------------------------------------------
-	prevRev := getValue(prevIS.GrossProfitSection.Revenues)
-	projRev := 0.0
-	projSegments := make([]edgar.StandardizedSegment, 0)
-
-	// If we have Segment Growth Drivers AND Previous Segments, use SOTP
-	if len(assumptions.SegmentGrowth) > 0 && len(prevSegments) > 0 {
-		totalSegRev := 0.0
-		for _, seg := range prevSegments {
-			growth, ok := assumptions.SegmentGrowth[seg.Name]
-			if !ok {
-				growth = assumptions.RevenueGrowth // Fallback to aggregate
-			}
-			prevSegRev := getValue(seg.Revenues)
-			newSegRev := prevSegRev * (1 + growth)
-
-			// Create projected segment
-			newSeg := seg
-			newVal := newSegRev
-			newSeg.Revenues = &edgar.FSAPValue{Value: &newVal}
-			projSegments = append(projSegments, newSeg)
-
-			totalSegRev += newSegRev
-		}
-		// If segments explain most revenue (>80%), use SOTP sum.
-		// Otherwise, scaling might be needed, but for now Trust the Segments.
-		projRev = totalSegRev
-	} else {
-		// Aggregate Growth
-		projRev = prevRev * (1 + assumptions.RevenueGrowth)
-	}
+	projRev, projSegments := projectRevenue(prevIS, prevSegments, assumptions)
 
 	// COGS
 	projCOGS := -(projRev * assumptions.COGSPercent) // Negative expense
@@ -111,45 +240,27 @@ func (e *ProjectionEngine) projectIncomeStatement(
 	}
 
 	projRD := -(projRev * assumptions.RDPercent)
-	projOpInc := projGP + projSGA + projRD
 
-	// -------------------------------------------------------------------------
-	// Interest Expense & Income (Granular)
-	// -------------------------------------------------------------------------
-	// Debt Interest
-	prevLTD := getValue(prevBS.NoncurrentLiabilities.LongTermDebt)
-	prevSTDebt := getValue(prevBS.CurrentLiabilities.NotesPayableShortTermDebt)
-	totalDebt := prevLTD + prevSTDebt
-
-	interestRate := assumptions.DebtInterestRate
-	if interestRate == 0 {
-		interestRate = assumptions.PreTaxCostOfDebt // Fallback to WACC component
-	}
-
-	// If still 0, create a floor based on history?
-	// For now, let's respect the 0 if explicit, otherwise fallback to history implied
-	prevInterestExp := getValue(prevIS.NonOperatingSection.InterestExpense)
-	if interestRate == 0 && totalDebt > 0 {
-		// Implied rate from history
-		impliedRate := math.Abs(prevInterestExp) / totalDebt
-		if impliedRate > 0 {
-			interestRate = impliedRate
-		}
+	// Impairment of fixed assets (non-cash, added back in operating cash flow)
+	var projOtherOpEx *edgar.FSAPValue
+	projImpairment := 0.0
+	if ppe != nil && ppe.Impairment != 0 {
+		projImpairment = -ppe.Impairment
+		projOtherOpEx = &edgar.FSAPValue{Value: &projImpairment}
 	}
-	projInterestExp := -(totalDebt * interestRate)
-
-	// Cash Interest
-	prevCash := getValue(prevBS.CurrentAssets.CashAndEquivalents)
-	projInterestInc := prevCash * assumptions.CashInterestRate
+	projOpInc := projGP + projSGA + projRD + projImpairment
 
-	// Net Interest
-	projNetInterest := projInterestExp + projInterestInc
+	// Net Interest (solved in ProjectYear)
+	projNetInterest := interest.Net()
 
 	// Pre-Tax Income
 	projEBT := projOpInc + projNetInterest
 
-	// Tax
+	// Tax (book expense; losses only carry a benefit through the tax roll's deferred tax asset)
 	projTax := -(projEBT * assumptions.TaxRate)
+	if roll := taxes.roll(projEBT, projNetInterest); roll != nil {
+		projTax = -roll.BookTax
+	}
 
 	// Net Income (Attrib to all)
 	projNI := projEBT + projTax
@@ -159,6 +270,9 @@ func (e *ProjectionEngine) projectIncomeStatement(
 
 	// EPS Calculation
 	shares := assumptions.SharesOutstanding
+	if roll := shareCount.roll(projNI); roll != nil {
+		shares = roll.WeightedAverage
+	}
 	if shares == 0 {
 		shares = 100.0 // Default to avoid division by zero or use specific fallback
 	}
@@ -175,11 +289,12 @@ func (e *ProjectionEngine) projectIncomeStatement(
 			GrossProfit:     &edgar.FSAPValue{Value: &projGP},
 		},
 		OperatingCostSection: &edgar.OperatingCostSection{
-			SGAExpenses:      &edgar.FSAPValue{Value: &projSGA}, // Total
-			SellingMarketing: &edgar.FSAPValue{Value: &projSelling},
-			GeneralAdmin:     &edgar.FSAPValue{Value: &projAdmin},
-			RDExpenses:       &edgar.FSAPValue{Value: &projRD},
-			OperatingIncome:  &edgar.FSAPValue{Value: &projOpInc},
+			SGAExpenses:            &edgar.FSAPValue{Value: &projSGA}, // Total
+			SellingMarketing:       &edgar.FSAPValue{Value: &projSelling},
+			GeneralAdmin:           &edgar.FSAPValue{Value: &projAdmin},
+			RDExpenses:             &edgar.FSAPValue{Value: &projRD},
+			OtherOperatingExpenses: projOtherOpEx,
+			OperatingIncome:        &edgar.FSAPValue{Value: &projOpInc},
 		},
 		NonOperatingSection: &edgar.NonOperatingSection{
 			InterestExpense: &edgar.FSAPValue{Value: &projNetInterest}, // Net for now in this slot
@@ -239,6 +354,70 @@ func (e *ProjectionEngine) projectIncomeStatement(
 	return projIS, projSegments, projNI, projDividends, projRev
 }
 
+// projectRevenue grows revenue by segment (SOTP) when segment drivers and history exist,
+// otherwise in aggregate
+func projectRevenue(
+	prevIS *edgar.IncomeStatement,
+	prevSegments []edgar.StandardizedSegment,
+	assumptions ProjectionAssumptions,
+) (float64, []edgar.StandardizedSegment) {
+	// -------------------------------------------------------------------------
+	// Revenue Logic: SOTP vs Aggregate
+	// -------------------------------------------------------------------------
+	prevRev := getValue(prevIS.GrossProfitSection.Revenues)
+	projRev := 0.0
+	projSegments := make([]edgar.StandardizedSegment, 0)
+
+	// If we have Segment Growth Drivers AND Previous Segments, use SOTP
+	if len(assumptions.SegmentGrowth) > 0 && len(prevSegments) > 0 {
+		totalSegRev := 0.0
+		for _, seg := range prevSegments {
+			growth, ok := assumptions.SegmentGrowth[seg.Name]
+			if !ok {
+				growth = assumptions.RevenueGrowth // Fallback to aggregate
+			}
+			prevSegRev := getValue(seg.Revenues)
+			newSegRev := prevSegRev * (1 + growth)
+
+			// Create projected segment
+			newSeg := seg
+			newVal := newSegRev
+			newSeg.Revenues = &edgar.FSAPValue{Value: &newVal}
+			projSegments = append(projSegments, newSeg)
+
+			totalSegRev += newSegRev
+		}
+		// If segments explain most revenue (>80%), use SOTP sum.
+		// Otherwise, scaling might be needed, but for now Trust the Segments.
+		projRev = totalSegRev
+	} else {
+		// Aggregate Growth
+		projRev = prevRev * (1 + assumptions.RevenueGrowth)
+	}
+
+	return projRev, projSegments
+}
+
+// projectDepreciation is the year's book depreciation: the fixed-asset waterfall when
+// set, otherwise the life or rate driver on opening gross PP&E
+func projectDepreciation(prevBS *edgar.BalanceSheet, assumptions ProjectionAssumptions, projRev float64, ppe *PPERoll) float64 {
+	if ppe != nil {
+		return ppe.Depreciation
+	}
+	prevPPEAtCost := getValue(prevBS.NoncurrentAssets.PPEAtCost)
+	if prevPPENet := getValue(prevBS.NoncurrentAssets.PPENet); prevPPEAtCost == 0 && prevPPENet > 0 {
+		prevPPEAtCost = prevPPENet
+	}
+	switch {
+	case assumptions.UsefulLifeForecast > 0:
+		return prevPPEAtCost / assumptions.UsefulLifeForecast
+	case assumptions.DepreciationPercent > 0:
+		return prevPPEAtCost * assumptions.DepreciationPercent
+	default:
+		return projRev * 0.03 // Fallback
+	}
+}
+
 // projectBalanceSheet calculates the projected Balance Sheet
 func (e *ProjectionEngine) projectBalanceSheet(
 	prevBS *edgar.BalanceSheet,
@@ -247,6 +426,10 @@ func (e *ProjectionEngine) projectBalanceSheet(
 	projCOGS float64, // Needed for DSI/DPO
 	projNI float64,
 	projDividends float64,
+	debt *DebtYear, // Scheduled term debt (nil = held flat)
+	ppe *PPERoll, // Fixed-asset waterfall (nil = depreciation driver on gross PP&E)
+	tax *TaxRoll, // Deferred tax movements (nil = held flat)
+	shares *ShareRoll, // Buybacks and option exercises (nil = none)
 ) (*edgar.BalanceSheet, float64, float64, float64, float64) {
 
 	// -------------------------------------------------------------------------
@@ -286,20 +469,15 @@ func (e *ProjectionEngine) projectBalanceSheet(
 		prevPPEAtCost = prevPPENet
 	}
 
-	// Depreciation
-	projDep := 0.0
-	if assumptions.UsefulLifeForecast > 0 {
-		projDep = prevPPEAtCost / assumptions.UsefulLifeForecast
-	} else if assumptions.DepreciationPercent > 0 {
-		projDep = prevPPEAtCost * assumptions.DepreciationPercent
-	} else {
-		projDep = projRev * 0.03 // Fallback
-	}
-
+	projDep := projectDepreciation(prevBS, assumptions, projRev, ppe)
 	projCapex := -(projRev * assumptions.CapexPercent)
 
 	projPPEAtCost := prevPPEAtCost + math.Abs(projCapex)
 	projAccumDep := prevAccumDep + projDep
+	if ppe != nil {
+		projPPEAtCost = ppe.ClosingCost
+		projAccumDep = ppe.ClosingAccumulated
+	}
 	projPPENet := projPPEAtCost - projAccumDep
 
 	// Other Non-Current Rollforwards
@@ -313,6 +491,9 @@ func (e *ProjectionEngine) projectBalanceSheet(
 	projIntangibles := prevIntangibles
 	projLTI := prevLTI
 	projDTA := prevDTA
+	if tax != nil {
+		projDTA += tax.DeferredTaxAssetChange
+	}
 	projOtherNCA := prevOtherNCA
 
 	// -------------------------------------------------------------------------
@@ -347,6 +528,9 @@ func (e *ProjectionEngine) projectBalanceSheet(
 	prevLTD := getValue(prevBS.NoncurrentLiabilities.LongTermDebt)
 
 	projDTL := prevDTL
+	if tax != nil {
+		projDTL += tax.DeferredTaxLiabilityChange
+	}
 	projOtherNCL := prevOtherNCL
 	projLTD := prevLTD // Debt held constant before plug
 
@@ -367,6 +551,25 @@ func (e *ProjectionEngine) projectBalanceSheet(
 	projNCI := prevNCI
 	projAOCI := prevAOCI
 	projTreasury := prevTreasury
+	if shares != nil {
+		projStock += shares.ExerciseProceeds // Exercise cash increases APIC
+		projTreasury -= shares.BuybackSpend  // Repurchases are held in treasury (contra-equity)
+	}
+
+	// Lines without a driver (leases, pensions, finance division...) are held flat.
+	// Additional items carry forward too; NodeDrivers (% of Revenue) override them by label.
+	carried := carryForwardLines(prevBS)
+	if debt != nil {
+		// Scheduled term debt: the portion due next year is current
+		current := debt.CurrentPortion
+		projLTD = debt.Closing - current
+		carried.CurrentMaturitiesLTD = &edgar.FSAPValue{Value: &current}
+	}
+	extraCA := rollAdditionalItems(prevBS.CurrentAssets.AdditionalItems, assumptions.NodeDrivers, "BS-CA:", projRev)
+	extraNCA := rollAdditionalItems(prevBS.NoncurrentAssets.AdditionalItems, assumptions.NodeDrivers, "BS-NCA:", projRev)
+	extraCL := rollAdditionalItems(prevBS.CurrentLiabilities.AdditionalItems, assumptions.NodeDrivers, "BS-CL:", projRev)
+	extraNCL := rollAdditionalItems(prevBS.NoncurrentLiabilities.AdditionalItems, assumptions.NodeDrivers, "BS-NCL:", projRev)
+	extraEq := rollAdditionalItems(prevBS.Equity.AdditionalItems, assumptions.NodeDrivers, "BS-Eq:", projRev)
 
 	// -------------------------------------------------------------------------
 	// F. Balance Sheet Balancing (The Plug)
@@ -374,24 +577,25 @@ func (e *ProjectionEngine) projectBalanceSheet(
 	// Strategy: Cash = (L + E) - (Non-Cash Assets)
 
 	// Sum L + E (Excluding ST Debt Plug)
-	clTotalNoPlug := projAP + projAccrued + projOtherCL + projDefRev
-	nclTotal := projLTD + projDTL + projOtherNCL
-	eqTotal := projStock + projRE + projNCI + projAOCI + projTreasury
+	clTotalNoPlug := projAP + projAccrued + projOtherCL + projDefRev + sumItems(extraCL)
+	nclTotal := projLTD + projDTL + projOtherNCL + sumItems(extraNCL)
+	eqTotal := projStock + projRE + projNCI + projAOCI + projTreasury + sumItems(extraEq)
 
-	totalSources := clTotalNoPlug + nclTotal + eqTotal
+	totalSources := clTotalNoPlug + nclTotal + eqTotal + carried.sources()
 
 	// Sum Non-Cash Assets
 	ncaTotal := projAR + projInv + projOtherCA + projSTInvest +
 		projPPENet + projGoodwill + projIntangibles +
-		projLTI + projDTA + projOtherNCA
+		projLTI + projDTA + projOtherNCA +
+		sumItems(extraCA) + sumItems(extraNCA) + carried.uses()
 
 	// Derived Cash
 	derivedCash := totalSources - ncaTotal
 	revolverNeeded := 0.0
 
-	if derivedCash < 0 {
-		revolverNeeded = math.Abs(derivedCash)
-		derivedCash = 0 // Min cash floor
+	if derivedCash < assumptions.MinimumCash {
+		revolverNeeded = assumptions.MinimumCash - derivedCash
+		derivedCash = assumptions.MinimumCash // Min cash floor
 	}
 
 	projCash := derivedCash
@@ -443,47 +647,12 @@ func (e *ProjectionEngine) projectBalanceSheet(
 		},
 	}
 
-	// Dynamic Item Projection (NodeDrivers) for Balance Sheet
-	if assumptions.NodeDrivers != nil {
-		for key, pct := range assumptions.NodeDrivers {
-			projValue := projRev * pct
-			projValuePtr := new(float64)
-			*projValuePtr = projValue
-
-			switch {
-			case len(key) > 6 && key[:6] == "BS-CA:":
-				label := key[7:]
-				projBS.CurrentAssets.AdditionalItems = append(
-					projBS.CurrentAssets.AdditionalItems,
-					edgar.FSAPValue{Label: label, Value: projValuePtr},
-				)
-			case len(key) > 7 && key[:7] == "BS-NCA:":
-				label := key[8:]
-				projBS.NoncurrentAssets.AdditionalItems = append(
-					projBS.NoncurrentAssets.AdditionalItems,
-					edgar.FSAPValue{Label: label, Value: projValuePtr},
-				)
-			case len(key) > 6 && key[:6] == "BS-CL:":
-				label := key[7:]
-				projBS.CurrentLiabilities.AdditionalItems = append(
-					projBS.CurrentLiabilities.AdditionalItems,
-					edgar.FSAPValue{Label: label, Value: projValuePtr},
-				)
-			case len(key) > 7 && key[:7] == "BS-NCL:":
-				label := key[8:]
-				projBS.NoncurrentLiabilities.AdditionalItems = append(
-					projBS.NoncurrentLiabilities.AdditionalItems,
-					edgar.FSAPValue{Label: label, Value: projValuePtr},
-				)
-			case len(key) > 6 && key[:6] == "BS-Eq:":
-				label := key[7:]
-				projBS.Equity.AdditionalItems = append(
-					projBS.Equity.AdditionalItems,
-					edgar.FSAPValue{Label: label, Value: projValuePtr},
-				)
-			}
-		}
-	}
+	projBS.CurrentAssets.AdditionalItems = extraCA
+	projBS.NoncurrentAssets.AdditionalItems = extraNCA
+	projBS.CurrentLiabilities.AdditionalItems = extraCL
+	projBS.NoncurrentLiabilities.AdditionalItems = extraNCL
+	projBS.Equity.AdditionalItems = extraEq
+	carried.apply(projBS)
 
 	calc.CalculateBalanceSheetTotals(projBS)
 
@@ -500,6 +669,10 @@ func (e *ProjectionEngine) projectCashFlow(
 	projSBC float64,
 	revolverNeeded float64, // Used for Financing
 	projDividends float64,
+	debt *DebtYear, // Scheduled term debt flows (nil = none)
+	ppe *PPERoll, // Disposals and impairments (nil = none)
+	tax *TaxRoll, // Deferred taxes (nil = none)
+	shares *ShareRoll, // Buybacks and option exercise proceeds (nil = none)
 ) *edgar.CashFlowStatement {
 
 	// 5. Reconcile Cash Flow
@@ -519,29 +692,78 @@ func (e *ProjectionEngine) projectCashFlow(
 	chgAR := -(projAR - prevAR)
 	chgInv := -(projInv - prevInv)
 	chgAP := (projAP - prevAP)
+	chgDefRev := getValue(projBS.CurrentLiabilities.DeferredRevenueCurrent) - getValue(prevBS.CurrentLiabilities.DeferredRevenueCurrent)
+
+	// Driven additional items: working capital in operations, long-term assets in
+	// investing, long-term liabilities and equity in financing
+	chgOtherWC := -(sumItems(projBS.CurrentAssets.AdditionalItems) - sumItems(prevBS.CurrentAssets.AdditionalItems)) +
+		(sumItems(projBS.CurrentLiabilities.AdditionalItems) - sumItems(prevBS.CurrentLiabilities.AdditionalItems))
+	chgOtherInv := -(sumItems(projBS.NoncurrentAssets.AdditionalItems) - sumItems(prevBS.NoncurrentAssets.AdditionalItems))
+	chgOtherFin := (sumItems(projBS.NoncurrentLiabilities.AdditionalItems) - sumItems(prevBS.NoncurrentLiabilities.AdditionalItems)) +
+		(sumItems(projBS.Equity.AdditionalItems) - sumItems(prevBS.Equity.AdditionalItems))
+
+	// The revolver is a balance plug: the flow is the change in short-term debt
+	chgRevolver := revolverNeeded - getValue(prevBS.CurrentLiabilities.NotesPayableShortTermDebt)
+	var debtProceeds, debtRepayments float64
+	if chgRevolver >= 0 {
+		debtProceeds = chgRevolver
+	} else {
+		debtRepayments = chgRevolver
+	}
+	if debt != nil {
+		debtProceeds += debt.Issuance
+		debtRepayments -= debt.Repayments
+	}
+
+	var projBuybacks, projIssuance float64
+	if shares != nil {
+		projBuybacks = -shares.BuybackSpend
+		projIssuance = shares.ExerciseProceeds
+	}
 
 	finalNetChange := projCash - prevCash
 
 	// Calculate Section Totals explicitly
 	// OCF = NI + Dep + SBC + Working Capital Changes
-	netCashOp := projNI + projDep + projSBC + chgAR + chgInv + chgAP
-	netCashInv := projCapex
-	netCashFin := revolverNeeded - projDividends // Inflows (Debt) - Outflows (Divs)
+	var projImpairment, projDisposals float64
+	if ppe != nil {
+		projImpairment = ppe.Impairment
+		projDisposals = ppe.DisposalProceeds
+	}
+	var projDeferredTax float64
+	if tax != nil {
+		projDeferredTax = tax.DeferredTax
+	}
+
+	netCashOp := projNI + projDep + projSBC + projImpairment + projDeferredTax + chgAR + chgInv + chgAP + chgDefRev + chgOtherWC
+	netCashInv := projCapex + projDisposals + chgOtherInv
+	netCashFin := debtProceeds + debtRepayments + projIssuance + projBuybacks - projDividends + chgOtherFin // Inflows (Debt) - Outflows (Divs)
 
 	projCF := &edgar.CashFlowStatement{
 		OperatingActivities: &edgar.CFOperatingSection{
 			NetIncomeStart:           &edgar.FSAPValue{Value: &projNI},
 			DepreciationAmortization: &edgar.FSAPValue{Value: &projDep},
+			DeferredTaxes:            &edgar.FSAPValue{Value: &projDeferredTax},
+			StockBasedCompensation:   &edgar.FSAPValue{Value: &projSBC},
+			ImpairmentCharges:        &edgar.FSAPValue{Value: &projImpairment},
 			ChangeReceivables:        &edgar.FSAPValue{Value: &chgAR},
 			ChangeInventory:          &edgar.FSAPValue{Value: &chgInv},
 			ChangePayables:           &edgar.FSAPValue{Value: &chgAP},
+			ChangeDeferredRevenue:    &edgar.FSAPValue{Value: &chgDefRev},
+			OtherWorkingCapital:      &edgar.FSAPValue{Value: &chgOtherWC},
 		},
 		InvestingActivities: &edgar.CFInvestingSection{
-			Capex: &edgar.FSAPValue{Value: &projCapex},
+			Capex:              &edgar.FSAPValue{Value: &projCapex},
+			ProceedsAssetSales: &edgar.FSAPValue{Value: &projDisposals},
+			OtherInvesting:     &edgar.FSAPValue{Value: &chgOtherInv},
 		},
 		FinancingActivities: &edgar.CFFinancingSection{
-			DebtProceeds:  &edgar.FSAPValue{Value: &revolverNeeded},
-			DividendsPaid: &edgar.FSAPValue{Value: &projDividends},
+			DebtProceeds:          &edgar.FSAPValue{Value: &debtProceeds},
+			DebtRepayments:        &edgar.FSAPValue{Value: &debtRepayments},
+			StockIssuanceProceeds: &edgar.FSAPValue{Value: &projIssuance},
+			ShareRepurchases:      &edgar.FSAPValue{Value: &projBuybacks},
+			DividendsPaid:         &edgar.FSAPValue{Value: &projDividends},
+			OtherFinancing:        &edgar.FSAPValue{Value: &chgOtherFin},
 		},
 		CashSummary: &edgar.CashSummarySection{
 			NetCashOperating: &edgar.FSAPValue{Value: &netCashOp},
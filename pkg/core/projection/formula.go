@@ -0,0 +1,458 @@
+package projection
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// =============================================================================
+// FORMULA STRATEGY
+// A small expression language for analyst-defined drivers, e.g.
+//   stores * sales_per_store * (1 + comp)
+//   revenue[t-1] * 1.05
+// Numbers, node IDs, node[t-k] lags, + - * / ^, parentheses and min/max/abs.
+// =============================================================================
+
+// FormulaError is a formula problem at a byte offset (0-based) of the expression
+type FormulaError struct {
+	Pos int
+	Msg string
+}
+
+func (e *FormulaError) Error() string {
+	return fmt.Sprintf("formula: position %d: %s", e.Pos, e.Msg)
+}
+
+// FormulaRef is a node reference in a formula; Lag 0 is the projected year
+type FormulaRef struct {
+	ID  string
+	Lag int
+	Pos int
+}
+
+// Key is how the reference is passed in Context.Drivers ("revenue", "revenue[t-1]")
+func (r FormulaRef) Key() string {
+	if r.Lag == 0 {
+		return r.ID
+	}
+	return fmt.Sprintf("%s[t-%d]", r.ID, r.Lag)
+}
+
+// FormulaStrategy evaluates a parsed expression over other nodes' values
+type FormulaStrategy struct {
+	Expression string `json:"expression"`
+
+	root formulaExpr
+	refs []FormulaRef
+}
+
+// ParseFormula parses an expression into a strategy
+func ParseFormula(expression string) (*FormulaStrategy, error) {
+	p := &formulaParser{src: expression}
+	p.next()
+	root, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+	f := &FormulaStrategy{Expression: expression, root: root}
+	seen := make(map[string]bool)
+	for _, r := range p.refs {
+		if !seen[r.Key()] {
+			seen[r.Key()] = true
+			f.refs = append(f.refs, r)
+		}
+	}
+	return f, nil
+}
+
+func (s *FormulaStrategy) Name() string { return "Formula" }
+
+// References lists every distinct node reference, in order of appearance
+func (s *FormulaStrategy) References() []FormulaRef {
+	return append([]FormulaRef(nil), s.refs...)
+}
+
+// LaggedReferences returns the node[t-k] references
+func (s *FormulaStrategy) LaggedReferences() []FormulaRef {
+	var refs []FormulaRef
+	for _, r := range s.refs {
+		if r.Lag > 0 {
+			refs = append(refs, r)
+		}
+	}
+	return refs
+}
+
+// RequiredDrivers returns the nodes read in the projected year; lagged
+// references read recorded values and add no dependency
+func (s *FormulaStrategy) RequiredDrivers() []string {
+	var ids []string
+	for _, r := range s.refs {
+		if r.Lag == 0 {
+			ids = append(ids, r.ID)
+		}
+	}
+	return ids
+}
+
+func (s *FormulaStrategy) Validate(ctx Context) error {
+	if s.root == nil {
+		return fmt.Errorf("FormulaStrategy has no parsed expression")
+	}
+	for _, r := range s.refs {
+		if _, ok := ctx.Drivers[r.Key()]; !ok {
+			return &FormulaError{Pos: r.Pos, Msg: fmt.Sprintf("no value for '%s'", r.Key())}
+		}
+	}
+	return nil
+}
+
+func (s *FormulaStrategy) Calculate(ctx Context) (float64, error) {
+	if err := s.Validate(ctx); err != nil {
+		return 0, err
+	}
+	return s.root.eval(ctx.Drivers)
+}
+
+// ValidateFormula checks that every reference is a skeleton node or a driver registered on the skeleton
+func (s *StandardSkeleton) ValidateFormula(f *FormulaStrategy) error {
+	for _, r := range f.refs {
+		if !s.HasNode(r.ID) {
+			return &FormulaError{Pos: r.Pos, Msg: fmt.Sprintf("unknown node '%s'", r.ID)}
+		}
+	}
+	return nil
+}
+
+// HasNode reports whether id names a skeleton node or a registered driver
+// (a bare "volume" also matches "auto_volume")
+func (s *StandardSkeleton) HasNode(id string) bool {
+	if IsSkeletonID(id) {
+		return true
+	}
+	if s == nil {
+		return false
+	}
+	_, ok := s.Drivers[id]
+	if !ok {
+		_, ok = s.Drivers["auto_"+id]
+	}
+	return ok
+}
+
+// =============================================================================
+// EXPRESSION TREE
+// =============================================================================
+
+type formulaExpr interface {
+	eval(values map[string]float64) (float64, error)
+}
+
+type numberExpr float64
+
+func (e numberExpr) eval(map[string]float64) (float64, error) { return float64(e), nil }
+
+type refExpr struct{ ref FormulaRef }
+
+func (e refExpr) eval(values map[string]float64) (float64, error) {
+	v, ok := values[e.ref.Key()]
+	if !ok {
+		return 0, &FormulaError{Pos: e.ref.Pos, Msg: fmt.Sprintf("no value for '%s'", e.ref.Key())}
+	}
+	return v, nil
+}
+
+type negExpr struct{ x formulaExpr }
+
+func (e negExpr) eval(values map[string]float64) (float64, error) {
+	v, err := e.x.eval(values)
+	return -v, err
+}
+
+type binaryExpr struct {
+	op   byte
+	pos  int
+	l, r formulaExpr
+}
+
+func (e binaryExpr) eval(values map[string]float64) (float64, error) {
+	l, err := e.l.eval(values)
+	if err != nil {
+		return 0, err
+	}
+	r, err := e.r.eval(values)
+	if err != nil {
+		return 0, err
+	}
+	switch e.op {
+	case '+':
+		return l + r, nil
+	case '-':
+		return l - r, nil
+	case '*':
+		return l * r, nil
+	case '/':
+		if r == 0 {
+			return 0, &FormulaError{Pos: e.pos, Msg: "division by zero"}
+		}
+		return l / r, nil
+	default: // '^'
+		v := math.Pow(l, r)
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return 0, &FormulaError{Pos: e.pos, Msg: fmt.Sprintf("%g ^ %g is undefined", l, r)}
+		}
+		return v, nil
+	}
+}
+
+type callExpr struct {
+	fn   string
+	args []formulaExpr
+}
+
+// formulaFuncs are the callable functions and their argument counts (-1 = one or more)
+var formulaFuncs = map[string]int{"min": -1, "max": -1, "abs": 1}
+
+func (e callExpr) eval(values map[string]float64) (float64, error) {
+	args := make([]float64, len(e.args))
+	for i, a := range e.args {
+		v, err := a.eval(values)
+		if err != nil {
+			return 0, err
+		}
+		args[i] = v
+	}
+	switch e.fn {
+	case "abs":
+		return math.Abs(args[0]), nil
+	case "min":
+		out := args[0]
+		for _, v := range args[1:] {
+			out = math.Min(out, v)
+		}
+		return out, nil
+	default: // "max"
+		out := args[0]
+		for _, v := range args[1:] {
+			out = math.Max(out, v)
+		}
+		return out, nil
+	}
+}
+
+// =============================================================================
+// PARSER
+// sum     = product { ("+" | "-") product }
+// product = unary { ("*" | "/") unary }
+// unary   = "-" unary | power
+// power   = primary [ "^" unary ]
+// primary = number | ident [ "[" "t" [ "-" integer ] "]" ] | ident "(" sum { "," sum } ")" | "(" sum ")"
+// =============================================================================
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of formula"
+	}
+	return fmt.Sprintf("'%s'", t.text)
+}
+
+type formulaParser struct {
+	src  string
+	off  int
+	tok  token
+	refs []FormulaRef
+}
+
+func (p *formulaParser) errorf(format string, args ...interface{}) *FormulaError {
+	return &FormulaError{Pos: p.tok.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *formulaParser) next() {
+	for p.off < len(p.src) && (p.src[p.off] == ' ' || p.src[p.off] == '\t') {
+		p.off++
+	}
+	start := p.off
+	if p.off >= len(p.src) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return
+	}
+	c := p.src[p.off]
+	switch {
+	case c >= '0' && c <= '9' || c == '.':
+		for p.off < len(p.src) && (isDigit(p.src[p.off]) || p.src[p.off] == '.') {
+			p.off++
+		}
+		p.tok = token{kind: tokNumber, text: p.src[start:p.off], pos: start}
+	case isIdentStart(c):
+		for p.off < len(p.src) && (isIdentStart(p.src[p.off]) || isDigit(p.src[p.off])) {
+			p.off++
+		}
+		p.tok = token{kind: tokIdent, text: p.src[start:p.off], pos: start}
+	default: // Operators and punctuation; anything else fails where it is expected
+		p.off++
+		p.tok = token{kind: tokOp, text: string(c), pos: start}
+	}
+}
+
+func isDigit(c byte) bool      { return c >= '0' && c <= '9' }
+func isIdentStart(c byte) bool { return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+
+func (p *formulaParser) is(op string) bool {
+	return p.tok.kind == tokOp && p.tok.text == op
+}
+
+func (p *formulaParser) expect(op string) error {
+	if !p.is(op) {
+		return p.errorf("expected '%s', found %s", op, p.tok)
+	}
+	p.next()
+	return nil
+}
+
+func (p *formulaParser) parseSum() (formulaExpr, error) {
+	l, err := p.parseProduct()
+	for err == nil && (p.is("+") || p.is("-")) {
+		op := binaryExpr{op: p.tok.text[0], pos: p.tok.pos, l: l}
+		p.next()
+		if op.r, err = p.parseProduct(); err == nil {
+			l = op
+		}
+	}
+	return l, err
+}
+
+func (p *formulaParser) parseProduct() (formulaExpr, error) {
+	l, err := p.parseUnary()
+	for err == nil && (p.is("*") || p.is("/")) {
+		op := binaryExpr{op: p.tok.text[0], pos: p.tok.pos, l: l}
+		p.next()
+		if op.r, err = p.parseUnary(); err == nil {
+			l = op
+		}
+	}
+	return l, err
+}
+
+func (p *formulaParser) parseUnary() (formulaExpr, error) {
+	if p.is("-") {
+		p.next()
+		x, err := p.parseUnary()
+		return negExpr{x}, err
+	}
+	return p.parsePower()
+}
+
+func (p *formulaParser) parsePower() (formulaExpr, error) {
+	base, err := p.parsePrimary()
+	if err != nil || !p.is("^") {
+		return base, err
+	}
+	op := binaryExpr{op: '^', pos: p.tok.pos, l: base}
+	p.next()
+	op.r, err = p.parseUnary() // Right-associative: 2^3^2 = 2^9
+	return op, err
+}
+
+func (p *formulaParser) parsePrimary() (formulaExpr, error) {
+	tok := p.tok
+	switch {
+	case tok.kind == tokNumber:
+		v, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number '%s'", tok.text)
+		}
+		p.next()
+		return numberExpr(v), nil
+
+	case tok.kind == tokIdent:
+		p.next()
+		if p.is("(") {
+			return p.parseCall(tok)
+		}
+		ref := FormulaRef{ID: tok.text, Pos: tok.pos}
+		if p.is("[") {
+			lag, err := p.parseLag()
+			if err != nil {
+				return nil, err
+			}
+			ref.Lag = lag
+		}
+		p.refs = append(p.refs, ref)
+		return refExpr{ref}, nil
+
+	case p.is("("):
+		p.next()
+		x, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		return x, p.expect(")")
+	}
+	return nil, p.errorf("expected a number, node or '(', found %s", tok)
+}
+
+// parseLag reads "[t]" or "[t-k]" after a node ID
+func (p *formulaParser) parseLag() (int, error) {
+	p.next()
+	if p.tok.kind != tokIdent || p.tok.text != "t" {
+		return 0, p.errorf("expected 't' in a year reference, found %s", p.tok)
+	}
+	p.next()
+	lag := 0
+	if p.is("-") {
+		p.next()
+		n, err := strconv.Atoi(p.tok.text)
+		if p.tok.kind != tokNumber || err != nil || n < 1 {
+			return 0, p.errorf("expected a positive whole number of years, found %s", p.tok)
+		}
+		lag = n
+		p.next()
+	}
+	return lag, p.expect("]")
+}
+
+func (p *formulaParser) parseCall(name token) (formulaExpr, error) {
+	arity, ok := formulaFuncs[name.text]
+	if !ok {
+		return nil, &FormulaError{Pos: name.pos, Msg: fmt.Sprintf("unknown function '%s'", name.text)}
+	}
+	p.next()
+	call := callExpr{fn: name.text}
+	for {
+		arg, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+		if !p.is(",") {
+			break
+		}
+		p.next()
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if arity > 0 && len(call.args) != arity {
+		return nil, &FormulaError{Pos: name.pos, Msg: fmt.Sprintf("%s takes %d argument(s), got %d", name.text, arity, len(call.args))}
+	}
+	return call, nil
+}
@@ -0,0 +1,591 @@
+package projection
+
+import (
+	"agentic_valuation/pkg/core/edgar"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// =============================================================================
+// SKELETON GRAPH
+// Nodes whose strategy was chosen by the AI or a user, and their drivers, are
+// evaluated as a dependency DAG; their values become the engine's drivers.
+// Nodes left on the SYSTEM default keep the engine's formulas. The graph works
+// on its own copy of the node values: the skeleton itself is never written.
+// =============================================================================
+
+// maxGraphIterations bounds the evaluate -> project passes of one year
+const maxGraphIterations = 10
+
+// DrivableNodeIDs are the skeleton nodes a strategy may set. Subtotals, balances
+// and the cash plug stay accounting identities computed by the engine.
+var DrivableNodeIDs = []string{
+	"revenue", "cogs", "sga", "selling_marketing", "general_admin", "rd",
+	"capex", "dso", "dsi", "dpo",
+}
+
+// CycleError reports a circular dependency between nodes
+type CycleError struct {
+	Path []string // First node repeated at the end
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("skeleton graph has a cycle: %s", strings.Join(e.Path, " -> "))
+}
+
+// NodeGraph is the evaluation order of a skeleton's nodes
+type NodeGraph struct {
+	nodes      map[string]*Node
+	strategies map[string]ProjectionStrategy // Evaluated nodes only (nil for entered drivers)
+	deps       map[string][]dependency       // Evaluated nodes only
+	lags       map[string][]dependency       // Prior-year inputs of formulas (no ordering constraint)
+	values     map[string]map[int]float64    // Working copy of node values by year
+	order      []string
+	warnings   []string
+}
+
+// dependency is one input of a node: the node it reads, its key in Context.Drivers
+// and, for a formula's node[t-k], how many years back
+type dependency struct {
+	id  string
+	key string
+	lag int
+}
+
+// IsDrivableNode reports whether a strategy may set the skeleton node
+func IsDrivableNode(id string) bool {
+	for _, d := range DrivableNodeIDs {
+		if d == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Overridden reports whether the node's strategy was set by the AI or a user
+func (n *Node) Overridden() bool {
+	return n.Type == NodeTypeSkeleton && n.UpdatedBy != "SYSTEM" && (n.Strategy != nil || n.StrategyName != "")
+}
+
+// AddDriver attaches a driver to a skeleton node and registers it for evaluation
+func (s *StandardSkeleton) AddDriver(parentID string, driver *Node) error {
+	parent, ok := s.GetAllNodes()[parentID]
+	if !ok || parent == nil {
+		return fmt.Errorf("node '%s' not found in skeleton", parentID)
+	}
+	if err := parent.AttachDriver(driver); err != nil {
+		return err
+	}
+	if s.Drivers == nil {
+		s.Drivers = make(map[string]*Node)
+	}
+	s.Drivers[driver.ID] = driver
+	return nil
+}
+
+// Graph resolves every node's dependencies and sorts them topologically. A
+// strategy on a node the engine computes is skipped with a warning.
+func (s *StandardSkeleton) Graph() (*NodeGraph, error) {
+	g := &NodeGraph{
+		nodes:      make(map[string]*Node),
+		strategies: make(map[string]ProjectionStrategy),
+		deps:       make(map[string][]dependency),
+		lags:       make(map[string][]dependency),
+		values:     make(map[string]map[int]float64),
+	}
+	for id, n := range s.GetAllNodes() {
+		if n != nil {
+			g.nodes[id] = n
+		}
+	}
+	for id, n := range s.Drivers {
+		if n == nil {
+			continue
+		}
+		if _, clash := g.nodes[id]; clash {
+			return nil, fmt.Errorf("driver '%s' shadows a skeleton node", id)
+		}
+		g.nodes[id] = n
+	}
+
+	for _, id := range g.ids() {
+		n := g.nodes[id]
+		values := make(map[int]float64, len(n.Values))
+		for y, v := range n.Values {
+			values[y] = v
+		}
+		g.values[id] = values
+
+		if n.Type == NodeTypeSkeleton && !n.Overridden() {
+			continue
+		}
+		if n.Type == NodeTypeSkeleton && !IsDrivableNode(id) {
+			g.warnings = append(g.warnings, fmt.Sprintf("node '%s' is computed by the engine; its %s strategy is ignored", id, n.StrategyName))
+			continue
+		}
+		strategy, err := strategyFor(n)
+		if err != nil {
+			return nil, err
+		}
+		g.strategies[id] = strategy
+		if f, ok := strategy.(*FormulaStrategy); ok {
+			if err := s.ValidateFormula(f); err != nil {
+				return nil, fmt.Errorf("node '%s': %w", id, err)
+			}
+		}
+		if l, ok := strategy.(laggedStrategy); ok {
+			if g.lags[id], err = g.lagged(id, l.LaggedReferences()); err != nil {
+				return nil, err
+			}
+		}
+		deps, err := g.resolve(n, strategy)
+		if err != nil {
+			return nil, err
+		}
+		g.deps[id] = deps
+	}
+
+	order, err := g.sort()
+	if err != nil {
+		return nil, err
+	}
+	g.order = order
+	return g, nil
+}
+
+// Order returns the node IDs in evaluation order
+func (g *NodeGraph) Order() []string {
+	return append([]string(nil), g.order...)
+}
+
+// Warnings lists the strategies the graph skipped
+func (g *NodeGraph) Warnings() []string {
+	return append([]string(nil), g.warnings...)
+}
+
+// HasOverrides reports whether any skeleton node is driven by the graph
+func (g *NodeGraph) HasOverrides() bool {
+	for id := range g.deps {
+		if g.nodes[id].Type == NodeTypeSkeleton {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *NodeGraph) ids() []string {
+	ids := make([]string, 0, len(g.nodes))
+	for id := range g.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// resolve lists a node's inputs: attached drivers, subscriptions and the
+// strategy's required drivers ("volume" resolves to a node "volume" or "auto_volume")
+func (g *NodeGraph) resolve(n *Node, strategy ProjectionStrategy) ([]dependency, error) {
+	var deps []dependency
+	seen := make(map[string]bool)
+	add := func(id, key string) error {
+		if _, ok := g.nodes[id]; !ok {
+			return fmt.Errorf("node '%s' depends on '%s', which is not in the graph", n.ID, id)
+		}
+		if !seen[key] {
+			seen[key] = true
+			deps = append(deps, dependency{id: id, key: key})
+		}
+		return nil
+	}
+	for _, id := range append(append([]string(nil), n.DriverIDs...), n.SubscribesTo...) {
+		if err := add(id, strings.TrimPrefix(id, "auto_")); err != nil {
+			return nil, err
+		}
+	}
+
+	if strategy == nil {
+		return deps, nil
+	}
+	for _, name := range strategy.RequiredDrivers() {
+		if seen[name] {
+			continue
+		}
+		id := name
+		if _, ok := g.nodes[id]; !ok {
+			id = "auto_" + name
+		}
+		if err := add(id, name); err != nil {
+			return nil, fmt.Errorf("strategy %s: %w", strategy.Name(), err)
+		}
+	}
+	return deps, nil
+}
+
+// laggedStrategy is a strategy that also reads prior-year values of other nodes
+type laggedStrategy interface {
+	LaggedReferences() []FormulaRef // Lag > 0 only
+}
+
+// lagged resolves a node's prior-year references ("volume" to "volume" or "auto_volume")
+func (g *NodeGraph) lagged(id string, refs []FormulaRef) ([]dependency, error) {
+	var out []dependency
+	for _, r := range refs {
+		ref := r.ID
+		if _, ok := g.nodes[ref]; !ok {
+			ref = "auto_" + r.ID
+		}
+		if _, ok := g.nodes[ref]; !ok {
+			return nil, fmt.Errorf("node '%s' depends on '%s', which is not in the graph", id, r.Key())
+		}
+		out = append(out, dependency{id: ref, key: r.Key(), lag: r.Lag})
+	}
+	return out, nil
+}
+
+// sort orders the evaluated nodes after their inputs, reporting the first cycle found
+func (g *NodeGraph) sort() ([]string, error) {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+	var order, stack []string
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case done:
+			return nil
+		case visiting:
+			start := 0
+			for i, s := range stack {
+				if s == id {
+					start = i
+				}
+			}
+			return &CycleError{Path: append(append([]string(nil), stack[start:]...), id)}
+		}
+		state[id] = visiting
+		stack = append(stack, id)
+		for _, d := range g.deps[id] {
+			if err := visit(d.id); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = done
+		order = append(order, id)
+		return nil
+	}
+	for _, id := range g.ids() {
+		if err := visit(id); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// Evaluate computes the year's value of every node in order. Overridden skeleton
+// nodes and drivers with a strategy are calculated; drivers without one read the
+// entered value for the year, and every other node its recorded value.
+func (g *NodeGraph) Evaluate(year int) (map[string]float64, error) {
+	values := make(map[string]float64, len(g.order))
+	for _, id := range g.order {
+		deps, evaluated := g.deps[id]
+		if !evaluated {
+			values[id] = g.values[id][year]
+			continue
+		}
+		strategy := g.strategies[id]
+		if strategy == nil {
+			v, entered := g.values[id][year]
+			if !entered {
+				return nil, fmt.Errorf("driver '%s' has no value for %d and no strategy", id, year)
+			}
+			values[id] = v
+			continue
+		}
+
+		ctx := Context{
+			Year:           year,
+			LastYearValue:  g.values[id][year-1],
+			HistoricalData: make(map[int]float64),
+			Drivers:        make(map[string]float64, len(deps)),
+		}
+		for y, v := range g.values[id] {
+			if y < year {
+				ctx.HistoricalData[y] = v
+			}
+		}
+		for _, d := range deps {
+			ctx.Drivers[d.key] = values[d.id]
+		}
+		for _, d := range g.lags[id] {
+			if v, ok := g.values[d.id][year-d.lag]; ok {
+				ctx.Drivers[d.key] = v
+			}
+		}
+		v, err := strategy.Calculate(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("node '%s' (%d): %w", id, year, err)
+		}
+		values[id] = v
+	}
+	return values, nil
+}
+
+// storeDrivers keeps the year's calculated driver values for the following years
+func (g *NodeGraph) storeDrivers(year int, values map[string]float64) {
+	for id := range g.deps {
+		if g.nodes[id].Type == NodeTypeDriver {
+			g.values[id][year] = values[id]
+		}
+	}
+}
+
+// yearValues collects every node's value for the year
+func (g *NodeGraph) yearValues(year int) map[string]float64 {
+	out := make(map[string]float64)
+	for id, values := range g.values {
+		if v, ok := values[year]; ok {
+			out[id] = v
+		}
+	}
+	return out
+}
+
+// strategyFor returns the node's strategy, instantiating a named one with its
+// parameters (the node is left unchanged)
+func strategyFor(n *Node) (ProjectionStrategy, error) {
+	if n.Strategy != nil || n.StrategyName == "" {
+		return n.Strategy, nil
+	}
+	strategy, err := NewStrategySelector().buildStrategy(n.StrategyName, n.StrategyParams, n.Formula)
+	if err != nil {
+		return nil, fmt.Errorf("node '%s': %w", n.ID, err)
+	}
+	return strategy, nil
+}
+
+// applyStrategyParams sets a built-in strategy's parameters from Node.StrategyParams (JSON field names)
+func applyStrategyParams(strategy ProjectionStrategy, params map[string]float64) {
+	set := func(field *float64, name string) {
+		if v, ok := params[name]; ok {
+			*field = v
+		}
+	}
+	switch s := strategy.(type) {
+	case *GrowthStrategy:
+		set(&s.GrowthRate, "growth_rate")
+	case *PriceVolumeStrategy:
+		set(&s.PriceGrowth, "price_growth")
+		set(&s.VolumeGrowth, "volume_growth")
+	case *UnitCostStrategy:
+		set(&s.UnitCostGrowth, "unit_cost_growth")
+	case *MarginStrategy:
+		set(&s.MarginPercent, "margin_percent")
+		if s.BaseNodeID == "" {
+			s.BaseNodeID = "revenue"
+		}
+	case *SCurveStrategy:
+		set(&s.Saturation, "saturation")
+		set(&s.Steepness, "steepness")
+	case *MeanReversionStrategy:
+		set(&s.TargetMean, "target_mean")
+		set(&s.FadeYears, "fade_years")
+	case *CohortStrategy:
+		for age := 0; ; age++ {
+			v, ok := params[fmt.Sprintf("retention_%d", age)]
+			if !ok {
+				break
+			}
+			s.Retention = append(s.Retention, v)
+		}
+	case *BacklogStrategy:
+		set(&s.ConversionRate, "conversion_rate")
+	}
+}
+
+// applyGraph turns the graph's values for driven nodes into engine drivers.
+// Expense nodes hold positive amounts; ratios are taken on the year's revenue.
+func applyGraph(g *NodeGraph, values map[string]float64, prevIS *edgar.IncomeStatement, prevSegments []edgar.StandardizedSegment, a ProjectionAssumptions) (ProjectionAssumptions, error) {
+	a = a.Clone()
+	driven := func(id string) (float64, bool) {
+		if _, ok := g.deps[id]; !ok || g.nodes[id].Type != NodeTypeSkeleton {
+			return 0, false
+		}
+		return values[id], true
+	}
+
+	rev, _ := projectRevenue(prevIS, prevSegments, a)
+	if v, ok := driven("revenue"); ok {
+		prevRev := getValue(prevIS.GrossProfitSection.Revenues)
+		if prevRev == 0 {
+			return a, fmt.Errorf("node 'revenue' cannot drive growth without prior revenue")
+		}
+		a.RevenueGrowth = v/prevRev - 1
+		a.SegmentGrowth = nil
+		rev = v
+	}
+	ratio := func(id string, field *float64) error {
+		v, ok := driven(id)
+		if !ok {
+			return nil
+		}
+		if rev == 0 {
+			return fmt.Errorf("node '%s' cannot be taken as a share of zero revenue", id)
+		}
+		*field = v / rev
+		return nil
+	}
+
+	_, sga := driven("sga")
+	_, selling := driven("selling_marketing")
+	_, admin := driven("general_admin")
+	if sga && (selling || admin) {
+		return a, fmt.Errorf("node 'sga' and its components cannot all be driven")
+	}
+	if sga {
+		a.SellingMarketingPercent, a.GeneralAdminPercent = 0, 0 // The components take priority over SGAPercent
+	}
+	for id, field := range map[string]*float64{
+		"cogs":              &a.COGSPercent,
+		"sga":               &a.SGAPercent,
+		"selling_marketing": &a.SellingMarketingPercent,
+		"general_admin":     &a.GeneralAdminPercent,
+		"rd":                &a.RDPercent,
+		"capex":             &a.CapexPercent,
+	} {
+		if err := ratio(id, field); err != nil {
+			return a, err
+		}
+	}
+
+	// Days drivers replace the percentage method
+	if v, ok := driven("dso"); ok {
+		a.DSO, a.ReceivablesPercent = v, 0
+	}
+	if v, ok := driven("dsi"); ok {
+		a.DSI, a.InventoryPercent = v, 0
+	}
+	if v, ok := driven("dpo"); ok {
+		a.DPO, a.AccountsPayablePercent = v, 0
+	}
+	return a, nil
+}
+
+// sameValues reports whether two evaluations of the driven nodes agree
+func (g *NodeGraph) sameValues(prev, next map[string]float64) bool {
+	if prev == nil {
+		return false
+	}
+	for id := range g.deps {
+		if math.Abs(prev[id]-next[id]) > 1e-9*math.Max(1, math.Abs(next[id])) {
+			return false
+		}
+	}
+	return true
+}
+
+// statementValues maps statements onto skeleton node IDs (expenses as positive amounts)
+func statementValues(is *edgar.IncomeStatement, bs *edgar.BalanceSheet, cf *edgar.CashFlowStatement) map[string]float64 {
+	v := make(map[string]float64)
+	var rev, cogs float64
+	if is != nil {
+		if gp := is.GrossProfitSection; gp != nil {
+			rev, cogs = getValue(gp.Revenues), math.Abs(getValue(gp.CostOfGoodsSold))
+			v["revenue"], v["cogs"], v["gross_profit"] = rev, cogs, getValue(gp.GrossProfit)
+		}
+		if op := is.OperatingCostSection; op != nil {
+			v["sga"] = math.Abs(getValue(op.SGAExpenses))
+			v["selling_marketing"] = math.Abs(getValue(op.SellingMarketing))
+			v["general_admin"] = math.Abs(getValue(op.GeneralAdmin))
+			v["rd"] = math.Abs(getValue(op.RDExpenses))
+			v["other_operating"] = math.Abs(getValue(op.OtherOperatingExpenses))
+			v["operating_income"] = getValue(op.OperatingIncome)
+		}
+		if nonOp := is.NonOperatingSection; nonOp != nil {
+			v["interest_expense"] = -getValue(nonOp.InterestExpense) // Net, negative = expense
+			v["income_before_tax"] = getValue(nonOp.IncomeBeforeTax)
+		}
+		if tax := is.TaxAdjustments; tax != nil {
+			v["tax_expense"] = -getValue(tax.IncomeTaxExpense)
+		}
+		if ni := is.NetIncomeSection; ni != nil {
+			v["net_income"] = getValue(ni.NetIncomeToCommon)
+		}
+	}
+
+	if bs != nil {
+		ca, nca := bs.CurrentAssets, bs.NoncurrentAssets
+		cl, ncl, eq := bs.CurrentLiabilities, bs.NoncurrentLiabilities, bs.Equity
+		v["cash"] = getValue(ca.CashAndEquivalents)
+		v["short_term_investments"] = getValue(ca.ShortTermInvestments)
+		v["accounts_receivable"] = getValue(ca.AccountsReceivableNet)
+		v["inventory"] = getValue(ca.Inventories)
+		v["other_current_assets"] = getValue(ca.OtherCurrentAssets)
+		v["total_current_assets"] = total(ca.CalculatedTotal)
+		v["ppe_at_cost"] = getValue(nca.PPEAtCost)
+		v["accumulated_depreciation"] = math.Abs(getValue(nca.AccumulatedDepreciation))
+		v["ppe_net"] = getValue(nca.PPENet)
+		v["goodwill"] = getValue(nca.Goodwill)
+		v["intangibles"] = getValue(nca.Intangibles)
+		v["long_term_investments"] = getValue(nca.LongTermInvestments)
+		v["deferred_tax_assets"] = getValue(nca.DeferredTaxAssetsLT)
+		v["other_non_current_assets"] = getValue(nca.OtherNoncurrentAssets)
+		v["total_non_current_assets"] = total(nca.CalculatedTotal)
+		v["total_assets"] = v["total_current_assets"] + v["total_non_current_assets"]
+		v["accounts_payable"] = getValue(cl.AccountsPayable)
+		v["accrued_liabilities"] = getValue(cl.AccruedLiabilities)
+		v["short_term_debt"] = getValue(cl.NotesPayableShortTermDebt)
+		v["other_current_liabilities"] = getValue(cl.OtherCurrentLiabilities)
+		v["total_current_liabilities"] = total(cl.CalculatedTotal)
+		v["long_term_debt"] = getValue(ncl.LongTermDebt)
+		v["deferred_tax_liabilities"] = getValue(ncl.DeferredTaxLiabilities)
+		v["other_non_current_liabilities"] = getValue(ncl.OtherNoncurrentLiabilities)
+		v["total_non_current_liabilities"] = total(ncl.CalculatedTotal)
+		v["total_liabilities"] = v["total_current_liabilities"] + v["total_non_current_liabilities"]
+		v["common_stock"] = getValue(eq.CommonStockAPIC)
+		v["preferred_stock"] = getValue(eq.PreferredStock)
+		v["retained_earnings"] = getValue(eq.RetainedEarningsDeficit)
+		v["treasury_stock"] = getValue(eq.TreasuryStock)
+		v["aoci"] = getValue(eq.AccumOtherComprehensiveIncome)
+		v["minority_interest"] = getValue(eq.NoncontrollingInterests)
+		v["total_equity"] = total(eq.CalculatedTotal)
+
+		if rev != 0 {
+			v["dso"] = v["accounts_receivable"] / rev * 365
+		}
+		if cogs != 0 {
+			v["dsi"] = v["inventory"] / cogs * 365
+			v["dpo"] = v["accounts_payable"] / cogs * 365
+		}
+	}
+
+	if cf != nil {
+		if op := cf.OperatingActivities; op != nil {
+			v["depreciation"] = getValue(op.DepreciationAmortization)
+		}
+		if inv := cf.InvestingActivities; inv != nil {
+			v["capex"] = math.Abs(getValue(inv.Capex))
+		}
+	}
+	return v
+}
+
+// record writes a year's statements onto the graph's skeleton node values; with
+// keep, values already set (e.g. entered history) are left alone
+func (g *NodeGraph) record(year int, is *edgar.IncomeStatement, bs *edgar.BalanceSheet, cf *edgar.CashFlowStatement, keep bool) {
+	for id, v := range statementValues(is, bs, cf) {
+		n := g.nodes[id]
+		if n == nil || n.Type != NodeTypeSkeleton {
+			continue
+		}
+		if _, set := g.values[id][year]; keep && set {
+			continue
+		}
+		g.values[id][year] = v
+	}
+}
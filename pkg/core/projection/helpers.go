@@ -2,6 +2,8 @@ package projection
 
 import (
 	"agentic_valuation/pkg/core/edgar"
+	"sort"
+	"strings"
 )
 
 // Helper to safely unpack value
@@ -11,3 +13,117 @@ func getValue(v *edgar.FSAPValue) float64 {
 	}
 	return 0
 }
+
+// flat copies a value into a new FSAPValue (nil stays nil so absent lines stay absent)
+func flat(v *edgar.FSAPValue) *edgar.FSAPValue {
+	if v == nil || v.Value == nil {
+		return nil
+	}
+	x := *v.Value
+	return &edgar.FSAPValue{Value: &x}
+}
+
+// carriedLines are balance sheet lines the engine has no driver for
+type carriedLines struct {
+	FinanceDivLoansST, FinanceDivOtherCurrAsset, OtherAssets                          *edgar.FSAPValue
+	DeferredChargesLT, FinanceDivLoansLT, FinanceDivOtherLTAssets, RestrictedCash     *edgar.FSAPValue
+	CurrentMaturitiesLTD, CurrentOperatingLeaseLiab, FinanceDivCurr                   *edgar.FSAPValue
+	LongTermOperatingLeaseLiab, PensionObligations, FinanceDivNoncurr, PreferredStock *edgar.FSAPValue
+}
+
+// carryForwardLines holds the undriven lines of the prior balance sheet flat
+func carryForwardLines(prev *edgar.BalanceSheet) carriedLines {
+	return carriedLines{
+		FinanceDivLoansST:          flat(prev.CurrentAssets.FinanceDivLoansST),
+		FinanceDivOtherCurrAsset:   flat(prev.CurrentAssets.FinanceDivOtherCurrAsset),
+		OtherAssets:                flat(prev.CurrentAssets.OtherAssets),
+		DeferredChargesLT:          flat(prev.NoncurrentAssets.DeferredChargesLT),
+		FinanceDivLoansLT:          flat(prev.NoncurrentAssets.FinanceDivLoansLT),
+		FinanceDivOtherLTAssets:    flat(prev.NoncurrentAssets.FinanceDivOtherLTAssets),
+		RestrictedCash:             flat(prev.NoncurrentAssets.RestrictedCash),
+		CurrentMaturitiesLTD:       flat(prev.CurrentLiabilities.CurrentMaturitiesLTD),
+		CurrentOperatingLeaseLiab:  flat(prev.CurrentLiabilities.CurrentOperatingLeaseLiab),
+		FinanceDivCurr:             flat(prev.CurrentLiabilities.FinanceDivCurr),
+		LongTermOperatingLeaseLiab: flat(prev.NoncurrentLiabilities.LongTermOperatingLeaseLiab),
+		PensionObligations:         flat(prev.NoncurrentLiabilities.PensionObligations),
+		FinanceDivNoncurr:          flat(prev.NoncurrentLiabilities.FinanceDivNoncurr),
+		PreferredStock:             flat(prev.Equity.PreferredStock),
+	}
+}
+
+// uses sums the carried assets
+func (c carriedLines) uses() float64 {
+	return getValue(c.FinanceDivLoansST) + getValue(c.FinanceDivOtherCurrAsset) + getValue(c.OtherAssets) +
+		getValue(c.DeferredChargesLT) + getValue(c.FinanceDivLoansLT) + getValue(c.FinanceDivOtherLTAssets) +
+		getValue(c.RestrictedCash)
+}
+
+// sources sums the carried liabilities and equity
+func (c carriedLines) sources() float64 {
+	return getValue(c.CurrentMaturitiesLTD) + getValue(c.CurrentOperatingLeaseLiab) + getValue(c.FinanceDivCurr) +
+		getValue(c.LongTermOperatingLeaseLiab) + getValue(c.PensionObligations) + getValue(c.FinanceDivNoncurr) +
+		getValue(c.PreferredStock)
+}
+
+// apply writes the carried lines onto a projected balance sheet
+func (c carriedLines) apply(bs *edgar.BalanceSheet) {
+	bs.CurrentAssets.FinanceDivLoansST = c.FinanceDivLoansST
+	bs.CurrentAssets.FinanceDivOtherCurrAsset = c.FinanceDivOtherCurrAsset
+	bs.CurrentAssets.OtherAssets = c.OtherAssets
+	bs.NoncurrentAssets.DeferredChargesLT = c.DeferredChargesLT
+	bs.NoncurrentAssets.FinanceDivLoansLT = c.FinanceDivLoansLT
+	bs.NoncurrentAssets.FinanceDivOtherLTAssets = c.FinanceDivOtherLTAssets
+	bs.NoncurrentAssets.RestrictedCash = c.RestrictedCash
+	bs.CurrentLiabilities.CurrentMaturitiesLTD = c.CurrentMaturitiesLTD
+	bs.CurrentLiabilities.CurrentOperatingLeaseLiab = c.CurrentOperatingLeaseLiab
+	bs.CurrentLiabilities.FinanceDivCurr = c.FinanceDivCurr
+	bs.NoncurrentLiabilities.LongTermOperatingLeaseLiab = c.LongTermOperatingLeaseLiab
+	bs.NoncurrentLiabilities.PensionObligations = c.PensionObligations
+	bs.NoncurrentLiabilities.FinanceDivNoncurr = c.FinanceDivNoncurr
+	bs.Equity.PreferredStock = c.PreferredStock
+}
+
+// rollAdditionalItems carries a section's additional items forward and sets the
+// ones addressed by a NodeDriver key (e.g. "BS-CA: Prepaid") to % of revenue
+func rollAdditionalItems(prev []edgar.FSAPValue, drivers map[string]float64, prefix string, projRev float64) []edgar.FSAPValue {
+	var out []edgar.FSAPValue
+	index := make(map[string]int, len(prev))
+	for _, item := range prev {
+		if item.Value == nil {
+			continue
+		}
+		x := *item.Value
+		index[item.Label] = len(out)
+		out = append(out, edgar.FSAPValue{Label: item.Label, Value: &x})
+	}
+
+	keys := make([]string, 0, len(drivers))
+	for key := range drivers {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		label := strings.TrimSpace(key[len(prefix):])
+		v := projRev * drivers[key]
+		if i, ok := index[label]; ok {
+			out[i].Value = &v
+			continue
+		}
+		index[label] = len(out)
+		out = append(out, edgar.FSAPValue{Label: label, Value: &v})
+	}
+	return out
+}
+
+// sumItems totals a section's additional items
+func sumItems(items []edgar.FSAPValue) float64 {
+	total := 0.0
+	for _, item := range items {
+		if item.Value != nil {
+			total += *item.Value
+		}
+	}
+	return total
+}
@@ -0,0 +1,179 @@
+package projection
+
+import (
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/validate"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// DefaultArticulationTolerance is the largest gap (in statement units) accepted by the horizon checks
+const DefaultArticulationTolerance = 0.01
+
+// History is the T-0 base a forecast rolls forward from
+type History struct {
+	IncomeStatement *edgar.IncomeStatement
+	BalanceSheet    *edgar.BalanceSheet
+	Segments        []edgar.StandardizedSegment // Optional: SOTP revenue build
+	FiscalYear      int                         // Last actual year (T-0)
+}
+
+// DriverSchedule sets named drivers year by year (e.g. from assumption.Node.YearlyValues).
+// Index 0 is the first projected year; a path shorter than the horizon holds its last value.
+type DriverSchedule map[string][]float64
+
+// HorizonInput describes a multi-year projection
+type HorizonInput struct {
+	History     History
+	Assumptions ProjectionAssumptions // Drivers for every year unless scheduled
+	Schedule    DriverSchedule        // Optional per-year overrides by driver name
+	Years       int
+	Tolerance   float64 // Articulation tolerance (default DefaultArticulationTolerance)
+}
+
+// ArticulationError names the first projected year and line that does not tie
+type ArticulationError struct {
+	Year     int
+	Line     string
+	Expected float64
+	Actual   float64
+}
+
+func (e *ArticulationError) Error() string {
+	return fmt.Sprintf("projection %d does not articulate: %s is %.4f, expected %.4f (difference %.4f)",
+		e.Year, e.Line, e.Actual, e.Expected, e.Actual-e.Expected)
+}
+
+// Articulation lines checked each year
+const (
+	LineBalanceSheet = "Total Assets vs Liabilities + Equity"
+	LineCashFlow     = "CFO + CFI + CFF vs Net Change in Cash"
+	LineCashBegin    = "Beginning Cash vs Prior Year Cash"
+	LineCashEnd      = "Ending Cash vs Balance Sheet Cash"
+)
+
+// Validate checks the history can be projected
+func (h History) Validate() error {
+	if h.IncomeStatement == nil || h.BalanceSheet == nil {
+		return fmt.Errorf("projection requires history income statement and balance sheet")
+	}
+	if h.IncomeStatement.GrossProfitSection == nil {
+		return fmt.Errorf("history income statement is missing the gross profit section")
+	}
+	return nil
+}
+
+// AssumptionsFor returns the base assumptions with every scheduled driver set to its value for year index i
+func (s DriverSchedule) AssumptionsFor(base ProjectionAssumptions, i int) (ProjectionAssumptions, error) {
+	a := base.Clone()
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := s[name]
+		if len(path) == 0 {
+			continue
+		}
+		idx := i
+		if idx >= len(path) {
+			idx = len(path) - 1
+		}
+		if err := a.SetDriver(name, path[idx]); err != nil {
+			return a, err
+		}
+	}
+	return a, nil
+}
+
+// ProjectHorizon rolls the history forward year by year, applying the schedule and
+// checking that each year's statements articulate. The first failing check is
+// returned as an *ArticulationError (or a *ConvergenceError when the year's interest
+// did not converge) together with the years projected so far.
+func (e *ProjectionEngine) ProjectHorizon(in HorizonInput) ([]*ProjectedFinancials, error) {
+	if err := in.History.Validate(); err != nil {
+		return nil, err
+	}
+	if in.Years <= 0 {
+		return nil, fmt.Errorf("projection horizon must be at least one year")
+	}
+	tol := in.Tolerance
+	if tol <= 0 {
+		tol = DefaultArticulationTolerance
+	}
+
+	path := make([]*ProjectedFinancials, 0, in.Years)
+	prevIS, prevBS, prevSegments := in.History.IncomeStatement, in.History.BalanceSheet, in.History.Segments
+	var vintages []PPEVintage    // Closing PP&E layers carried into the next year
+	var taxes *TaxRoll           // Closing NOLs carried into the next year
+	var shares *ShareRoll        // Closing share count carried into the next year
+	graph, graphErr := e.graph() // One working copy of the skeleton's values for the whole horizon
+	for y := 0; y < in.Years; y++ {
+		a, err := in.Schedule.AssumptionsFor(in.Assumptions, y)
+		if err != nil {
+			return path, fmt.Errorf("year %d: %w", in.History.FiscalYear+y+1, err)
+		}
+		if a.FixedAssets != nil && y > 0 {
+			a.FixedAssets.Vintages = vintages
+		}
+		if a.Tax != nil && taxes != nil {
+			a.Tax.carryNOLs(taxes)
+		}
+		if a.Shares != nil && shares != nil {
+			a.Shares.carryShares(shares)
+		}
+		proj := e.projectGraphYear(graph, graphErr, prevIS, prevBS, prevSegments, a, in.History.FiscalYear+y+1)
+		if proj.FixedAssets != nil {
+			vintages = proj.FixedAssets.Vintages
+		}
+		taxes, shares = proj.Tax, proj.Shares
+		path = append(path, proj)
+		if proj.GraphError != nil {
+			return path, fmt.Errorf("year %d: %w", proj.Year, proj.GraphError)
+		}
+		if proj.Interest != nil && !proj.Interest.Converged {
+			return path, &ConvergenceError{Year: proj.Year, Iterations: proj.Interest.Iterations, Residual: proj.Interest.Residual}
+		}
+		if err := CheckArticulation(prevBS, proj, tol); err != nil {
+			return path, err
+		}
+		prevIS, prevBS, prevSegments = proj.IncomeStatement, proj.BalanceSheet, proj.Segments
+	}
+	return path, nil
+}
+
+// CheckArticulation verifies A = L + E and the cash roll from the prior balance sheet
+func CheckArticulation(prevBS *edgar.BalanceSheet, proj *ProjectedFinancials, tolerance float64) error {
+	bs := proj.BalanceSheet
+	assets := total(bs.CurrentAssets.CalculatedTotal) + total(bs.NoncurrentAssets.CalculatedTotal)
+	liabilities := total(bs.CurrentLiabilities.CalculatedTotal) + total(bs.NoncurrentLiabilities.CalculatedTotal)
+	equity := total(bs.Equity.CalculatedTotal)
+	if check := validate.CheckBalanceEquation(assets, liabilities, equity, tolerance); !check.IsBalanced {
+		return &ArticulationError{Year: proj.Year, Line: LineBalanceSheet, Expected: check.ComputedAssets, Actual: check.TotalAssets}
+	}
+
+	summary := proj.CashFlow.CashSummary
+	cfo, cfi, cff := getValue(summary.NetCashOperating), getValue(summary.NetCashInvesting), getValue(summary.NetCashFinancing)
+	if check := validate.CheckCashFlowEquation(cfo, cfi, cff, getValue(summary.NetChangeInCash), tolerance); !check.IsBalanced {
+		return &ArticulationError{Year: proj.Year, Line: LineCashFlow, Expected: check.ReportedTotal, Actual: check.ComputedTotal}
+	}
+
+	prevCash := getValue(prevBS.CurrentAssets.CashAndEquivalents)
+	if begin := getValue(summary.CashBeginning); math.Abs(begin-prevCash) > tolerance {
+		return &ArticulationError{Year: proj.Year, Line: LineCashBegin, Expected: prevCash, Actual: begin}
+	}
+	cash := getValue(bs.CurrentAssets.CashAndEquivalents)
+	if end := getValue(summary.CashBeginning) + cfo + cfi + cff; math.Abs(end-cash) > tolerance {
+		return &ArticulationError{Year: proj.Year, Line: LineCashEnd, Expected: cash, Actual: end}
+	}
+	return nil
+}
+
+func total(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
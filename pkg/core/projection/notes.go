@@ -0,0 +1,18 @@
+package projection
+
+import "agentic_valuation/pkg/core/edgar"
+
+// ApplyNotes seeds the term debt schedule and the opening NOL carryforwards from a
+// filing's debt and income tax notes. Whatever the notes do not cover keeps its
+// current setting; unrated tranches take DebtInterestRate.
+func (a *ProjectionAssumptions) ApplyNotes(notes []*edgar.ExtractedNote, bs *edgar.BalanceSheet, fiscalYear int) {
+	if len(DebtTranchesFromNotes(notes, fiscalYear)) > 0 && bs != nil {
+		a.DebtSchedule = NewDebtSchedule(notes, bs, fiscalYear, a.DebtInterestRate)
+	}
+	if jurisdictions := TaxJurisdictionsFromNotes(notes); len(jurisdictions) > 0 {
+		if a.Tax == nil {
+			a.Tax = &TaxPolicy{}
+		}
+		a.Tax.Jurisdictions = jurisdictions
+	}
+}
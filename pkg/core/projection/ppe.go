@@ -0,0 +1,264 @@
+package projection
+
+import (
+	"agentic_valuation/pkg/core/edgar"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// DefaultAssetLife is the depreciable life (years) used when no life or rate driver is set
+const DefaultAssetLife = 10.0
+
+// PPEVintage is one layer of gross PP&E depreciated straight-line
+type PPEVintage struct {
+	Year        int     `json:"year"` // Fiscal year placed in service (0 = in service at T-0)
+	Cost        float64 `json:"cost"`
+	Accumulated float64 `json:"accumulated"` // Accumulated depreciation and impairment (positive)
+	Annual      float64 `json:"annual"`      // Straight-line charge per full year
+}
+
+// Net is the layer's net book value
+func (v PPEVintage) Net() float64 {
+	return v.Cost - v.Accumulated
+}
+
+// FixedAssetPolicy replaces the gross-PP&E depreciation rate with a vintage waterfall:
+// the T-0 base depreciates over its remaining life and every year's capex forms a
+// new layer depreciated over its own life.
+type FixedAssetPolicy struct {
+	ExistingLife           float64         // Remaining life (years) of the T-0 net book value (0 = gross cost over the asset life)
+	NewLife                float64         // Life of each capex vintage (0 = UsefulLifeForecast, 1 / DepreciationPercent, or DefaultAssetLife)
+	HalfYear               bool            // Capex takes half a year of depreciation in the year placed in service
+	DisposalPercent        float64         // Share of opening gross cost sold each year at net book value (oldest layers first)
+	Impairments            map[int]float64 // Write-downs of net book value by fiscal year (oldest layers first)
+	RetireFullyDepreciated bool            // Remove fully depreciated layers from cost and accumulated depreciation
+	Vintages               []PPEVintage    // Opening layers (nil = one layer seeded from the prior balance sheet)
+}
+
+// PPERoll is the fixed-asset waterfall of one projected year
+type PPERoll struct {
+	Year                 int             `json:"year"`
+	OpeningCost          float64         `json:"opening_cost"`
+	OpeningAccumulated   float64         `json:"opening_accumulated"`
+	Capex                float64         `json:"capex"` // Positive
+	DisposalsCost        float64         `json:"disposals_cost"`
+	DisposalsAccumulated float64         `json:"disposals_accumulated"`
+	DisposalProceeds     float64         `json:"disposal_proceeds"` // Net book value sold (no gain or loss)
+	Impairment           float64         `json:"impairment"`
+	Depreciation         float64         `json:"depreciation"`
+	Retirements          float64         `json:"retirements"` // Fully depreciated cost written off
+	ClosingCost          float64         `json:"closing_cost"`
+	ClosingAccumulated   float64         `json:"closing_accumulated"`
+	DepreciationByYear   map[int]float64 `json:"depreciation_by_vintage"` // Vintage year (0 = T-0 base) -> charge
+	Vintages             []PPEVintage    `json:"vintages"`                // Closing layers
+}
+
+// ClosingNet is the closing net book value
+func (r PPERoll) ClosingNet() float64 {
+	return r.ClosingCost - r.ClosingAccumulated
+}
+
+// Clone deep-copies the policy
+func (p *FixedAssetPolicy) Clone() *FixedAssetPolicy {
+	if p == nil {
+		return nil
+	}
+	out := *p
+	out.Vintages = append([]PPEVintage(nil), p.Vintages...)
+	if p.Impairments != nil {
+		out.Impairments = make(map[int]float64, len(p.Impairments))
+		for y, v := range p.Impairments {
+			out.Impairments[y] = v
+		}
+	}
+	return &out
+}
+
+// assetLife is the depreciable life implied by the engine's depreciation drivers
+func assetLife(a ProjectionAssumptions) float64 {
+	switch {
+	case a.UsefulLifeForecast > 0:
+		return a.UsefulLifeForecast
+	case a.DepreciationPercent > 0:
+		return 1 / a.DepreciationPercent
+	default:
+		return DefaultAssetLife
+	}
+}
+
+// opening returns the policy's layers, seeding a single T-0 layer from the balance sheet when unset
+func (p *FixedAssetPolicy) opening(prevBS *edgar.BalanceSheet, a ProjectionAssumptions) []PPEVintage {
+	if p.Vintages != nil {
+		return append([]PPEVintage(nil), p.Vintages...)
+	}
+	cost := getValue(prevBS.NoncurrentAssets.PPEAtCost)
+	accumulated := math.Abs(getValue(prevBS.NoncurrentAssets.AccumulatedDepreciation))
+	if cost == 0 {
+		cost, accumulated = getValue(prevBS.NoncurrentAssets.PPENet), 0
+	}
+	if cost <= 0 {
+		return nil
+	}
+	base := PPEVintage{Cost: cost, Accumulated: accumulated, Annual: cost / assetLife(a)}
+	if p.ExistingLife > 0 {
+		base.Annual = base.Net() / p.ExistingLife
+	}
+	return []PPEVintage{base}
+}
+
+// Roll runs the waterfall for one year: disposals, impairment, depreciation of the
+// existing layers, the new capex vintage, then retirements
+func (p *FixedAssetPolicy) Roll(prevBS *edgar.BalanceSheet, a ProjectionAssumptions, capex float64, year int) PPERoll {
+	layers := p.opening(prevBS, a)
+	roll := PPERoll{Year: year, Capex: capex, DepreciationByYear: make(map[int]float64)}
+	for _, v := range layers {
+		roll.OpeningCost += v.Cost
+		roll.OpeningAccumulated += v.Accumulated
+	}
+
+	// Disposals at net book value, oldest first
+	toDispose := roll.OpeningCost * p.DisposalPercent
+	for i := range layers {
+		if toDispose <= 0 {
+			break
+		}
+		v := &layers[i]
+		take := math.Min(toDispose, v.Cost)
+		if v.Cost <= 0 || take <= 0 {
+			continue
+		}
+		share := take / v.Cost
+		roll.DisposalsCost += take
+		roll.DisposalsAccumulated += v.Accumulated * share
+		roll.DisposalProceeds += v.Net() * share
+		v.Cost -= take
+		v.Accumulated -= v.Accumulated * share
+		v.Annual -= v.Annual * share
+		toDispose -= take
+	}
+
+	// Impairment of net book value, oldest first; the remaining life is unchanged
+	toImpair := p.Impairments[year]
+	for i := range layers {
+		if toImpair <= 0 {
+			break
+		}
+		v := &layers[i]
+		net := v.Net()
+		hit := math.Min(toImpair, net)
+		if hit <= 0 {
+			continue
+		}
+		v.Annual *= (net - hit) / net
+		v.Accumulated += hit
+		roll.Impairment += hit
+		toImpair -= hit
+	}
+
+	// Depreciation of the existing layers
+	for i := range layers {
+		v := &layers[i]
+		charge := math.Min(v.Annual, v.Net())
+		if charge <= 0 {
+			continue
+		}
+		v.Accumulated += charge
+		roll.Depreciation += charge
+		roll.DepreciationByYear[v.Year] += charge
+	}
+
+	// This year's capex vintage
+	if capex > 0 {
+		life := p.NewLife
+		if life <= 0 {
+			life = assetLife(a)
+		}
+		v := PPEVintage{Year: year, Cost: capex, Annual: capex / life}
+		charge := v.Annual
+		if p.HalfYear {
+			charge /= 2
+		}
+		v.Accumulated = math.Min(charge, v.Cost)
+		roll.Depreciation += v.Accumulated
+		roll.DepreciationByYear[year] += v.Accumulated
+		layers = append(layers, v)
+	}
+
+	// Retire fully depreciated layers
+	for _, v := range layers {
+		if p.RetireFullyDepreciated && v.Net() <= 1e-9 {
+			roll.Retirements += v.Cost
+			continue
+		}
+		roll.Vintages = append(roll.Vintages, v)
+		roll.ClosingCost += v.Cost
+		roll.ClosingAccumulated += v.Accumulated
+	}
+	return roll
+}
+
+// FixedAssetMarkdown renders the waterfall of every projected year that ran one
+func FixedAssetMarkdown(path []*ProjectedFinancials) string {
+	var rolls []*PPERoll
+	for _, p := range path {
+		if p.FixedAssets != nil {
+			rolls = append(rolls, p.FixedAssets)
+		}
+	}
+	if len(rolls) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("**PP&E Roll-Forward**\n\n| Line |")
+	for _, r := range rolls {
+		fmt.Fprintf(&sb, " %d |", r.Year)
+	}
+	sb.WriteString("\n|---|")
+	sb.WriteString(strings.Repeat("---|", len(rolls)))
+	sb.WriteString("\n")
+
+	row := func(name string, value func(r *PPERoll) float64) {
+		fmt.Fprintf(&sb, "| %s |", name)
+		for _, r := range rolls {
+			fmt.Fprintf(&sb, " %.1f |", value(r))
+		}
+		sb.WriteString("\n")
+	}
+	row("Opening Gross PP&E", func(r *PPERoll) float64 { return r.OpeningCost })
+	row("+ Capex", func(r *PPERoll) float64 { return r.Capex })
+	row("- Disposals (cost)", func(r *PPERoll) float64 { return r.DisposalsCost })
+	row("- Retirements", func(r *PPERoll) float64 { return r.Retirements })
+	row("Closing Gross PP&E", func(r *PPERoll) float64 { return r.ClosingCost })
+	row("Opening Accumulated Depreciation", func(r *PPERoll) float64 { return r.OpeningAccumulated })
+	row("+ Depreciation", func(r *PPERoll) float64 { return r.Depreciation })
+	row("+ Impairment", func(r *PPERoll) float64 { return r.Impairment })
+	row("- Disposals (accumulated)", func(r *PPERoll) float64 { return r.DisposalsAccumulated })
+	row("- Retirements (accumulated)", func(r *PPERoll) float64 { return r.Retirements })
+	row("Closing Accumulated Depreciation", func(r *PPERoll) float64 { return r.ClosingAccumulated })
+	row("Net PP&E", func(r *PPERoll) float64 { return r.ClosingNet() })
+
+	// Depreciation by vintage
+	vintages := make(map[int]bool)
+	for _, r := range rolls {
+		for y := range r.DepreciationByYear {
+			vintages[y] = true
+		}
+	}
+	years := make([]int, 0, len(vintages))
+	for y := range vintages {
+		years = append(years, y)
+	}
+	sort.Ints(years)
+	for _, y := range years {
+		name := fmt.Sprintf("D&A: %d vintage", y)
+		if y == 0 {
+			name = "D&A: existing base"
+		}
+		vintage := y
+		row(name, func(r *PPERoll) float64 { return r.DepreciationByYear[vintage] })
+	}
+	return sb.String()
+}
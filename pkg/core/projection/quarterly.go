@@ -0,0 +1,458 @@
+package projection
+
+import (
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/synthesis"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// =============================================================================
+// QUARTERLY PROJECTIONS
+// Each quarter is one ProjectYear step on quarterly drivers: the fiscal year's
+// revenue follows the annual growth and is spread over its quarters by
+// seasonality; balances, rates and lives are restated for a quarter.
+// =============================================================================
+
+// QuartersPerYear is the number of fiscal quarters in a fiscal year
+const QuartersPerYear = 4
+
+// QuarterStatements are one fiscal quarter's reported or projected statements
+// (three-month flows, quarter-end balance sheet)
+type QuarterStatements struct {
+	FiscalYear      int
+	Quarter         int // 1-4
+	IncomeStatement *edgar.IncomeStatement
+	BalanceSheet    *edgar.BalanceSheet
+	CashFlow        *edgar.CashFlowStatement
+}
+
+// Seasonality is each quarter's share of fiscal-year revenue (index 0 = Q1)
+type Seasonality [QuartersPerYear]float64
+
+// EvenSeasonality spreads revenue evenly over the quarters
+var EvenSeasonality = Seasonality{0.25, 0.25, 0.25, 0.25}
+
+// QuarterlyInput describes a quarterly projection
+type QuarterlyInput struct {
+	History     []QuarterStatements   // Reported quarters, oldest first; the last one is T-0
+	Assumptions ProjectionAssumptions // Annual drivers (growth is fiscal-year over fiscal-year)
+	Schedule    DriverSchedule        // Optional annual overrides; index 0 is the first projected fiscal year
+	Seasonality *Seasonality          // nil = SeasonalityFromHistory
+	Quarters    int
+	Tolerance   float64 // Articulation tolerance (default DefaultArticulationTolerance)
+}
+
+// QuarterlyHistoryFromGolden reads the record's quarters oldest first. A missing
+// Q4 is derived when Q1-Q3 are present: its income statement as the fiscal year
+// less Q1-Q3, its cash flows as the fiscal year less the nine-month year to date.
+func QuarterlyHistoryFromGolden(record *synthesis.GoldenRecord) []QuarterStatements {
+	if record == nil {
+		return nil
+	}
+	var out []QuarterStatements
+	years := make(map[int]bool)
+	for _, q := range record.Quarters {
+		is := q.IncomeStatement
+		qs := QuarterStatements{FiscalYear: q.FiscalYear, Quarter: q.Quarter, IncomeStatement: &is}
+		if q.BalanceSheet != nil {
+			bs := *q.BalanceSheet
+			qs.BalanceSheet = &bs
+		}
+		if q.CashFlowStatement != nil {
+			cf := *q.CashFlowStatement
+			qs.CashFlow = &cf
+		}
+		out = append(out, qs)
+		years[q.FiscalYear] = true
+	}
+
+	for fy := range years {
+		annual, ok := record.Timeline[fy]
+		if !ok || record.Quarters[synthesis.QuarterKey(fy, 4)] != nil {
+			continue
+		}
+		parts := []*synthesis.QuarterlySnapshot{record.Quarters[synthesis.QuarterKey(fy, 1)], record.Quarters[synthesis.QuarterKey(fy, 2)], record.Quarters[synthesis.QuarterKey(fy, 3)]}
+		if parts[0] == nil || parts[1] == nil || parts[2] == nil {
+			continue
+		}
+		is, cf := annual.IncomeStatement, annual.CashFlowStatement
+		isParts := []interface{}{&annual.IncomeStatement}
+		for _, p := range parts {
+			isParts = append(isParts, &p.IncomeStatement)
+		}
+		q4IS := synthesis.CombineFlows(isParts, []float64{1, -1, -1, -1}).(*edgar.IncomeStatement)
+		if ni := q4IS.NetIncomeSection; ni != nil && is.NetIncomeSection != nil {
+			restateEPS(ni, shareCounts(is.NetIncomeSection))
+		}
+		nineMonths := &parts[2].CashFlowYearToDate
+		q4CF := synthesis.CombineFlows([]interface{}{&annual.CashFlowStatement, nineMonths}, []float64{1, -1}).(*edgar.CashFlowStatement)
+		if sum := q4CF.CashSummary; sum != nil && cf.CashSummary != nil {
+			sum.CashEnding = flat(cf.CashSummary.CashEnding)
+			sum.CashBeginning = nil
+			if q3 := nineMonths.CashSummary; q3 != nil {
+				sum.CashBeginning = flat(q3.CashEnding)
+			}
+		}
+		bs := annual.BalanceSheet
+		out = append(out, QuarterStatements{FiscalYear: fy, Quarter: 4, IncomeStatement: q4IS, BalanceSheet: &bs, CashFlow: q4CF})
+	}
+
+	sortQuarters(out)
+	return out
+}
+
+func sortQuarters(qs []QuarterStatements) {
+	sort.Slice(qs, func(i, j int) bool {
+		if qs[i].FiscalYear != qs[j].FiscalYear {
+			return qs[i].FiscalYear < qs[j].FiscalYear
+		}
+		return qs[i].Quarter < qs[j].Quarter
+	})
+}
+
+// SeasonalityFromHistory averages each quarter's share of revenue over the fiscal
+// years with all four quarters reported (EvenSeasonality without one)
+func SeasonalityFromHistory(history []QuarterStatements) Seasonality {
+	byYear := make(map[int]*[QuartersPerYear]float64)
+	counts := make(map[int]int)
+	for _, q := range history {
+		if q.Quarter < 1 || q.Quarter > QuartersPerYear || q.IncomeStatement == nil || q.IncomeStatement.GrossProfitSection == nil {
+			continue
+		}
+		if byYear[q.FiscalYear] == nil {
+			byYear[q.FiscalYear] = &[QuartersPerYear]float64{}
+		}
+		byYear[q.FiscalYear][q.Quarter-1] = getValue(q.IncomeStatement.GrossProfitSection.Revenues)
+		counts[q.FiscalYear]++
+	}
+
+	var out Seasonality
+	years := 0
+	for fy, revs := range byYear {
+		total := revs[0] + revs[1] + revs[2] + revs[3]
+		if counts[fy] != QuartersPerYear || total <= 0 {
+			continue
+		}
+		for i, r := range revs {
+			out[i] += r / total
+		}
+		years++
+	}
+	if years == 0 {
+		return EvenSeasonality
+	}
+	for i := range out {
+		out[i] /= float64(years)
+	}
+	return out
+}
+
+// quarterAssumptions restates annual drivers for a three-month period: balances
+// driven by revenue or costs see a quarter's flow, so days and balance ratios are
+// scaled up; rates are per quarter and asset lives are counted in quarters
+func quarterAssumptions(a ProjectionAssumptions) ProjectionAssumptions {
+	q := a.Clone()
+	const n = QuartersPerYear
+	q.DSO, q.DSI, q.DPO = a.DSO*n, a.DSI*n, a.DPO*n
+	q.ReceivablesPercent *= n
+	q.InventoryPercent *= n
+	q.AccountsPayablePercent *= n
+	q.DeferredRevenuePercent *= n
+	for key := range q.NodeDrivers {
+		if strings.HasPrefix(key, "BS-") {
+			q.NodeDrivers[key] *= n
+		}
+	}
+	q.UsefulLifeForecast *= n
+	q.DepreciationPercent /= n
+	q.CashInterestRate /= n
+	q.DebtInterestRate /= n
+	q.RevolverInterestRate /= n
+	q.PreTaxCostOfDebt /= n // Debt rate fallback when DebtInterestRate is not set
+	q.SegmentGrowth = nil   // Revenue is set per quarter from the fiscal-year target
+	return q
+}
+
+// ProjectQuarters rolls the reported quarters forward one quarter at a time. A
+// fiscal year's revenue grows at the year's RevenueGrowth over the prior fiscal
+// year; the part not yet reported or projected is spread over the remaining
+// quarters by seasonality. Each quarter is checked for articulation. Term debt,
+// PP&E vintage, tax and share policies are annual and are not supported, and the
+// skeleton (keyed by fiscal year) is not updated.
+func (e *ProjectionEngine) ProjectQuarters(in QuarterlyInput) ([]*ProjectedFinancials, error) {
+	if len(in.History) == 0 {
+		return nil, fmt.Errorf("quarterly projection requires reported quarters")
+	}
+	history := append([]QuarterStatements(nil), in.History...)
+	sortQuarters(history)
+	last := history[len(history)-1]
+	if err := (History{IncomeStatement: last.IncomeStatement, BalanceSheet: last.BalanceSheet}).Validate(); err != nil {
+		return nil, fmt.Errorf("quarter %s: %w", synthesis.QuarterKey(last.FiscalYear, last.Quarter), err)
+	}
+	if in.Quarters <= 0 {
+		return nil, fmt.Errorf("quarterly projection must cover at least one quarter")
+	}
+	a := in.Assumptions
+	if a.DebtSchedule != nil || a.FixedAssets != nil || a.Tax != nil || a.Shares != nil {
+		return nil, fmt.Errorf("quarterly projection does not support debt, fixed asset, tax or share policies")
+	}
+	season := SeasonalityFromHistory(history)
+	if in.Seasonality != nil {
+		season = *in.Seasonality
+	}
+	tol := in.Tolerance
+	if tol <= 0 {
+		tol = DefaultArticulationTolerance
+	}
+	quarterly := &ProjectionEngine{InterestTolerance: e.InterestTolerance, MaxInterestIterations: e.MaxInterestIterations}
+
+	// Revenue by fiscal year and quarter, reported then projected
+	revenue := make(map[int]*[QuartersPerYear]float64)
+	known := make(map[int]*[QuartersPerYear]bool)
+	setRevenue := func(fy, q int, v float64) {
+		if revenue[fy] == nil {
+			revenue[fy], known[fy] = &[QuartersPerYear]float64{}, &[QuartersPerYear]bool{}
+		}
+		revenue[fy][q-1], known[fy][q-1] = v, true
+	}
+	for _, h := range history {
+		if h.IncomeStatement != nil && h.IncomeStatement.GrossProfitSection != nil {
+			setRevenue(h.FiscalYear, h.Quarter, getValue(h.IncomeStatement.GrossProfitSection.Revenues))
+		}
+	}
+
+	path := make([]*ProjectedFinancials, 0, in.Quarters)
+	prevIS, prevBS := last.IncomeStatement, last.BalanceSheet
+	fy, q := last.FiscalYear, last.Quarter
+	firstFY := fy
+	if q == QuartersPerYear {
+		firstFY++
+	}
+	for i := 0; i < in.Quarters; i++ {
+		if q++; q > QuartersPerYear {
+			fy, q = fy+1, 1
+		}
+		annual, err := in.Schedule.AssumptionsFor(a, fy-firstFY)
+		if err != nil {
+			return path, fmt.Errorf("quarter %s: %w", synthesis.QuarterKey(fy, q), err)
+		}
+
+		// The fiscal year's revenue target, less what is already in place
+		prior, ok := fullYear(revenue, known, fy-1)
+		if !ok {
+			prior = trailingRevenue(revenue, known, fy, q, season)
+		}
+		remaining := prior * (1 + annual.RevenueGrowth)
+		share := 0.0
+		for j := 1; j <= QuartersPerYear; j++ {
+			if revenue[fy] != nil && known[fy][j-1] {
+				remaining -= revenue[fy][j-1]
+			} else if j >= q {
+				share += season[j-1]
+			}
+		}
+		target := remaining
+		if share > 0 {
+			target = remaining * season[q-1] / share
+		}
+
+		prevRev := getValue(prevIS.GrossProfitSection.Revenues)
+		if prevRev == 0 {
+			return path, fmt.Errorf("quarter %s: the prior quarter has no revenue to grow from", synthesis.QuarterKey(fy, q))
+		}
+		qa := quarterAssumptions(annual)
+		qa.RevenueGrowth = target/prevRev - 1
+
+		proj := quarterly.ProjectYear(prevIS, prevBS, nil, qa, fy)
+		proj.Quarter = q
+		path = append(path, proj)
+		if proj.Interest != nil && !proj.Interest.Converged {
+			err := &ConvergenceError{Year: fy, Iterations: proj.Interest.Iterations, Residual: proj.Interest.Residual}
+			return path, fmt.Errorf("quarter %s: %w", synthesis.QuarterKey(fy, q), err)
+		}
+		if err := CheckArticulation(prevBS, proj, tol); err != nil {
+			return path, fmt.Errorf("quarter %s: %w", synthesis.QuarterKey(fy, q), err)
+		}
+		setRevenue(fy, q, getValue(proj.IncomeStatement.GrossProfitSection.Revenues))
+		prevIS, prevBS = proj.IncomeStatement, proj.BalanceSheet
+	}
+	return path, nil
+}
+
+// fullYear sums a fiscal year's revenue when all four quarters are in place
+func fullYear(revenue map[int]*[QuartersPerYear]float64, known map[int]*[QuartersPerYear]bool, fy int) (float64, bool) {
+	if revenue[fy] == nil {
+		return 0, false
+	}
+	total := 0.0
+	for j := 0; j < QuartersPerYear; j++ {
+		if !known[fy][j] {
+			return 0, false
+		}
+		total += revenue[fy][j]
+	}
+	return total, true
+}
+
+// trailingRevenue annualizes the most recent quarter before (fy, q) by its
+// seasonal share, for a prior fiscal year that is not fully reported
+func trailingRevenue(revenue map[int]*[QuartersPerYear]float64, known map[int]*[QuartersPerYear]bool, fy, q int, season Seasonality) float64 {
+	for {
+		if q--; q < 1 {
+			fy, q = fy-1, QuartersPerYear
+		}
+		if revenue[fy] == nil {
+			return 0
+		}
+		if known[fy][q-1] && season[q-1] > 0 {
+			return revenue[fy][q-1] / season[q-1]
+		}
+	}
+}
+
+// QuarterStatementsOf lists projected quarters as quarter statements
+func QuarterStatementsOf(path []*ProjectedFinancials) []QuarterStatements {
+	out := make([]QuarterStatements, 0, len(path))
+	for _, p := range path {
+		if p.Quarter == 0 {
+			continue
+		}
+		out = append(out, QuarterStatements{FiscalYear: p.Year, Quarter: p.Quarter, IncomeStatement: p.IncomeStatement, BalanceSheet: p.BalanceSheet, CashFlow: p.CashFlow})
+	}
+	return out
+}
+
+// RollupFiscalYears combines every fiscal year with all four quarters present
+// (reported, projected or both) into annual statements: flows are summed, the
+// balance sheet is Q4's and cash begins at Q1's opening balance
+func RollupFiscalYears(quarters []QuarterStatements) []*ProjectedFinancials {
+	sorted := append([]QuarterStatements(nil), quarters...)
+	sortQuarters(sorted)
+	byYear := make(map[int][]QuarterStatements)
+	var years []int
+	for _, q := range sorted {
+		if _, seen := byYear[q.FiscalYear]; !seen {
+			years = append(years, q.FiscalYear)
+		}
+		byYear[q.FiscalYear] = append(byYear[q.FiscalYear], q)
+	}
+
+	var out []*ProjectedFinancials
+	for _, fy := range years {
+		qs := byYear[fy]
+		if len(qs) != QuartersPerYear {
+			continue
+		}
+		complete := true
+		for i, q := range qs {
+			complete = complete && q.Quarter == i+1
+		}
+		if complete {
+			p := sumQuarters(qs)
+			p.Quarter = 0
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// LTM sums the four quarters ending with the given one (last twelve months)
+func LTM(quarters []QuarterStatements, fiscalYear, quarter int) (*ProjectedFinancials, error) {
+	return window(quarters, fiscalYear, quarter, 0)
+}
+
+// NTM sums the four quarters after the given one (next twelve months)
+func NTM(quarters []QuarterStatements, fiscalYear, quarter int) (*ProjectedFinancials, error) {
+	return window(quarters, fiscalYear, quarter, QuartersPerYear)
+}
+
+// window sums four consecutive quarters ending shift quarters after (fy, q)
+func window(quarters []QuarterStatements, fy, q, shift int) (*ProjectedFinancials, error) {
+	index := make(map[string]QuarterStatements, len(quarters))
+	for _, qs := range quarters {
+		index[synthesis.QuarterKey(qs.FiscalYear, qs.Quarter)] = qs
+	}
+	end := (fy*QuartersPerYear + q - 1) + shift
+	picked := make([]QuarterStatements, 0, QuartersPerYear)
+	for n := end - QuartersPerYear + 1; n <= end; n++ {
+		key := synthesis.QuarterKey(n/QuartersPerYear, n%QuartersPerYear+1)
+		qs, ok := index[key]
+		if !ok {
+			return nil, fmt.Errorf("twelve months ending %s: quarter %s is missing",
+				synthesis.QuarterKey(end/QuartersPerYear, end%QuartersPerYear+1), key)
+		}
+		picked = append(picked, qs)
+	}
+	return sumQuarters(picked), nil
+}
+
+// sumQuarters adds consecutive quarters: summed flows, the last balance sheet,
+// average weighted shares and cash from the first opening to the last closing
+func sumQuarters(qs []QuarterStatements) *ProjectedFinancials {
+	first, last := qs[0], qs[len(qs)-1]
+	isParts := make([]interface{}, 0, len(qs))
+	cfParts := make([]interface{}, 0, len(qs))
+	signs := make([]float64, 0, len(qs))
+	var shares [2]float64 // Basic, diluted
+	sharePeriods := 0
+	for _, q := range qs {
+		if q.IncomeStatement != nil {
+			isParts = append(isParts, q.IncomeStatement)
+			if ni := q.IncomeStatement.NetIncomeSection; ni != nil && getValue(ni.WeightedAverageShares) > 0 {
+				counts := shareCounts(ni)
+				shares[0] += counts[0]
+				shares[1] += counts[1]
+				sharePeriods++
+			}
+		}
+		if q.CashFlow != nil {
+			cfParts = append(cfParts, q.CashFlow)
+		}
+		signs = append(signs, 1)
+	}
+
+	out := &ProjectedFinancials{Year: last.FiscalYear, Quarter: last.Quarter, BalanceSheet: last.BalanceSheet}
+	if len(isParts) > 0 {
+		out.IncomeStatement = synthesis.CombineFlows(isParts, signs[:len(isParts)]).(*edgar.IncomeStatement)
+		if ni := out.IncomeStatement.NetIncomeSection; ni != nil && sharePeriods > 0 {
+			restateEPS(ni, [2]float64{shares[0] / float64(sharePeriods), shares[1] / float64(sharePeriods)})
+		}
+	}
+	if len(cfParts) > 0 {
+		out.CashFlow = synthesis.CombineFlows(cfParts, signs[:len(cfParts)]).(*edgar.CashFlowStatement)
+		if sum := out.CashFlow.CashSummary; sum != nil {
+			if first.CashFlow != nil && first.CashFlow.CashSummary != nil {
+				sum.CashBeginning = flat(first.CashFlow.CashSummary.CashBeginning)
+			}
+			if last.CashFlow != nil && last.CashFlow.CashSummary != nil {
+				sum.CashEnding = flat(last.CashFlow.CashSummary.CashEnding)
+			}
+		}
+	}
+	return out
+}
+
+// shareCounts returns a period's basic and diluted weighted shares; diluted shares
+// are implied from net income and diluted EPS (basic without one)
+func shareCounts(ni *edgar.NetIncomeSection) [2]float64 {
+	basic := getValue(ni.WeightedAverageShares)
+	diluted := basic
+	if eps := getValue(ni.EPSDiluted); eps != 0 {
+		diluted = getValue(ni.NetIncomeToCommon) / eps
+	}
+	return [2]float64{basic, diluted}
+}
+
+// restateEPS sets the weighted shares and recomputes EPS from the period's net income
+func restateEPS(ni *edgar.NetIncomeSection, shares [2]float64) {
+	if shares[0] <= 0 {
+		return
+	}
+	income := getValue(ni.NetIncomeToCommon)
+	basic, diluted := income/shares[0], income/shares[1]
+	ni.WeightedAverageShares = &edgar.FSAPValue{Value: &shares[0]}
+	ni.EPSBasic = &edgar.FSAPValue{Value: &basic}
+	ni.EPSDiluted = &edgar.FSAPValue{Value: &diluted}
+}
@@ -27,6 +27,11 @@ type StrategyDecision struct {
 	Reasoning           string   `json:"reasoning"`
 	Confidence          float64  `json:"confidence"`
 
+	// Strategy parameters by JSON field name (e.g. "margin_percent"), or the
+	// expression of a "Formula" strategy
+	StrategyParams map[string]float64 `json:"strategy_params,omitempty"`
+	Formula        string             `json:"formula,omitempty"`
+
 	// If drivers need to be created
 	NewDrivers []*Node `json:"new_drivers,omitempty"`
 }
@@ -42,10 +47,15 @@ type StrategySelector struct {
 func NewStrategySelector() *StrategySelector {
 	return &StrategySelector{
 		strategies: map[string]func() ProjectionStrategy{
-			"GrowthRate":  func() ProjectionStrategy { return &GrowthStrategy{} },
-			"PriceVolume": func() ProjectionStrategy { return &PriceVolumeStrategy{} },
-			"UnitCost":    func() ProjectionStrategy { return &UnitCostStrategy{} },
-			"Margin":      func() ProjectionStrategy { return &MarginStrategy{} },
+			"GrowthRate":    func() ProjectionStrategy { return &GrowthStrategy{} },
+			"PriceVolume":   func() ProjectionStrategy { return &PriceVolumeStrategy{} },
+			"UnitCost":      func() ProjectionStrategy { return &UnitCostStrategy{} },
+			"Margin":        func() ProjectionStrategy { return &MarginStrategy{} },
+			"Formula":       func() ProjectionStrategy { return &FormulaStrategy{} },
+			"SCurve":        func() ProjectionStrategy { return &SCurveStrategy{} },
+			"MeanReversion": func() ProjectionStrategy { return &MeanReversionStrategy{} },
+			"Cohort":        func() ProjectionStrategy { return &CohortStrategy{} },
+			"Backlog":       func() ProjectionStrategy { return &BacklogStrategy{} },
 		},
 	}
 }
@@ -164,22 +174,51 @@ func (s *StrategySelector) ApplyDecision(skeleton *StandardSkeleton, decision St
 	if !ok {
 		return fmt.Errorf("node '%s' not found in skeleton", decision.NodeID)
 	}
+	if !IsDrivableNode(decision.NodeID) {
+		return fmt.Errorf("node '%s' is computed by the engine and cannot take a strategy", decision.NodeID)
+	}
 
 	// Create and assign strategy
-	strategy, err := s.CreateStrategy(decision.RecommendedStrategy)
+	strategy, err := s.buildStrategy(decision.RecommendedStrategy, decision.StrategyParams, decision.Formula)
 	if err != nil {
-		return err
+		return fmt.Errorf("node '%s': %w", decision.NodeID, err)
+	}
+	if m, ok := strategy.(*MarginStrategy); ok && len(decision.RequiredDrivers) > 0 {
+		m.BaseNodeID = decision.RequiredDrivers[0]
 	}
-	node.Strategy = strategy
-	node.StrategyName = decision.RecommendedStrategy
-	node.UpdatedBy = "AI"
 
-	// Attach new drivers
+	// Attach new drivers and register them for graph evaluation
 	for _, driver := range decision.NewDrivers {
-		if err := node.AttachDriver(driver); err != nil {
+		if err := skeleton.AddDriver(decision.NodeID, driver); err != nil {
 			return fmt.Errorf("failed to attach driver '%s': %w", driver.ID, err)
 		}
 	}
 
+	// A formula may only read skeleton nodes and registered drivers
+	if f, ok := strategy.(*FormulaStrategy); ok {
+		if err := skeleton.ValidateFormula(f); err != nil {
+			return fmt.Errorf("node '%s': %w", decision.NodeID, err)
+		}
+	}
+	node.Strategy = strategy
+	node.StrategyName = decision.RecommendedStrategy
+	node.StrategyParams = decision.StrategyParams
+	node.Formula = decision.Formula
+	node.UpdatedBy = "AI"
+
 	return nil
 }
+
+// buildStrategy instantiates a named strategy with its parameters, parsing the
+// expression of a formula
+func (s *StrategySelector) buildStrategy(name string, params map[string]float64, formula string) (ProjectionStrategy, error) {
+	strategy, err := s.CreateStrategy(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := strategy.(*FormulaStrategy); ok {
+		return ParseFormula(formula)
+	}
+	applyStrategyParams(strategy, params)
+	return strategy, nil
+}
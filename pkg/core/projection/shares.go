@@ -0,0 +1,183 @@
+package projection
+
+import (
+	"agentic_valuation/pkg/core/edgar"
+	"math"
+)
+
+// DefaultBuybackYears spreads a buyback authorization without a stated period
+const DefaultBuybackYears = 3
+
+// ShareCountPolicy rolls the basic share count forward: buybacks retire shares at
+// the repurchase price, stock-based compensation and option exercises issue them.
+type ShareCountPolicy struct {
+	OpeningShares      float64         // Basic shares at the start of the year (0 = SharesOutstanding)
+	BuybackDollars     map[int]float64 // Repurchase spend by fiscal year (e.g. BuybacksFromGuidance)
+	BuybackPayoutRatio float64         // Share of net income repurchased in years without a dollar budget
+	RepurchasePrice    float64         // Average price paid per share this year (0 = no buybacks)
+	PriceGrowth        float64         // Yearly growth of the repurchase and grant prices
+	SBCGrantPrice      float64         // Price at which SBC expense converts to shares (0 = RepurchasePrice)
+	OptionExercises    map[int]float64 // Shares issued on option exercise by fiscal year
+	OptionStrike       float64         // Weighted-average exercise price (cash received per share)
+}
+
+// ShareRoll is the share count roll-forward of one projected year
+type ShareRoll struct {
+	Year             int     `json:"year"`
+	Opening          float64 `json:"opening"`
+	BuybackSpend     float64 `json:"buyback_spend"`
+	RepurchasePrice  float64 `json:"repurchase_price"`
+	Repurchased      float64 `json:"repurchased"`
+	GrantPrice       float64 `json:"grant_price"`
+	SBCIssued        float64 `json:"sbc_issued"`
+	OptionsExercised float64 `json:"options_exercised"`
+	ExerciseProceeds float64 `json:"exercise_proceeds"`
+	Closing          float64 `json:"closing"`
+	WeightedAverage  float64 `json:"weighted_average"` // Flows are mid-year: the average of opening and closing
+}
+
+// Clone deep-copies the policy
+func (p *ShareCountPolicy) Clone() *ShareCountPolicy {
+	if p == nil {
+		return nil
+	}
+	out := *p
+	out.BuybackDollars = copyYearMap(p.BuybackDollars)
+	out.OptionExercises = copyYearMap(p.OptionExercises)
+	return &out
+}
+
+func copyYearMap(m map[int]float64) map[int]float64 {
+	if m == nil {
+		return nil
+	}
+	out := make(map[int]float64, len(m))
+	for y, v := range m {
+		out[y] = v
+	}
+	return out
+}
+
+// Roll computes the year's share count. Buybacks use the year's dollar budget, or
+// the payout ratio on positive net income; SBC dollars convert at the grant price.
+func (p *ShareCountPolicy) Roll(year int, netIncome, sbc, sharesOutstanding float64) ShareRoll {
+	roll := ShareRoll{Year: year, Opening: p.OpeningShares, RepurchasePrice: p.RepurchasePrice, GrantPrice: p.SBCGrantPrice}
+	if roll.Opening == 0 {
+		roll.Opening = sharesOutstanding
+	}
+	if roll.GrantPrice == 0 {
+		roll.GrantPrice = p.RepurchasePrice
+	}
+
+	if roll.RepurchasePrice > 0 {
+		spend, budgeted := p.BuybackDollars[year]
+		if !budgeted {
+			spend = math.Max(netIncome, 0) * p.BuybackPayoutRatio
+		}
+		// Cannot retire more than the opening count
+		roll.Repurchased = math.Min(math.Max(spend, 0)/roll.RepurchasePrice, roll.Opening)
+		roll.BuybackSpend = roll.Repurchased * roll.RepurchasePrice
+	}
+	if roll.GrantPrice > 0 && sbc > 0 {
+		roll.SBCIssued = sbc / roll.GrantPrice
+	}
+	roll.OptionsExercised = p.OptionExercises[year]
+	roll.ExerciseProceeds = roll.OptionsExercised * p.OptionStrike
+
+	roll.Closing = roll.Opening - roll.Repurchased + roll.SBCIssued + roll.OptionsExercised
+	roll.WeightedAverage = (roll.Opening + roll.Closing) / 2
+	return roll
+}
+
+// carryShares opens the policy at a roll's closing count and grown prices
+func (p *ShareCountPolicy) carryShares(roll *ShareRoll) {
+	p.OpeningShares = roll.Closing
+	p.RepurchasePrice = roll.RepurchasePrice * (1 + p.PriceGrowth)
+	if p.SBCGrantPrice != 0 {
+		p.SBCGrantPrice = roll.GrantPrice * (1 + p.PriceGrowth)
+	}
+}
+
+// shareBasis is what a year's share roll needs besides net income
+type shareBasis struct {
+	policy            *ShareCountPolicy
+	year              int
+	sbc               float64
+	sharesOutstanding float64
+}
+
+// roll runs the policy on the year's net income (nil without a policy)
+func (b *shareBasis) roll(netIncome float64) *ShareRoll {
+	if b == nil {
+		return nil
+	}
+	r := b.policy.Roll(b.year, netIncome, b.sbc, b.sharesOutstanding)
+	return &r
+}
+
+// BuybacksFromGuidance spreads the remaining buyback authorization from the capital
+// allocation analysis evenly over its stated period, starting in firstYear
+func BuybacksFromGuidance(c *edgar.CapitalAnalysis, firstYear int) map[int]float64 {
+	if c == nil || c.BuybackRemaining <= 0 {
+		return nil
+	}
+	years := int(math.Round(c.BuybackPeriodYears))
+	if years <= 0 {
+		years = DefaultBuybackYears
+	}
+	out := make(map[int]float64, years)
+	for i := 0; i < years; i++ {
+		out[firstYear+i] = c.BuybackRemaining / float64(years)
+	}
+	return out
+}
+
+// PerShareValues are one projected year's per-share figures
+type PerShareValues struct {
+	Year              int     `json:"year"`
+	Shares            float64 `json:"shares"` // Weighted average
+	EPS               float64 `json:"eps"`
+	DPS               float64 `json:"dps"`
+	BookValuePerShare float64 `json:"book_value_per_share"` // On closing shares
+}
+
+// PerShareByYear reads per-share values from each projected year, using the share
+// roll when there is one and the income statement's weighted shares otherwise
+func PerShareByYear(path []*ProjectedFinancials) []PerShareValues {
+	out := make([]PerShareValues, 0, len(path))
+	for _, p := range path {
+		v := PerShareValues{Year: p.Year}
+		closing := 0.0
+		if p.Shares != nil {
+			v.Shares, closing = p.Shares.WeightedAverage, p.Shares.Closing
+		} else if p.IncomeStatement != nil && p.IncomeStatement.NetIncomeSection != nil {
+			v.Shares = getValue(p.IncomeStatement.NetIncomeSection.WeightedAverageShares)
+			closing = v.Shares
+		}
+		if v.Shares > 0 {
+			if p.IncomeStatement != nil && p.IncomeStatement.NetIncomeSection != nil {
+				v.EPS = getValue(p.IncomeStatement.NetIncomeSection.NetIncomeToCommon) / v.Shares
+			}
+			if p.CashFlow != nil && p.CashFlow.FinancingActivities != nil {
+				v.DPS = math.Abs(getValue(p.CashFlow.FinancingActivities.DividendsPaid)) / v.Shares
+			}
+		}
+		if closing > 0 && p.BalanceSheet != nil && p.BalanceSheet.Equity.CalculatedTotal != nil {
+			v.BookValuePerShare = *p.BalanceSheet.Equity.CalculatedTotal / closing
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+// ShareRolls returns each year's share roll, or nil unless every year has one
+func ShareRolls(path []*ProjectedFinancials) []ShareRoll {
+	out := make([]ShareRoll, 0, len(path))
+	for _, p := range path {
+		if p.Shares == nil {
+			return nil
+		}
+		out = append(out, *p.Shares)
+	}
+	return out
+}
@@ -88,6 +88,9 @@ type StandardSkeleton struct {
 
 	// CapEx
 	CapEx *Node `json:"capex"`
+
+	// Attached driver nodes by ID (see AddDriver)
+	Drivers map[string]*Node `json:"drivers,omitempty"`
 }
 
 // NewStandardSkeleton creates a skeleton with all fixed nodes initialized
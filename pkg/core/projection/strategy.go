@@ -6,6 +6,7 @@ package projection
 
 import (
 	"fmt"
+	"math"
 	"time"
 )
 
@@ -183,6 +184,143 @@ func (s *SumStrategy) Calculate(ctx Context) (float64, error) {
 	return total, nil
 }
 
+// SCurveStrategy implements logistic adoption toward a saturation level
+// Formula: Value(t) = L / (1 + ((L - Value(t-1)) / Value(t-1)) × e^(-k))
+// Each year moves one step along the logistic curve through last year's value
+type SCurveStrategy struct {
+	Saturation float64 `json:"saturation"` // L, e.g. the addressable market
+	Steepness  float64 `json:"steepness"`  // k, e.g. 0.5 per year
+}
+
+func (s *SCurveStrategy) Name() string { return "SCurve" }
+
+func (s *SCurveStrategy) RequiredDrivers() []string { return nil }
+
+func (s *SCurveStrategy) Validate(ctx Context) error {
+	if s.Saturation <= 0 {
+		return fmt.Errorf("SCurveStrategy requires a positive saturation level")
+	}
+	if ctx.LastYearValue <= 0 {
+		return fmt.Errorf("SCurveStrategy requires a positive LastYearValue")
+	}
+	return nil
+}
+
+func (s *SCurveStrategy) Calculate(ctx Context) (float64, error) {
+	if err := s.Validate(ctx); err != nil {
+		return 0, err
+	}
+	gap := (s.Saturation - ctx.LastYearValue) / ctx.LastYearValue
+	return s.Saturation / (1 + gap*math.Exp(-s.Steepness)), nil
+}
+
+// meanReversionResidual is the share of the gap to the mean left after FadeYears
+const meanReversionResidual = 0.05
+
+// MeanReversionStrategy fades a ratio exponentially toward an industry mean
+// Formula: Value(t) = Mean + (Value(t-1) - Mean) × 0.05^(1/FadeYears)
+// 95% of the gap closes over FadeYears
+type MeanReversionStrategy struct {
+	TargetMean float64 `json:"target_mean"` // e.g., 0.12 industry EBIT margin
+	FadeYears  float64 `json:"fade_years"`  // e.g., 5
+}
+
+func (s *MeanReversionStrategy) Name() string { return "MeanReversion" }
+
+func (s *MeanReversionStrategy) RequiredDrivers() []string { return nil }
+
+func (s *MeanReversionStrategy) Validate(ctx Context) error {
+	if s.FadeYears <= 0 {
+		return fmt.Errorf("MeanReversionStrategy requires positive FadeYears")
+	}
+	return nil
+}
+
+func (s *MeanReversionStrategy) Calculate(ctx Context) (float64, error) {
+	if err := s.Validate(ctx); err != nil {
+		return 0, err
+	}
+	decay := math.Pow(meanReversionResidual, 1/s.FadeYears)
+	return s.TargetMean + (ctx.LastYearValue-s.TargetMean)*decay, nil
+}
+
+// CohortStrategy implements subscription revenue as retained cohorts
+// Formula: Revenue(t) = Σ NewCohort(t-a) × Retention[a]
+// The "new_cohort" driver holds each year's first-year revenue of new customers;
+// cohorts older than the curve, or without a recorded value, contribute nothing
+type CohortStrategy struct {
+	// Retention[a] is the share of a cohort's first-year revenue kept at age a
+	// (Retention[0] is usually 1); StrategyParams "retention_0", "retention_1", ...
+	Retention []float64 `json:"retention"`
+}
+
+func (s *CohortStrategy) Name() string { return "Cohort" }
+
+func (s *CohortStrategy) RequiredDrivers() []string {
+	return []string{"new_cohort"}
+}
+
+// LaggedReferences returns the prior cohorts the curve reaches back to
+func (s *CohortStrategy) LaggedReferences() []FormulaRef {
+	refs := make([]FormulaRef, 0, len(s.Retention))
+	for age := 1; age < len(s.Retention); age++ {
+		refs = append(refs, FormulaRef{ID: "new_cohort", Lag: age})
+	}
+	return refs
+}
+
+func (s *CohortStrategy) Validate(ctx Context) error {
+	if len(s.Retention) == 0 {
+		return fmt.Errorf("CohortStrategy requires a retention curve")
+	}
+	if _, ok := ctx.Drivers["new_cohort"]; !ok {
+		return fmt.Errorf("CohortStrategy requires 'new_cohort' driver")
+	}
+	return nil
+}
+
+func (s *CohortStrategy) Calculate(ctx Context) (float64, error) {
+	if err := s.Validate(ctx); err != nil {
+		return 0, err
+	}
+	total := ctx.Drivers["new_cohort"] * s.Retention[0]
+	for _, ref := range s.LaggedReferences() {
+		total += ctx.Drivers[ref.Key()] * s.Retention[ref.Lag]
+	}
+	return total, nil
+}
+
+// BacklogStrategy implements revenue as the burn-down of the order backlog
+// Formula: Revenue = Backlog × ConversionRate
+// The "backlog" driver is the backlog at the start of the year; it rolls with
+// e.g. the formula "backlog[t-1] + bookings[t-1] - revenue[t-1]"
+type BacklogStrategy struct {
+	ConversionRate float64 `json:"conversion_rate"` // Share of opening backlog recognized this year
+}
+
+func (s *BacklogStrategy) Name() string { return "Backlog" }
+
+func (s *BacklogStrategy) RequiredDrivers() []string {
+	return []string{"backlog"}
+}
+
+func (s *BacklogStrategy) Validate(ctx Context) error {
+	if s.ConversionRate <= 0 || s.ConversionRate > 1 {
+		return fmt.Errorf("BacklogStrategy requires a conversion rate in (0, 1]")
+	}
+	if _, ok := ctx.Drivers["backlog"]; !ok {
+		return fmt.Errorf("BacklogStrategy requires 'backlog' driver")
+	}
+	return nil
+}
+
+func (s *BacklogStrategy) Calculate(ctx Context) (float64, error) {
+	if err := s.Validate(ctx); err != nil {
+		return 0, err
+	}
+	return ctx.Drivers["backlog"] * s.ConversionRate, nil
+}
+
 // =============================================================================
 // POLYMORPHIC NODE
 // =============================================================================
@@ -208,6 +346,7 @@ type Node struct {
 	Strategy       ProjectionStrategy `json:"-"` // Not serialized directly
 	StrategyName   string             `json:"strategy_name"`
 	StrategyParams map[string]float64 `json:"strategy_params,omitempty"`
+	Formula        string             `json:"formula,omitempty"` // Expression for the "Formula" strategy
 
 	// Hierarchy
 	ParentID     *string  `json:"parent_id,omitempty"`     // For drivers attached to skeleton
@@ -0,0 +1,379 @@
+package projection
+
+import (
+	"agentic_valuation/pkg/core/edgar"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// DefaultNOLUsageCap is the share of taxable income capped losses may offset (US federal, post-2017 losses)
+const DefaultNOLUsageCap = 0.80
+
+// NOLVintage is one layer of net operating loss carryforward
+type NOLVintage struct {
+	Year       int     `json:"year"`        // Fiscal year the loss arose (0 = at or before T-0)
+	Amount     float64 `json:"amount"`      // Unused gross loss (positive)
+	ExpiryYear int     `json:"expiry_year"` // Last fiscal year the loss can be used (0 = indefinite)
+	Capped     bool    `json:"capped"`      // Subject to the jurisdiction's usage cap
+}
+
+// TaxJurisdiction is one taxing authority and its loss carryforward rules
+type TaxJurisdiction struct {
+	Name              string
+	Rate              float64      // Statutory rate (0 = an even share of whatever TaxRate the rated jurisdictions leave)
+	UsageCap          float64      // Share of taxable income capped losses may offset (0 = DefaultNOLUsageCap, 1 = no cap)
+	CarryforwardYears int          // Life of losses generated in the forecast (0 = indefinite)
+	NOLs              []NOLVintage // Opening carryforwards
+}
+
+// TaxPolicy separates book from cash taxes: losses build NOLs instead of negative tax,
+// NOL usage is capped per jurisdiction, and the differences run through deferred
+// tax assets (losses) and liabilities (accelerated tax depreciation).
+type TaxPolicy struct {
+	Jurisdictions           []TaxJurisdiction // nil = one jurisdiction at TaxRate with the default cap
+	TaxDepreciationMultiple float64           // Tax depreciation as a multiple of book (0 = same as book)
+	ValuationAllowance      bool              // No deferred tax asset is recognised on losses (no book benefit)
+}
+
+// JurisdictionTax is one jurisdiction's share of a TaxRoll
+type JurisdictionTax struct {
+	Name         string       `json:"name"`
+	Rate         float64      `json:"rate"`
+	NOLOpening   float64      `json:"nol_opening"`
+	NOLGenerated float64      `json:"nol_generated"`
+	NOLUsed      float64      `json:"nol_used"`
+	NOLExpired   float64      `json:"nol_expired"`
+	CashTax      float64      `json:"cash_tax"`
+	NOLs         []NOLVintage `json:"nols"` // Closing carryforwards
+}
+
+// NOLClosing is the jurisdiction's closing carryforward
+func (j JurisdictionTax) NOLClosing() float64 {
+	return sumNOLs(j.NOLs)
+}
+
+// TaxRoll is the book-to-cash tax bridge of one projected year
+type TaxRoll struct {
+	Year                       int               `json:"year"`
+	PreTaxIncome               float64           `json:"pre_tax_income"`
+	BookDepreciation           float64           `json:"book_depreciation"`
+	TaxDepreciation            float64           `json:"tax_depreciation"`
+	TaxableIncome              float64           `json:"taxable_income"`     // Before loss carryforwards
+	BookTax                    float64           `json:"book_tax"`           // Income tax expense (positive = charge)
+	CashTax                    float64           `json:"cash_tax"`           // Current tax paid (never negative)
+	UnleveredCashTax           float64           `json:"unlevered_cash_tax"` // Cash tax on taxable income before net interest
+	DeferredTax                float64           `json:"deferred_tax"`       // Book less cash tax (added back in operations)
+	DeferredTaxAssetChange     float64           `json:"deferred_tax_asset"` // Tax-effected NOL movement (0 under a valuation allowance)
+	DeferredTaxLiabilityChange float64           `json:"deferred_tax_liab"`  // Tax-effected excess tax depreciation
+	Jurisdictions              []JurisdictionTax `json:"jurisdictions"`
+}
+
+// NOLUsed is the carryforward used across jurisdictions
+func (r TaxRoll) NOLUsed() float64 {
+	total := 0.0
+	for _, j := range r.Jurisdictions {
+		total += j.NOLUsed
+	}
+	return total
+}
+
+// NOLClosing is the closing carryforward across jurisdictions
+func (r TaxRoll) NOLClosing() float64 {
+	total := 0.0
+	for _, j := range r.Jurisdictions {
+		total += j.NOLClosing()
+	}
+	return total
+}
+
+// Clone deep-copies the policy
+func (p *TaxPolicy) Clone() *TaxPolicy {
+	if p == nil {
+		return nil
+	}
+	out := *p
+	if p.Jurisdictions != nil {
+		out.Jurisdictions = make([]TaxJurisdiction, len(p.Jurisdictions))
+		for i, j := range p.Jurisdictions {
+			j.NOLs = append([]NOLVintage(nil), j.NOLs...)
+			out.Jurisdictions[i] = j
+		}
+	}
+	return &out
+}
+
+// resolve returns the policy's jurisdictions with unset rates sharing what TaxRate leaves
+func (p *TaxPolicy) resolve(taxRate float64) []TaxJurisdiction {
+	if len(p.Jurisdictions) == 0 {
+		return []TaxJurisdiction{{Rate: taxRate}}
+	}
+	out := make([]TaxJurisdiction, len(p.Jurisdictions))
+	copy(out, p.Jurisdictions)
+	rated, unrated := 0.0, 0
+	for _, j := range out {
+		if j.Rate == 0 {
+			unrated++
+		}
+		rated += j.Rate
+	}
+	if unrated > 0 {
+		share := math.Max(taxRate-rated, 0) / float64(unrated)
+		for i := range out {
+			if out[i].Rate == 0 {
+				out[i].Rate = share
+			}
+		}
+	}
+	return out
+}
+
+// carryNOLs seeds the policy's opening carryforwards with a roll's closing ones
+func (p *TaxPolicy) carryNOLs(roll *TaxRoll) {
+	if len(p.Jurisdictions) == 0 {
+		p.Jurisdictions = []TaxJurisdiction{{}}
+	}
+	for i := range p.Jurisdictions {
+		if i < len(roll.Jurisdictions) {
+			p.Jurisdictions[i].NOLs = append([]NOLVintage(nil), roll.Jurisdictions[i].NOLs...)
+		}
+	}
+}
+
+// Roll computes the year's book and cash taxes. Taxable income is pre-tax income
+// less excess tax depreciation; a loss adds a capped NOL vintage in every
+// jurisdiction, a profit uses uncapped losses first and then capped losses up to
+// the cap. Book tax is cash tax plus the deferred tax movements, so without a
+// valuation allowance it stays at rate × pre-tax income.
+func (p *TaxPolicy) Roll(year int, preTax, netInterest, bookDepreciation, taxRate float64) TaxRoll {
+	multiple := p.TaxDepreciationMultiple
+	if multiple <= 0 {
+		multiple = 1
+	}
+	roll := TaxRoll{
+		Year:             year,
+		PreTaxIncome:     preTax,
+		BookDepreciation: bookDepreciation,
+		TaxDepreciation:  bookDepreciation * multiple,
+	}
+	excess := roll.TaxDepreciation - roll.BookDepreciation
+	roll.TaxableIncome = preTax - excess
+
+	for _, j := range p.resolve(taxRate) {
+		levered := j.run(year, roll.TaxableIncome)
+		unlevered := j.run(year, roll.TaxableIncome-netInterest)
+
+		roll.CashTax += levered.CashTax
+		roll.UnleveredCashTax += unlevered.CashTax
+		roll.DeferredTaxLiabilityChange += j.Rate * excess
+		if !p.ValuationAllowance {
+			roll.DeferredTaxAssetChange += j.Rate * (levered.NOLClosing() - levered.NOLOpening)
+		}
+		roll.Jurisdictions = append(roll.Jurisdictions, levered)
+	}
+	roll.BookTax = roll.CashTax + roll.DeferredTaxLiabilityChange - roll.DeferredTaxAssetChange
+	roll.DeferredTax = roll.BookTax - roll.CashTax
+	return roll
+}
+
+// run applies one year's taxable income to the jurisdiction's carryforwards
+func (j TaxJurisdiction) run(year int, taxable float64) JurisdictionTax {
+	out := JurisdictionTax{Name: j.Name, Rate: j.Rate}
+	var layers []NOLVintage
+	for _, v := range j.NOLs {
+		out.NOLOpening += v.Amount
+		if v.ExpiryYear > 0 && v.ExpiryYear < year {
+			out.NOLExpired += v.Amount
+			continue
+		}
+		layers = append(layers, v)
+	}
+
+	if taxable <= 0 {
+		out.NOLGenerated = -taxable
+		if out.NOLGenerated > 0 {
+			v := NOLVintage{Year: year, Amount: out.NOLGenerated, Capped: true}
+			if j.CarryforwardYears > 0 {
+				v.ExpiryYear = year + j.CarryforwardYears
+			}
+			layers = append(layers, v)
+		}
+		out.NOLs = layers
+		return out
+	}
+
+	// Uncapped losses first, then capped; soonest to expire first within each
+	sort.SliceStable(layers, func(a, b int) bool {
+		la, lb := layers[a], layers[b]
+		if la.Capped != lb.Capped {
+			return !la.Capped
+		}
+		return expiryKey(la) < expiryKey(lb)
+	})
+	limit := j.UsageCap
+	if limit <= 0 {
+		limit = DefaultNOLUsageCap
+	}
+	remaining := taxable
+	capRoom := -1.0 // Set once the uncapped layers are used
+	for i := range layers {
+		v := &layers[i]
+		room := remaining
+		if v.Capped {
+			if capRoom < 0 {
+				capRoom = math.Min(limit, 1) * remaining
+			}
+			room = capRoom
+		}
+		use := math.Min(v.Amount, room)
+		if use <= 0 {
+			continue
+		}
+		v.Amount -= use
+		remaining -= use
+		if v.Capped {
+			capRoom -= use
+		}
+		out.NOLUsed += use
+	}
+	for _, v := range layers {
+		if v.Amount > 1e-9 {
+			out.NOLs = append(out.NOLs, v)
+		}
+	}
+	out.CashTax = j.Rate * remaining
+	return out
+}
+
+func expiryKey(v NOLVintage) int {
+	if v.ExpiryYear == 0 {
+		return math.MaxInt32
+	}
+	return v.ExpiryYear
+}
+
+func sumNOLs(nols []NOLVintage) float64 {
+	total := 0.0
+	for _, v := range nols {
+		total += v.Amount
+	}
+	return total
+}
+
+// taxBasis is what a year's tax roll needs besides pre-tax income and interest
+type taxBasis struct {
+	policy           *TaxPolicy
+	year             int
+	rate             float64
+	bookDepreciation float64
+}
+
+// roll runs the policy on the year's pre-tax income (nil without a policy)
+func (b *taxBasis) roll(preTax, netInterest float64) *TaxRoll {
+	if b == nil {
+		return nil
+	}
+	r := b.policy.Roll(b.year, preTax, netInterest, b.bookDepreciation, b.rate)
+	return &r
+}
+
+// TaxJurisdictionsFromNotes reads gross NOL carryforwards from the extracted income
+// tax notes ("nol_carryforwards", or a single "net_operating_loss_carryforwards"
+// amount), grouped by jurisdiction. Losses without an expiry are treated as the
+// capped, indefinite-lived kind; rates are left for the caller to set.
+func TaxJurisdictionsFromNotes(notes []*edgar.ExtractedNote) []TaxJurisdiction {
+	var out []TaxJurisdiction
+	index := make(map[string]int)
+	add := func(name string, v NOLVintage) {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			name = "federal"
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(out)
+			index[name] = i
+			out = append(out, TaxJurisdiction{Name: name})
+		}
+		out[i].NOLs = append(out[i].NOLs, v)
+	}
+
+	for _, n := range notes {
+		if n == nil || n.NoteCategory != edgar.NoteCategoryIncomeTax {
+			continue
+		}
+		entries, _ := n.StructuredData["nol_carryforwards"].([]interface{})
+		for _, raw := range entries {
+			entry, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			amount, _ := entry["amount"].(float64)
+			if amount <= 0 {
+				continue
+			}
+			name, _ := entry["jurisdiction"].(string)
+			expiration := fmt.Sprint(entry["expiration"])
+			v := NOLVintage{Amount: amount, ExpiryYear: firstYear(expiration)}
+			v.Capped = v.ExpiryYear == 0
+			add(name, v)
+		}
+		if len(entries) > 0 {
+			continue
+		}
+		if amount, ok := n.StructuredData["net_operating_loss_carryforwards"].(float64); ok && amount > 0 {
+			add("", NOLVintage{Amount: amount, Capped: true})
+		}
+	}
+	return out
+}
+
+// firstYear returns the first four-digit year in s (0 if none): "expire 2030 through 2037" -> 2030
+func firstYear(s string) int {
+	match := yearPattern.FindString(s)
+	if match == "" {
+		return 0
+	}
+	return parseYear(match)
+}
+
+// TaxMarkdown renders the book-to-cash tax bridge of every projected year that ran one
+func TaxMarkdown(path []*ProjectedFinancials) string {
+	var rolls []*TaxRoll
+	for _, p := range path {
+		if p.Tax != nil {
+			rolls = append(rolls, p.Tax)
+		}
+	}
+	if len(rolls) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("**Tax Bridge**\n\n| Line |")
+	for _, r := range rolls {
+		fmt.Fprintf(&sb, " %d |", r.Year)
+	}
+	sb.WriteString("\n|---|")
+	sb.WriteString(strings.Repeat("---|", len(rolls)))
+	sb.WriteString("\n")
+
+	row := func(name string, value func(r *TaxRoll) float64) {
+		fmt.Fprintf(&sb, "| %s |", name)
+		for _, r := range rolls {
+			fmt.Fprintf(&sb, " %.1f |", value(r))
+		}
+		sb.WriteString("\n")
+	}
+	row("Pre-Tax Income", func(r *TaxRoll) float64 { return r.PreTaxIncome })
+	row("- Excess Tax Depreciation", func(r *TaxRoll) float64 { return r.TaxDepreciation - r.BookDepreciation })
+	row("Taxable Income", func(r *TaxRoll) float64 { return r.TaxableIncome })
+	row("NOL Used", func(r *TaxRoll) float64 { return r.NOLUsed() })
+	row("Closing NOL", func(r *TaxRoll) float64 { return r.NOLClosing() })
+	row("Cash Tax", func(r *TaxRoll) float64 { return r.CashTax })
+	row("+ Change in DTL", func(r *TaxRoll) float64 { return r.DeferredTaxLiabilityChange })
+	row("- Change in DTA", func(r *TaxRoll) float64 { return r.DeferredTaxAssetChange })
+	row("Book Tax", func(r *TaxRoll) float64 { return r.BookTax })
+	return sb.String()
+}
@@ -0,0 +1,186 @@
+package projection_test
+
+import (
+	"math"
+	"testing"
+
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/projection"
+)
+
+func TestDebtSchedule_Roll(t *testing.T) {
+	s := &projection.DebtSchedule{
+		BaseYear: 2024,
+		Tranches: []projection.DebtTranche{
+			{Name: "Notes due 2026", Principal: 100, Rate: 0.04, MaturityYear: 2026},
+			{Name: "Term loan", Principal: 60, Floating: true, Spread: 0.02, Amortization: 20},
+			{Name: "Notes due 2025", Principal: 50, Rate: 0.05, MaturityYear: 2025, Refinance: true, RefinanceRate: 0.07, RefinanceTenor: 3},
+		},
+		Curve: projection.RateCurve{2025: 0.03, 2027: 0.05},
+	}
+
+	y1 := s.Roll(2025)
+	// Term loan amortizes 20 at 5%; the 2025 notes are refinanced at 7%
+	if y1.Opening != 210 || y1.Repayments != 70 || y1.Issuance != 50 || y1.Closing != 190 {
+		t.Errorf("unexpected 2025 flows: %+v", y1)
+	}
+	wantInterest := 100*0.04 + 0.05*(60-10) + 25*0.05 + 25*0.07
+	if math.Abs(y1.Interest-wantInterest) > 1e-9 {
+		t.Errorf("2025 interest %.4f, want %.4f", y1.Interest, wantInterest)
+	}
+	// Due in 2026: the 2026 notes and the next amortization
+	if y1.CurrentPortion != 120 {
+		t.Errorf("current portion %.2f, want 120", y1.CurrentPortion)
+	}
+
+	y2 := s.Roll(2026)
+	if y2.Opening != y1.Closing || y2.Closing != 70 {
+		t.Errorf("2026 should open at %.2f and close at 70: %+v", y1.Closing, y2)
+	}
+	// By 2027 the loan is repaid and the refinanced notes roll again in 2028, so nothing is current
+	if s.Roll(2027).CurrentPortion != 0 {
+		t.Errorf("expected no current portion after 2027: %+v", s.Roll(2027))
+	}
+	if rate := s.Roll(2027).Tranches[1].Rate; math.Abs(rate-0.07) > 1e-9 {
+		t.Errorf("floating rate %.4f, want curve 5%% + 2%%", rate)
+	}
+}
+
+func TestDebtTranchesFromNotes(t *testing.T) {
+	notes := []*edgar.ExtractedNote{
+		{NoteCategory: edgar.NoteCategoryDebt, StructuredData: map[string]interface{}{
+			"debt_instruments": []interface{}{
+				map[string]interface{}{"type": "4.375% Senior Notes", "principal": 500.0, "maturity": "March 2029"},
+				map[string]interface{}{"type": "Term Loan A", "principal": 300.0, "floating": true, "spread": 1.5, "rate_unit": "percent", "maturity": "2027"},
+				map[string]interface{}{"type": "Convertible Notes due 2026", "principal": 200.0, "interest_rate": 0.5, "rate_unit": "percent"},
+				map[string]interface{}{"type": "Revolving facility", "principal": 50.0, "interest_rate": 0.5},
+			},
+		}},
+	}
+	tranches := projection.DebtTranchesFromNotes(notes, 2024)
+	if len(tranches) != 4 {
+		t.Fatalf("expected 2 tranches, got %+v", tranches)
+	}
+	if math.Abs(tranches[0].Rate-0.04375) > 1e-12 || tranches[0].MaturityYear != 2029 {
+		t.Errorf("senior notes parsed as %+v", tranches[0])
+	}
+	if !tranches[1].Floating || math.Abs(tranches[1].Spread-0.015) > 1e-12 || tranches[1].MaturityYear != 2027 {
+		t.Errorf("term loan parsed as %+v", tranches[1])
+	}
+	// A sub-1 percentage is not mistaken for a decimal, and a rate without a unit is not guessed
+	if math.Abs(tranches[2].Rate-0.005) > 1e-12 || tranches[2].MaturityYear != 2026 {
+		t.Errorf("convertible parsed as %+v", tranches[2])
+	}
+	if tranches[3].Rate != 0 {
+		t.Errorf("rate without a unit should be left unset: %+v", tranches[3])
+	}
+
+	// Without instruments the maturity table is used
+	table := []*edgar.ExtractedNote{{NoteCategory: edgar.NoteCategoryDebt, Tables: []edgar.NoteTable{{Rows: []edgar.NoteTableRow{
+		{RowLabel: "2025", ColumnYear: 2024, Value: fptr(100)},
+		{RowLabel: "2026", ColumnYear: 2024, Value: fptr(150)},
+		{RowLabel: "Thereafter", ColumnYear: 2024, Value: fptr(250)},
+		{RowLabel: "Total", ColumnYear: 2024, Value: fptr(500)},
+	}}}}}
+	bs := &edgar.BalanceSheet{
+		CurrentLiabilities:    edgar.CurrentLiabilities{CurrentMaturitiesLTD: val(100)},
+		NoncurrentLiabilities: edgar.NoncurrentLiabilities{LongTermDebt: val(900)},
+	}
+	s := projection.NewDebtSchedule(table, bs, 2024, 0.05)
+	if len(s.Tranches) != 3 || s.Tranches[2].MaturityYear != 2027 {
+		t.Fatalf("unexpected maturity tranches: %+v", s.Tranches)
+	}
+	if math.Abs(s.Total()-1000) > 1e-9 || s.Tranches[0].Rate != 0.05 {
+		t.Errorf("schedule not reconciled to the balance sheet: total %.2f, %+v", s.Total(), s.Tranches[0])
+	}
+}
+
+func TestProjectHorizon_DebtSchedule(t *testing.T) {
+	hist := horizonHistory()
+	hist.BalanceSheet.CurrentLiabilities.CurrentMaturitiesLTD = val(50)
+	hist.BalanceSheet.NoncurrentLiabilities.LongTermDebt = val(150)
+
+	assumptions := horizonAssumptions()
+	assumptions.DebtInterestRate = 0.20 // Ignored for term debt with a schedule
+	assumptions.DebtSchedule = &projection.DebtSchedule{
+		BaseYear: 2024,
+		Tranches: []projection.DebtTranche{
+			{Name: "Notes due 2025", Principal: 50, Rate: 0.04, MaturityYear: 2025},
+			{Name: "Notes due 2027", Principal: 150, Rate: 0.06, MaturityYear: 2027, Refinance: true, RefinanceRate: 0.08},
+		},
+	}
+
+	path, err := projection.NewProjectionEngine(nil).ProjectHorizon(projection.HorizonInput{
+		History:     hist,
+		Assumptions: assumptions,
+		Years:       4,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first := path[0]
+	if first.Debt == nil || first.Interest.DebtInterest != first.Debt.Interest {
+		t.Fatalf("scheduled interest should drive the income statement: %+v", first.Interest)
+	}
+	if want := 50*0.04/2 + 150*0.06; math.Abs(first.Debt.Interest-want) > 1e-9 {
+		t.Errorf("2025 term interest %.4f, want %.4f", first.Debt.Interest, want)
+	}
+	if got := getValue(first.CashFlow.FinancingActivities.DebtRepayments); got > -50+1e-9 {
+		t.Errorf("expected the 2025 notes repaid in financing, got %.2f", got)
+	}
+	bs := first.BalanceSheet
+	if getValue(bs.NoncurrentLiabilities.LongTermDebt) != 150 || getValue(bs.CurrentLiabilities.CurrentMaturitiesLTD) != 0 {
+		t.Errorf("2025 term debt split %.2f / %.2f, want 150 / 0",
+			getValue(bs.NoncurrentLiabilities.LongTermDebt), getValue(bs.CurrentLiabilities.CurrentMaturitiesLTD))
+	}
+
+	// 2027 refinances at 8%: the repayment and reissue both reach the cash flow
+	refi := path[2]
+	if refi.Debt.Issuance != 150 || getValue(refi.CashFlow.FinancingActivities.DebtProceeds) < 150 {
+		t.Errorf("refinancing not in proceeds: %+v", refi.Debt)
+	}
+	if rate := path[3].Debt.EffectiveRate(); math.Abs(rate-0.08) > 1e-9 {
+		t.Errorf("post-refinancing rate %.4f, want 8%%", rate)
+	}
+}
+
+func fptr(v float64) *float64 { return &v }
+
+func TestApplyNotes(t *testing.T) {
+	notes := []*edgar.ExtractedNote{
+		{NoteCategory: edgar.NoteCategoryDebt, StructuredData: map[string]interface{}{
+			"debt_instruments": []interface{}{
+				map[string]interface{}{"type": "Senior Notes", "principal": 150.0, "interest_rate": 4.0, "rate_unit": "percent", "maturity": "2026"},
+				map[string]interface{}{"type": "Term Loan", "principal": 50.0},
+			},
+		}},
+		{NoteCategory: edgar.NoteCategoryIncomeTax, StructuredData: map[string]interface{}{
+			"net_operating_loss_carryforwards": 80.0,
+		}},
+	}
+	hist := horizonHistory()
+	a := horizonAssumptions()
+	a.DebtInterestRate = 0.06
+	a.ApplyNotes(notes, hist.BalanceSheet, hist.FiscalYear)
+
+	if a.DebtSchedule == nil || len(a.DebtSchedule.Tranches) != 2 {
+		t.Fatalf("expected a two-tranche schedule, got %+v", a.DebtSchedule)
+	}
+	if a.DebtSchedule.BaseYear != 2024 || math.Abs(a.DebtSchedule.Total()-200) > 1e-9 {
+		t.Errorf("schedule not based on FY2024 term debt: %+v", a.DebtSchedule)
+	}
+	if a.DebtSchedule.Tranches[0].Rate != 0.04 || a.DebtSchedule.Tranches[1].Rate != 0.06 {
+		t.Errorf("unexpected tranche rates: %+v", a.DebtSchedule.Tranches)
+	}
+	if a.Tax == nil || len(a.Tax.Jurisdictions) != 1 || a.Tax.Jurisdictions[0].NOLs[0].Amount != 80 {
+		t.Errorf("expected the NOL on the tax policy, got %+v", a.Tax)
+	}
+
+	// Notes without debt or tax data leave the assumptions alone
+	b := horizonAssumptions()
+	b.ApplyNotes(nil, hist.BalanceSheet, hist.FiscalYear)
+	if b.DebtSchedule != nil || b.Tax != nil {
+		t.Errorf("expected no schedule or tax policy without notes: %+v %+v", b.DebtSchedule, b.Tax)
+	}
+}
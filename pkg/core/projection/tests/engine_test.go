@@ -85,6 +85,8 @@ func TestProjectYear_Balancing(t *testing.T) {
 		DPO:                36.5,
 		CapexPercent:       0.50, // High Capex to trigger plug?
 		UsefulLifeForecast: 10.0, // Dep = Gross / 10 = 1000 / 10 = 100
+		// Hand calculation below uses interest on opening debt
+		InterestConvention: projection.InterestBeginningBalance,
 	}
 
 	engine := projection.NewProjectionEngine(nil)
@@ -0,0 +1,138 @@
+package projection_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"agentic_valuation/pkg/core/projection"
+)
+
+func TestParseFormula_Calculate(t *testing.T) {
+	f, err := projection.ParseFormula("stores * sales_per_store * (1 + comp) - max(0, revenue[t-1] / 100) ^ 2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := f.RequiredDrivers(); len(got) != 3 || got[0] != "stores" || got[2] != "comp" {
+		t.Errorf("unexpected same-year references: %v", got)
+	}
+
+	v, err := f.Calculate(projection.Context{Drivers: map[string]float64{
+		"stores": 10, "sales_per_store": 5, "comp": 0.1, "revenue[t-1]": 300,
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := 10*5*1.1 - 9; math.Abs(v-want) > 1e-9 {
+		t.Errorf("formula = %.4f, want %.4f", v, want)
+	}
+
+	if _, err := f.Calculate(projection.Context{Drivers: map[string]float64{"stores": 1}}); err == nil {
+		t.Error("expected an error for missing references")
+	}
+}
+
+func TestParseFormula_ErrorPositions(t *testing.T) {
+	tests := []struct {
+		expr string
+		pos  int
+	}{
+		{"revenue * ", 10},
+		{"(revenue + 1", 12},
+		{"revenue[t+1]", 9},
+		{"revenue[t-0]", 10},
+		{"revenue $ 2", 8},
+		{"sqrt(revenue)", 0},
+		{"abs(1, 2)", 0},
+	}
+	for _, tt := range tests {
+		_, err := projection.ParseFormula(tt.expr)
+		var ferr *projection.FormulaError
+		if !errors.As(err, &ferr) {
+			t.Errorf("%q: expected a FormulaError, got %v", tt.expr, err)
+			continue
+		}
+		if ferr.Pos != tt.pos {
+			t.Errorf("%q: error at %d, want %d (%v)", tt.expr, ferr.Pos, tt.pos, err)
+		}
+	}
+}
+
+func TestSkeleton_ValidateFormula(t *testing.T) {
+	skeleton := projection.NewStandardSkeleton()
+	f, _ := projection.ParseFormula("revenue * stores")
+	err := skeleton.ValidateFormula(f)
+	var ferr *projection.FormulaError
+	if !errors.As(err, &ferr) || ferr.Pos != 10 {
+		t.Fatalf("expected 'stores' to be unknown at position 10, got %v", err)
+	}
+
+	if err := skeleton.AddDriver("revenue", &projection.Node{ID: "auto_stores", Type: projection.NodeTypeDriver}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := skeleton.ValidateFormula(f); err != nil {
+		t.Errorf("an attached driver should validate: %v", err)
+	}
+}
+
+func TestProjectHorizon_FormulaStrategy(t *testing.T) {
+	skeleton := projection.NewStandardSkeleton()
+	selector := projection.NewStrategySelector()
+	driver := func(id string, values map[int]float64) *projection.Node {
+		return &projection.Node{ID: id, Type: projection.NodeTypeDriver, Values: values, UpdatedBy: "USER"}
+	}
+	err := selector.ApplyDecision(skeleton, projection.StrategyDecision{
+		NodeID:              "revenue",
+		RecommendedStrategy: "Formula",
+		Formula:             "stores * sales_per_store * (1 + comp)",
+		NewDrivers: []*projection.Node{
+			driver("stores", map[int]float64{2025: 100, 2026: 110}),
+			driver("sales_per_store", map[int]float64{2025: 10, 2026: 10}),
+			driver("comp", map[int]float64{2025: 0.1, 2026: 0.05}),
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = selector.ApplyDecision(skeleton, projection.StrategyDecision{
+		NodeID:              "sga",
+		RecommendedStrategy: "Formula",
+		Formula:             "0.1 * revenue + 0.05 * revenue[t-1]",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	path, err := projection.NewProjectionEngine(skeleton).ProjectHorizon(projection.HorizonInput{
+		History:     horizonHistory(),
+		Assumptions: horizonAssumptions(),
+		Years:       2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	prevRev := 1000.0
+	for i, want := range []float64{100 * 10 * 1.1, 110 * 10 * 1.05} {
+		p := path[i]
+		rev := getValue(p.IncomeStatement.GrossProfitSection.Revenues)
+		if math.Abs(rev-want) > 1e-6 {
+			t.Errorf("year %d revenue %.4f, want %.4f", p.Year, rev, want)
+		}
+		sga := getValue(p.IncomeStatement.OperatingCostSection.SGAExpenses)
+		if want := 0.1*rev + 0.05*prevRev; math.Abs(math.Abs(sga)-want) > 1e-6 {
+			t.Errorf("year %d SG&A %.4f, want %.4f", p.Year, sga, want)
+		}
+		prevRev = rev
+	}
+
+	// A reference to an unknown node is rejected when the strategy is applied
+	err = selector.ApplyDecision(skeleton, projection.StrategyDecision{
+		NodeID:              "rd",
+		RecommendedStrategy: "Formula",
+		Formula:             "0.1 * revenue + headcount",
+	})
+	var ferr *projection.FormulaError
+	if !errors.As(err, &ferr) || ferr.Pos != 16 {
+		t.Errorf("expected an unknown 'headcount' at position 16, got %v", err)
+	}
+}
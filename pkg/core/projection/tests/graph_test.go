@@ -0,0 +1,157 @@
+package projection_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"agentic_valuation/pkg/core/projection"
+)
+
+func TestSkeletonGraph_Cycle(t *testing.T) {
+	skeleton := projection.NewStandardSkeleton()
+	skeleton.Revenue.Strategy = &projection.MarginStrategy{MarginPercent: 2, BaseNodeID: "cogs"}
+	skeleton.Revenue.UpdatedBy = "USER"
+	skeleton.COGS.Strategy = &projection.MarginStrategy{MarginPercent: 0.5, BaseNodeID: "revenue"}
+	skeleton.COGS.UpdatedBy = "USER"
+
+	_, err := skeleton.Graph()
+	var cycle *projection.CycleError
+	if !errors.As(err, &cycle) || len(cycle.Path) != 3 || cycle.Path[0] != cycle.Path[2] {
+		t.Fatalf("expected a revenue <-> cogs cycle, got %v", err)
+	}
+}
+
+func TestSkeletonGraph_SkipsIdentityNodes(t *testing.T) {
+	skeleton := projection.NewStandardSkeleton()
+	skeleton.GrossProfit.Strategy = &projection.GrowthStrategy{GrowthRate: 0.5}
+	skeleton.GrossProfit.UpdatedBy = "USER"
+	graph, err := skeleton.Graph()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if graph.HasOverrides() || len(graph.Warnings()) != 1 {
+		t.Errorf("expected gross profit to be skipped with a warning, got %v", graph.Warnings())
+	}
+
+	// The horizon projects with gross profit as an accounting identity and reports the skipped strategy
+	path, err := projection.NewProjectionEngine(skeleton).ProjectHorizon(projection.HorizonInput{
+		History:     horizonHistory(),
+		Assumptions: horizonAssumptions(),
+		Years:       1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	gp := path[0].IncomeStatement.GrossProfitSection
+	if math.Abs(getValue(gp.GrossProfit)-0.4*getValue(gp.Revenues)) > 1e-6 || len(path[0].Warnings) != 1 {
+		t.Errorf("gross profit %.4f should follow revenue less COGS (warnings %v)", getValue(gp.GrossProfit), path[0].Warnings)
+	}
+
+	// A decision on an identity node is rejected when it is applied
+	err = projection.NewStrategySelector().ApplyDecision(skeleton, projection.StrategyDecision{NodeID: "gross_profit", RecommendedStrategy: "Growth"})
+	if err == nil {
+		t.Error("expected ApplyDecision to reject gross profit")
+	}
+}
+
+func TestSkeletonGraph_MarginDecisionDrivesCOGS(t *testing.T) {
+	skeleton := projection.NewStandardSkeleton()
+	selector := projection.NewStrategySelector()
+	decision := selector.SelectStrategy(projection.DriverDiscovery{NodeID: "cogs"})
+	decision.StrategyParams = map[string]float64{"margin_percent": 0.45}
+	if err := selector.ApplyDecision(skeleton, decision); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	path, err := projection.NewProjectionEngine(skeleton).ProjectHorizon(projection.HorizonInput{
+		History:     horizonHistory(),
+		Assumptions: horizonAssumptions(), // COGS at 60% of revenue
+		Years:       2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, p := range path {
+		gp := p.IncomeStatement.GrossProfitSection
+		rev, cogs := getValue(gp.Revenues), getValue(gp.CostOfGoodsSold)
+		if math.Abs(cogs+0.45*rev) > 1e-6 {
+			t.Errorf("year %d COGS %.4f, want 45%% of %.4f", p.Year, cogs, rev)
+		}
+		if p.NodeValues["cogs"] != -cogs {
+			t.Errorf("year %d graph COGS %.4f, want %.4f", p.Year, p.NodeValues["cogs"], -cogs)
+		}
+	}
+	if len(skeleton.COGS.Values) != 0 {
+		t.Errorf("the projection should not write into the skeleton: %v", skeleton.COGS.Values)
+	}
+}
+
+func TestSkeletonGraph_PriceVolumeRevenue(t *testing.T) {
+	skeleton := projection.NewStandardSkeleton()
+	selector := projection.NewStrategySelector()
+	decision := selector.SelectStrategy(projection.DriverDiscovery{NodeID: "revenue", AvailableData: []string{"volume"}})
+	for _, d := range decision.NewDrivers {
+		switch d.ID {
+		case "auto_price":
+			d.Values[2024] = 10
+			d.StrategyParams = map[string]float64{"growth_rate": 0.05}
+		case "auto_volume":
+			d.Values[2024] = 100
+			d.StrategyParams = map[string]float64{"growth_rate": 0.20}
+		}
+	}
+	if err := selector.ApplyDecision(skeleton, decision); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	graph, err := skeleton.Graph()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	order := graph.Order()
+	position := make(map[string]int, len(order))
+	for i, id := range order {
+		position[id] = i
+	}
+	if position["auto_price"] > position["revenue"] || position["auto_volume"] > position["revenue"] {
+		t.Errorf("drivers should be evaluated before revenue: %v", order)
+	}
+
+	path, err := projection.NewProjectionEngine(skeleton).ProjectHorizon(projection.HorizonInput{
+		History:     horizonHistory(),
+		Assumptions: horizonAssumptions(),
+		Years:       2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Price 10 -> 10.5 -> 11.025, volume 100 -> 120 -> 144
+	for i, want := range []float64{10.5 * 120, 11.025 * 144} {
+		if got := getValue(path[i].IncomeStatement.GrossProfitSection.Revenues); math.Abs(got-want) > 1e-6 {
+			t.Errorf("year %d revenue %.4f, want %.4f", path[i].Year, got, want)
+		}
+	}
+	if path[1].NodeValues["auto_volume"] != 144 {
+		t.Errorf("volume should carry between years: %v", path[1].NodeValues)
+	}
+	if len(skeleton.Drivers["auto_volume"].Values) != 1 {
+		t.Errorf("the projection should not write into the skeleton: %v", skeleton.Drivers["auto_volume"].Values)
+	}
+
+	// A second run on the same skeleton (e.g. the next simulation iteration) starts from the same inputs
+	again, err := projection.NewProjectionEngine(skeleton).ProjectHorizon(projection.HorizonInput{
+		History:     horizonHistory(),
+		Assumptions: horizonAssumptions(),
+		Years:       2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := range path {
+		first, second := getValue(path[i].IncomeStatement.GrossProfitSection.Revenues), getValue(again[i].IncomeStatement.GrossProfitSection.Revenues)
+		if first != second {
+			t.Errorf("year %d revenue %.4f on the second run, want %.4f", path[i].Year, second, first)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package projection_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/internal/testfixture"
+	"agentic_valuation/pkg/core/projection"
+)
+
+// horizonHistory is the shared T-0 with deferred revenue, a carried line and an additional item
+func horizonHistory() projection.History {
+	hist := testfixture.History()
+	bs := hist.BalanceSheet
+	bs.CurrentAssets.OtherAssets = val(20)
+	bs.CurrentAssets.AdditionalItems = []edgar.FSAPValue{{Label: "Prepaids", Value: val(30).Value}}
+	bs.CurrentLiabilities.DeferredRevenueCurrent = val(50)
+	return hist
+}
+
+func horizonAssumptions() projection.ProjectionAssumptions {
+	return projection.ProjectionAssumptions{
+		RevenueGrowth:          0.10,
+		COGSPercent:            0.60,
+		SGAPercent:             0.20,
+		TaxRate:                0.25,
+		DSO:                    36.5,
+		DSI:                    36.5,
+		DPO:                    36.5,
+		CapexPercent:           0.15,
+		UsefulLifeForecast:     10.0,
+		DeferredRevenuePercent: 0.06,
+		StockBasedCompPercent:  0.01,
+		DividendPayoutRatio:    0.5,
+		MinimumCash:            150,
+		RevolverInterestRate:   0.08,
+		NodeDrivers:            map[string]float64{"BS-CL:Customer Deposits": 0.02},
+	}
+}
+
+func TestProjectHorizon_Articulates(t *testing.T) {
+	in := projection.HorizonInput{
+		History:     horizonHistory(),
+		Assumptions: horizonAssumptions(),
+		Schedule:    projection.DriverSchedule{"revenue_growth": {0.20, 0.10, 0.0}},
+		Years:       5,
+	}
+	path, err := projection.NewProjectionEngine(nil).ProjectHorizon(in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(path) != 5 || path[0].Year != 2025 || path[4].Year != 2029 {
+		t.Fatalf("unexpected path years: %d projections", len(path))
+	}
+
+	// Scheduled growth, holding the last value past the end of the path
+	revenues := []float64{1200, 1320, 1320, 1320, 1320}
+	for i, p := range path {
+		rev := getValue(p.IncomeStatement.GrossProfitSection.Revenues)
+		if math.Abs(rev-revenues[i]) > 1e-6 {
+			t.Errorf("year %d revenue %.2f, want %.2f", p.Year, rev, revenues[i])
+		}
+	}
+
+	first := path[0]
+	bs := first.BalanceSheet
+	if got := getValue(bs.CurrentAssets.OtherAssets); got != 20 {
+		t.Errorf("undriven other assets should carry flat, got %.2f", got)
+	}
+	if got := getValue(bs.CurrentLiabilities.DeferredRevenueCurrent); math.Abs(got-72) > 1e-6 {
+		t.Errorf("deferred revenue %.2f, want 72", got)
+	}
+	if len(bs.CurrentLiabilities.AdditionalItems) != 1 || math.Abs(getValue(&bs.CurrentLiabilities.AdditionalItems[0])-24) > 1e-6 {
+		t.Errorf("expected Customer Deposits driven at 2%% of revenue, got %+v", bs.CurrentLiabilities.AdditionalItems)
+	}
+	if cash := getValue(bs.CurrentAssets.CashAndEquivalents); cash < 150-1e-6 {
+		t.Errorf("cash %.2f below the minimum", cash)
+	}
+
+	// The first-year revolver draw reaches the cash flow statement
+	revolver := getValue(bs.CurrentLiabilities.NotesPayableShortTermDebt)
+	if revolver <= 0 {
+		t.Fatalf("expected the growth year to draw on the revolver, got %.2f", revolver)
+	}
+	if got := getValue(first.CashFlow.FinancingActivities.DebtProceeds); math.Abs(got-revolver) > 1e-6 {
+		t.Errorf("revolver draw %.2f not in debt proceeds %.2f", revolver, got)
+	}
+}
+
+func TestProjectHorizon_ArticulationError(t *testing.T) {
+	hist := horizonHistory()
+	path, err := projection.NewProjectionEngine(nil).ProjectHorizon(projection.HorizonInput{
+		History:     hist,
+		Assumptions: horizonAssumptions(),
+		Years:       1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Move 5 from other assets into cash: the balance sheet still balances
+	// but cash no longer ties to the cash flow statement
+	proj := path[0]
+	ca := &proj.BalanceSheet.CurrentAssets
+	ca.CashAndEquivalents = val(getValue(ca.CashAndEquivalents) + 5)
+	ca.OtherAssets = val(getValue(ca.OtherAssets) - 5)
+
+	err = projection.CheckArticulation(hist.BalanceSheet, proj, projection.DefaultArticulationTolerance)
+	var artErr *projection.ArticulationError
+	if !errors.As(err, &artErr) {
+		t.Fatalf("expected an ArticulationError, got %v", err)
+	}
+	if artErr.Year != 2025 || artErr.Line != projection.LineCashEnd {
+		t.Errorf("unexpected articulation error: %v", artErr)
+	}
+}
+
+func TestProjectHorizon_RejectsBadInput(t *testing.T) {
+	engine := projection.NewProjectionEngine(nil)
+	if _, err := engine.ProjectHorizon(projection.HorizonInput{Assumptions: horizonAssumptions(), Years: 3}); err == nil {
+		t.Error("expected error without history")
+	}
+	if _, err := engine.ProjectHorizon(projection.HorizonInput{
+		History:     horizonHistory(),
+		Assumptions: horizonAssumptions(),
+		Schedule:    projection.DriverSchedule{"not_a_driver": {0.1}},
+		Years:       3,
+	}); err == nil {
+		t.Error("expected error for an unknown scheduled driver")
+	}
+}
@@ -0,0 +1,130 @@
+package projection_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/projection"
+)
+
+// circularHistory has 200 of debt at an implied 5% and a year that needs the revolver
+func circularHistory() (*edgar.IncomeStatement, *edgar.BalanceSheet, projection.ProjectionAssumptions) {
+	prevIS := &edgar.IncomeStatement{
+		GrossProfitSection:  &edgar.GrossProfitSection{Revenues: val(1000)},
+		NonOperatingSection: &edgar.NonOperatingSection{InterestExpense: val(-10)},
+	}
+	prevBS := &edgar.BalanceSheet{
+		CurrentAssets: edgar.CurrentAssets{
+			CashAndEquivalents:    val(100),
+			AccountsReceivableNet: val(100),
+			Inventories:           val(100),
+		},
+		NoncurrentAssets: edgar.NoncurrentAssets{
+			PPENet:                  val(500),
+			PPEAtCost:               val(1000),
+			AccumulatedDepreciation: val(-500),
+		},
+		CurrentLiabilities:    edgar.CurrentLiabilities{AccountsPayable: val(100)},
+		NoncurrentLiabilities: edgar.NoncurrentLiabilities{LongTermDebt: val(200)},
+		Equity: edgar.Equity{
+			CommonStockAPIC:         val(100),
+			RetainedEarningsDeficit: val(400),
+		},
+	}
+	assumptions := projection.ProjectionAssumptions{
+		RevenueGrowth:      0.10,
+		COGSPercent:        0.60,
+		SGAPercent:         0.20,
+		RDPercent:          0.05,
+		TaxRate:            0.25,
+		DSO:                36.5,
+		DSI:                36.5,
+		DPO:                36.5,
+		CapexPercent:       0.50,
+		UsefulLifeForecast: 10.0,
+		CashInterestRate:   0.02,
+	}
+	return prevIS, prevBS, assumptions
+}
+
+func TestProjectYear_CircularInterest(t *testing.T) {
+	prevIS, prevBS, assumptions := circularHistory()
+	assumptions.RevolverInterestRate = 0.08
+
+	proj := projection.NewProjectionEngine(nil).ProjectYear(prevIS, prevBS, nil, assumptions, 2025)
+	solve := proj.Interest
+	if solve == nil || !solve.Converged {
+		t.Fatalf("expected a converged interest solve, got %+v", solve)
+	}
+	if solve.Iterations < 2 {
+		t.Errorf("expected the circularity to take several iterations, got %d", solve.Iterations)
+	}
+
+	// Closing balances reproduce the interest booked on the income statement
+	cash := getValue(proj.BalanceSheet.CurrentAssets.CashAndEquivalents)
+	revolver := getValue(proj.BalanceSheet.CurrentLiabilities.NotesPayableShortTermDebt)
+	want := 0.02*(100+cash)/2 - 0.05*200 - 0.08*(0+revolver)/2
+	got := getValue(proj.IncomeStatement.NonOperatingSection.InterestExpense)
+	if math.Abs(got-want) > 1e-5 {
+		t.Errorf("net interest %.6f, average-balance interest %.6f", got, want)
+	}
+	if revolver <= 243.75 {
+		t.Errorf("revolver interest should deepen the shortfall beyond 243.75, got %.4f", revolver)
+	}
+
+	// Opening-balance convention reproduces the single-pass result
+	assumptions.InterestConvention = projection.InterestBeginningBalance
+	legacy := projection.NewProjectionEngine(nil).ProjectYear(prevIS, prevBS, nil, assumptions, 2025)
+	if legacy.Interest.Iterations != 0 {
+		t.Errorf("beginning-balance interest should not iterate, got %d", legacy.Interest.Iterations)
+	}
+	if got := getValue(legacy.IncomeStatement.NonOperatingSection.InterestExpense); math.Abs(got-(0.02*100-0.05*200)) > 1e-9 {
+		t.Errorf("beginning-balance net interest %.4f, want -8", got)
+	}
+}
+
+func TestProjectYear_MinimumCash(t *testing.T) {
+	prevIS, prevBS, assumptions := circularHistory()
+	assumptions.MinimumCash = 50
+
+	proj := projection.NewProjectionEngine(nil).ProjectYear(prevIS, prevBS, nil, assumptions, 2025)
+	if cash := getValue(proj.BalanceSheet.CurrentAssets.CashAndEquivalents); math.Abs(cash-50) > 1e-9 {
+		t.Errorf("cash %.4f, want the 50 floor", cash)
+	}
+
+	base := assumptions
+	base.MinimumCash = 0
+	noFloor := projection.NewProjectionEngine(nil).ProjectYear(prevIS, prevBS, nil, base, 2025)
+	extra := getValue(proj.BalanceSheet.CurrentLiabilities.NotesPayableShortTermDebt) -
+		getValue(noFloor.BalanceSheet.CurrentLiabilities.NotesPayableShortTermDebt)
+	if extra < 50 {
+		t.Errorf("revolver should fund the cash floor plus its interest, drew %.4f more", extra)
+	}
+}
+
+func TestProjectYear_InterestIterationCap(t *testing.T) {
+	prevIS, prevBS, assumptions := circularHistory()
+	engine := projection.NewProjectionEngine(nil)
+	engine.MaxInterestIterations = 1
+
+	proj := engine.ProjectYear(prevIS, prevBS, nil, assumptions, 2025)
+	if proj.Interest.Converged || proj.Interest.Iterations != 1 {
+		t.Errorf("expected to stop unconverged after 1 iteration, got %+v", proj.Interest)
+	}
+
+	// The horizon stops at the unconverged year instead of passing it on silently
+	path, err := engine.ProjectHorizon(projection.HorizonInput{
+		History:     projection.History{IncomeStatement: prevIS, BalanceSheet: prevBS, FiscalYear: 2024},
+		Assumptions: assumptions,
+		Years:       3,
+	})
+	var convErr *projection.ConvergenceError
+	if !errors.As(err, &convErr) || convErr.Year != 2025 || convErr.Iterations != 1 || convErr.Residual <= 0 {
+		t.Fatalf("expected a 2025 convergence error, got %v", err)
+	}
+	if len(path) != 1 {
+		t.Errorf("expected the unconverged year in the partial path, got %d years", len(path))
+	}
+}
@@ -0,0 +1,126 @@
+package projection_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/projection"
+)
+
+func TestFixedAssetPolicy_Roll(t *testing.T) {
+	prevBS := &edgar.BalanceSheet{NoncurrentAssets: edgar.NoncurrentAssets{
+		PPEAtCost:               val(1000),
+		AccumulatedDepreciation: val(-600),
+	}}
+	policy := &projection.FixedAssetPolicy{
+		ExistingLife:    4, // 400 of net book value over 4 years
+		NewLife:         5,
+		HalfYear:        true,
+		DisposalPercent: 0.10,
+		Impairments:     map[int]float64{2025: 30},
+	}
+	roll := policy.Roll(prevBS, projection.ProjectionAssumptions{}, 200, 2025)
+
+	// Disposal of 10% of cost at book value: 100 cost, 60 accumulated, 40 proceeds
+	if roll.DisposalsCost != 100 || math.Abs(roll.DisposalsAccumulated-60) > 1e-9 || math.Abs(roll.DisposalProceeds-40) > 1e-9 {
+		t.Errorf("unexpected disposals: %+v", roll)
+	}
+	// Base: 360 net after disposal, impaired by 30 to 330, charged 90 * 330/360 = 82.5
+	base := 90 * 330.0 / 360
+	if math.Abs(roll.DepreciationByYear[0]-base) > 1e-9 {
+		t.Errorf("base depreciation %.4f, want %.4f", roll.DepreciationByYear[0], base)
+	}
+	// Capex vintage: 200 over 5 years, half a year
+	if math.Abs(roll.DepreciationByYear[2025]-20) > 1e-9 {
+		t.Errorf("vintage depreciation %.4f, want 20", roll.DepreciationByYear[2025])
+	}
+	if roll.ClosingCost != 1100 {
+		t.Errorf("closing cost %.2f, want 1100", roll.ClosingCost)
+	}
+	wantNet := 400 - 40 - 30 - base + 200 - 20
+	if math.Abs(roll.ClosingNet()-wantNet) > 1e-9 {
+		t.Errorf("closing net %.4f, want %.4f", roll.ClosingNet(), wantNet)
+	}
+	if len(roll.Vintages) != 2 || roll.Vintages[1].Year != 2025 {
+		t.Errorf("unexpected closing layers: %+v", roll.Vintages)
+	}
+}
+
+func TestFixedAssetPolicy_Retirement(t *testing.T) {
+	policy := &projection.FixedAssetPolicy{
+		RetireFullyDepreciated: true,
+		Vintages: []projection.PPEVintage{
+			{Year: 2020, Cost: 100, Accumulated: 90, Annual: 20},
+			{Year: 2023, Cost: 50, Accumulated: 10, Annual: 10},
+		},
+	}
+	roll := policy.Roll(&edgar.BalanceSheet{}, projection.ProjectionAssumptions{}, 0, 2025)
+	if roll.DepreciationByYear[2020] != 10 {
+		t.Errorf("the last charge is capped at net book value, got %.2f", roll.DepreciationByYear[2020])
+	}
+	if roll.Retirements != 100 || roll.ClosingCost != 50 || roll.ClosingAccumulated != 20 {
+		t.Errorf("expected the 2020 layer to be retired: %+v", roll)
+	}
+}
+
+func TestProjectHorizon_FixedAssets(t *testing.T) {
+	assumptions := horizonAssumptions()
+	assumptions.FixedAssets = &projection.FixedAssetPolicy{
+		ExistingLife: 5,
+		NewLife:      8,
+		Impairments:  map[int]float64{2026: 25},
+	}
+	path, err := projection.NewProjectionEngine(nil).ProjectHorizon(projection.HorizonInput{
+		History:     horizonHistory(),
+		Assumptions: assumptions,
+		Years:       3,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i, p := range path {
+		roll := p.FixedAssets
+		if roll == nil {
+			t.Fatalf("year %d has no PP&E roll", p.Year)
+		}
+		bs := p.BalanceSheet.NoncurrentAssets
+		if getValue(bs.PPEAtCost) != roll.ClosingCost || -getValue(bs.AccumulatedDepreciation) != roll.ClosingAccumulated {
+			t.Errorf("year %d balance sheet does not carry the roll", p.Year)
+		}
+		if getValue(p.CashFlow.OperatingActivities.DepreciationAmortization) != roll.Depreciation {
+			t.Errorf("year %d D&A does not come from the roll", p.Year)
+		}
+		// Each year's vintages carry into the next
+		if i > 0 && len(roll.Vintages) != i+2 {
+			t.Errorf("year %d has %d layers, want %d", p.Year, len(roll.Vintages), i+2)
+		}
+		if i > 0 && math.Abs(roll.OpeningCost-path[i-1].FixedAssets.ClosingCost) > 1e-9 {
+			t.Errorf("year %d opening cost does not tie to the prior close", p.Year)
+		}
+	}
+
+	// 500 of net book value over 5 years; the 2025 vintage takes a full year at 8 years
+	first := path[0].FixedAssets
+	if math.Abs(first.DepreciationByYear[0]-100) > 1e-9 || math.Abs(first.DepreciationByYear[2025]-first.Capex/8) > 1e-9 {
+		t.Errorf("unexpected 2025 depreciation: %+v", first.DepreciationByYear)
+	}
+
+	// The 2026 impairment hits operating income and is added back in operations
+	impaired := path[1]
+	if got := getValue(impaired.IncomeStatement.OperatingCostSection.OtherOperatingExpenses); got != -25 {
+		t.Errorf("impairment on the income statement %.2f, want -25", got)
+	}
+	if got := getValue(impaired.CashFlow.OperatingActivities.ImpairmentCharges); got != 25 {
+		t.Errorf("impairment add-back %.2f, want 25", got)
+	}
+
+	md := projection.FixedAssetMarkdown(path)
+	for _, want := range []string{"| Closing Gross PP&E |", "| D&A: existing base |", "| D&A: 2027 vintage |"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
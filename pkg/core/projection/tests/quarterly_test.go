@@ -0,0 +1,222 @@
+package projection_test
+
+import (
+	"math"
+	"testing"
+
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/projection"
+	"agentic_valuation/pkg/core/synthesis"
+)
+
+// quarterlyHistory is FY2024 reported by quarter: revenue 200/250/250/300 with the
+// horizon balance sheet at each quarter end
+func quarterlyHistory() []projection.QuarterStatements {
+	var out []projection.QuarterStatements
+	for i, rev := range []float64{200, 250, 250, 300} {
+		h := horizonHistory()
+		out = append(out, projection.QuarterStatements{
+			FiscalYear: 2024,
+			Quarter:    i + 1,
+			IncomeStatement: &edgar.IncomeStatement{
+				GrossProfitSection:  &edgar.GrossProfitSection{Revenues: val(rev)},
+				NonOperatingSection: &edgar.NonOperatingSection{InterestExpense: val(-2.5)},
+			},
+			BalanceSheet: h.BalanceSheet,
+			CashFlow: &edgar.CashFlowStatement{CashSummary: &edgar.CashSummarySection{
+				CashBeginning: val(100), CashEnding: val(100), NetChangeInCash: val(0),
+			}},
+		})
+	}
+	return out
+}
+
+func TestSeasonalityFromHistory(t *testing.T) {
+	s := projection.SeasonalityFromHistory(quarterlyHistory())
+	for i, want := range []float64{0.20, 0.25, 0.25, 0.30} {
+		if math.Abs(s[i]-want) > 1e-9 {
+			t.Errorf("Q%d share %.4f, want %.4f", i+1, s[i], want)
+		}
+	}
+	if s := projection.SeasonalityFromHistory(quarterlyHistory()[:3]); s != projection.EvenSeasonality {
+		t.Errorf("an incomplete year should fall back to even seasonality, got %v", s)
+	}
+}
+
+func TestProjectQuarters_RollsUpToFiscalYears(t *testing.T) {
+	a := horizonAssumptions()
+	a.MinimumCash = 40
+	history := quarterlyHistory()
+	path, err := projection.NewProjectionEngine(nil).ProjectQuarters(projection.QuarterlyInput{
+		History:     history,
+		Assumptions: a,
+		Schedule:    projection.DriverSchedule{"revenue_growth": {0.10, 0.20}},
+		Quarters:    8,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(path) != 8 || path[0].Year != 2025 || path[0].Quarter != 1 || path[7].Year != 2026 || path[7].Quarter != 4 {
+		t.Fatalf("unexpected quarters: %d, first %d Q%d", len(path), path[0].Year, path[0].Quarter)
+	}
+	for i, want := range []float64{220, 275, 275, 330} {
+		if got := getValue(path[i].IncomeStatement.GrossProfitSection.Revenues); math.Abs(got-want) > 1e-6 {
+			t.Errorf("2025Q%d revenue %.4f, want %.4f", i+1, got, want)
+		}
+	}
+
+	quarters := append(history, projection.QuarterStatementsOf(path)...)
+	years := projection.RollupFiscalYears(quarters)
+	if len(years) != 3 {
+		t.Fatalf("expected FY2024-FY2026, got %d years", len(years))
+	}
+	for i, want := range []float64{1000, 1100, 1320} {
+		if got := getValue(years[i].IncomeStatement.GrossProfitSection.Revenues); math.Abs(got-want) > 1e-6 {
+			t.Errorf("FY%d revenue %.4f, want %.4f", years[i].Year, got, want)
+		}
+	}
+
+	// The fiscal year articulates against the prior year-end like an annual projection
+	fy2025 := years[1]
+	if fy2025.BalanceSheet != path[3].BalanceSheet {
+		t.Error("FY2025 should close on the Q4 balance sheet")
+	}
+	if err := projection.CheckArticulation(history[3].BalanceSheet, fy2025, projection.DefaultArticulationTolerance); err != nil {
+		t.Errorf("FY2025 rollup does not articulate: %v", err)
+	}
+	quarterNI := 0.0
+	for _, p := range path[:4] {
+		quarterNI += getValue(p.IncomeStatement.NetIncomeSection.NetIncomeToCommon)
+	}
+	if ni := getValue(fy2025.IncomeStatement.NetIncomeSection.NetIncomeToCommon); math.Abs(ni-quarterNI) > 1e-6 {
+		t.Errorf("FY2025 net income %.4f, want the quarters' %.4f", ni, quarterNI)
+	}
+}
+
+func TestProjectQuarters_RejectsAnnualPolicies(t *testing.T) {
+	a := horizonAssumptions()
+	a.Tax = &projection.TaxPolicy{}
+	_, err := projection.NewProjectionEngine(nil).ProjectQuarters(projection.QuarterlyInput{
+		History: quarterlyHistory(), Assumptions: a, Quarters: 4,
+	})
+	if err == nil {
+		t.Error("expected the tax policy to be rejected")
+	}
+}
+
+func TestLTMAndNTM(t *testing.T) {
+	history := quarterlyHistory()
+	path, err := projection.NewProjectionEngine(nil).ProjectQuarters(projection.QuarterlyInput{
+		History: history, Assumptions: horizonAssumptions(), Quarters: 4,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	quarters := append(history, projection.QuarterStatementsOf(path)...)
+
+	ltm, err := projection.LTM(quarters, 2025, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := getValue(ltm.IncomeStatement.GrossProfitSection.Revenues), 250+300+220+275.0; math.Abs(got-want) > 1e-6 {
+		t.Errorf("LTM revenue %.4f, want %.4f", got, want)
+	}
+	if ltm.Year != 2025 || ltm.Quarter != 2 || ltm.BalanceSheet != path[1].BalanceSheet {
+		t.Errorf("LTM should end at 2025Q2, got %d Q%d", ltm.Year, ltm.Quarter)
+	}
+
+	ntm, err := projection.NTM(quarters, 2024, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := getValue(ntm.IncomeStatement.GrossProfitSection.Revenues); math.Abs(got-1100) > 1e-6 {
+		t.Errorf("NTM revenue %.4f, want 1100", got)
+	}
+	if _, err := projection.NTM(quarters, 2025, 1); err == nil {
+		t.Error("expected an error when the window runs past the projection")
+	}
+}
+
+func TestQuarterlyHistoryFromGolden_DerivesQ4(t *testing.T) {
+	record := &synthesis.GoldenRecord{
+		Timeline: map[int]*synthesis.YearlySnapshot{2024: {
+			FiscalYear:        2024,
+			IncomeStatement:   edgar.IncomeStatement{GrossProfitSection: &edgar.GrossProfitSection{Revenues: val(1000)}},
+			BalanceSheet:      edgar.BalanceSheet{CurrentAssets: edgar.CurrentAssets{CashAndEquivalents: val(90)}},
+			CashFlowStatement: edgar.CashFlowStatement{CashSummary: &edgar.CashSummarySection{NetCashOperating: val(400)}},
+		}},
+		Quarters: map[string]*synthesis.QuarterlySnapshot{},
+	}
+	ytdOperating := map[int]float64{1: 100, 2: 180, 3: 280}
+	for q, rev := range map[int]float64{1: 200, 2: 250, 3: 250} {
+		record.Quarters[synthesis.QuarterKey(2024, q)] = &synthesis.QuarterlySnapshot{
+			FiscalYear: 2024, Quarter: q,
+			IncomeStatement:    edgar.IncomeStatement{GrossProfitSection: &edgar.GrossProfitSection{Revenues: val(rev)}},
+			CashFlowYearToDate: edgar.CashFlowStatement{CashSummary: &edgar.CashSummarySection{NetCashOperating: val(ytdOperating[q])}},
+		}
+	}
+
+	quarters := projection.QuarterlyHistoryFromGolden(record)
+	if len(quarters) != 4 || quarters[3].Quarter != 4 {
+		t.Fatalf("expected Q1-Q4, got %d quarters", len(quarters))
+	}
+	q4 := quarters[3]
+	if got := getValue(q4.IncomeStatement.GrossProfitSection.Revenues); got != 300 {
+		t.Errorf("derived Q4 revenue %v, want 300", got)
+	}
+	if got := getValue(q4.BalanceSheet.CurrentAssets.CashAndEquivalents); got != 90 {
+		t.Errorf("Q4 should close on the fiscal year-end balance sheet, cash %v", got)
+	}
+	if got := getValue(q4.CashFlow.CashSummary.NetCashOperating); got != 120 {
+		t.Errorf("derived Q4 operating cash flow %v, want 120 (FY less the nine-month year to date)", got)
+	}
+}
+
+func TestProjectQuarters_InterestMatchesAnnualRate(t *testing.T) {
+	a := horizonAssumptions()
+	a.MinimumCash = 0
+	a.PreTaxCostOfDebt = 0.08 // The only debt rate: DebtInterestRate is unset
+	path, err := projection.NewProjectionEngine(nil).ProjectQuarters(projection.QuarterlyInput{
+		History: quarterlyHistory(), Assumptions: a, Quarters: 4,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	interest := 0.0
+	for _, p := range path {
+		interest += getValue(p.IncomeStatement.NonOperatingSection.InterestExpense)
+	}
+	if want := -200 * 0.08; math.Abs(interest-want) > 1e-6 {
+		t.Errorf("quarterly interest sums to %.4f, want the annual %.4f", interest, want)
+	}
+}
+
+func TestLTM_RestatesEPS(t *testing.T) {
+	var quarters []projection.QuarterStatements
+	for i, q := range []struct{ ni, shares, eps float64 }{{10, 100, 0.10}, {20, 100, 0.20}, {30, 110, 0.27}, {40, 110, 0.36}} {
+		quarters = append(quarters, projection.QuarterStatements{
+			FiscalYear: 2024, Quarter: i + 1,
+			IncomeStatement: &edgar.IncomeStatement{NetIncomeSection: &edgar.NetIncomeSection{
+				NetIncomeToCommon: val(q.ni), WeightedAverageShares: val(q.shares), EPSBasic: val(q.ni / q.shares), EPSDiluted: val(q.eps),
+			}},
+		})
+	}
+	ltm, err := projection.LTM(quarters, 2024, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ni := ltm.IncomeStatement.NetIncomeSection
+	if got := getValue(ni.NetIncomeToCommon); got != 100 {
+		t.Errorf("LTM net income %v, want 100", got)
+	}
+	if got := getValue(ni.WeightedAverageShares); math.Abs(got-105) > 1e-9 {
+		t.Errorf("LTM shares %v, want the 105 average", got)
+	}
+	if got := getValue(ni.EPSBasic); math.Abs(got-100.0/105) > 1e-9 {
+		t.Errorf("LTM basic EPS %.4f, want %.4f", got, 100.0/105)
+	}
+	diluted := (100 + 100 + 30/0.27 + 40/0.36) / 4
+	if got := getValue(ni.EPSDiluted); math.Abs(got-100/diluted) > 1e-9 {
+		t.Errorf("LTM diluted EPS %.4f, want %.4f", got, 100/diluted)
+	}
+}
@@ -0,0 +1,93 @@
+package projection_test
+
+import (
+	"math"
+	"testing"
+
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/projection"
+)
+
+func TestShareCountPolicy_Roll(t *testing.T) {
+	policy := &projection.ShareCountPolicy{
+		BuybackDollars:     map[int]float64{2025: 200},
+		BuybackPayoutRatio: 0.5,
+		RepurchasePrice:    50,
+		SBCGrantPrice:      40,
+		OptionExercises:    map[int]float64{2025: 1},
+		OptionStrike:       20,
+	}
+	roll := policy.Roll(2025, 1000, 80, 100)
+	// 200 / 50 repurchased, 80 / 40 granted, 1 exercised for 20
+	if roll.Opening != 100 || roll.Repurchased != 4 || roll.SBCIssued != 2 || roll.ExerciseProceeds != 20 {
+		t.Errorf("unexpected 2025 roll: %+v", roll)
+	}
+	if roll.Closing != 99 || roll.WeightedAverage != 99.5 {
+		t.Errorf("closing %.2f / average %.2f, want 99 / 99.5", roll.Closing, roll.WeightedAverage)
+	}
+
+	// Without a budget the payout ratio applies, and only to profits
+	if roll := policy.Roll(2026, 1000, 0, 100); roll.BuybackSpend != 500 {
+		t.Errorf("payout buyback %.2f, want 500", roll.BuybackSpend)
+	}
+	if roll := policy.Roll(2026, -1000, 0, 100); roll.BuybackSpend != 0 {
+		t.Errorf("a loss year should not buy back, got %.2f", roll.BuybackSpend)
+	}
+}
+
+func TestBuybacksFromGuidance(t *testing.T) {
+	schedule := projection.BuybacksFromGuidance(&edgar.CapitalAnalysis{BuybackRemaining: 900}, 2025)
+	if len(schedule) != 3 || schedule[2025] != 300 || schedule[2027] != 300 {
+		t.Errorf("expected 900 over the default 3 years, got %v", schedule)
+	}
+	if projection.BuybacksFromGuidance(&edgar.CapitalAnalysis{}, 2025) != nil {
+		t.Error("expected no schedule without an authorization")
+	}
+}
+
+func TestProjectHorizon_ShareCount(t *testing.T) {
+	assumptions := horizonAssumptions()
+	assumptions.SharesOutstanding = 100
+	assumptions.Shares = &projection.ShareCountPolicy{
+		BuybackDollars:  map[int]float64{2025: 20, 2026: 20, 2027: 20},
+		RepurchasePrice: 10,
+		PriceGrowth:     0.25,
+	}
+	path, err := projection.NewProjectionEngine(nil).ProjectHorizon(projection.HorizonInput{
+		History:     horizonHistory(),
+		Assumptions: assumptions,
+		Years:       3,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first := path[0]
+	if first.Shares == nil || first.Shares.Repurchased != 2 || first.Shares.SBCIssued <= 0 {
+		t.Fatalf("unexpected 2025 share roll: %+v", first.Shares)
+	}
+	is := first.IncomeStatement.NetIncomeSection
+	if got := getValue(is.WeightedAverageShares); got != first.Shares.WeightedAverage {
+		t.Errorf("EPS denominator %.4f, want %.4f", got, first.Shares.WeightedAverage)
+	}
+	if eps := getValue(is.EPSBasic); math.Abs(eps-getValue(is.NetIncomeToCommon)/first.Shares.WeightedAverage) > 1e-9 {
+		t.Errorf("EPS %.4f does not use the weighted shares", eps)
+	}
+	if got := getValue(first.CashFlow.FinancingActivities.ShareRepurchases); got != -20 {
+		t.Errorf("buybacks in financing %.2f, want -20", got)
+	}
+	if got := getValue(first.BalanceSheet.Equity.TreasuryStock); got != -20 {
+		t.Errorf("treasury stock %.2f, want -20", got)
+	}
+
+	// The count and price carry: 2026 opens at the 2025 close and buys at 12.5
+	second := path[1].Shares
+	if second.Opening != first.Shares.Closing || second.RepurchasePrice != 12.5 || second.Repurchased != 1.6 {
+		t.Errorf("unexpected 2026 share roll: %+v", second)
+	}
+
+	values := projection.PerShareByYear(path)
+	if len(values) != 3 || math.Abs(values[0].EPS-getValue(is.EPSBasic)) > 1e-9 || values[0].DPS <= 0 {
+		t.Errorf("unexpected per-share values: %+v", values)
+	}
+}
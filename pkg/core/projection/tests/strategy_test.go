@@ -2,6 +2,7 @@ package projection_test
 
 import (
 	"agentic_valuation/pkg/core/projection"
+	"math"
 	"testing"
 )
 
@@ -262,3 +263,123 @@ func TestStrategySelector_ApplyDecision(t *testing.T) {
 		t.Errorf("expected UpdatedBy='AI', got '%s'", skeleton.Revenue.UpdatedBy)
 	}
 }
+
+func TestSCurveStrategy(t *testing.T) {
+	s := &projection.SCurveStrategy{Saturation: 1000, Steepness: 0.5}
+
+	// Growth slows as the value approaches saturation
+	prev, value := 100.0, 100.0
+	for year := 2025; year <= 2040; year++ {
+		next, err := s.Calculate(projection.Context{Year: year, LastYearValue: value})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if next <= value || next >= 1000 {
+			t.Fatalf("year %d: %.2f should rise toward 1000 from %.2f", year, next, value)
+		}
+		prev, value = value, next
+	}
+	if value-prev > 5 {
+		t.Errorf("expected the curve to flatten near saturation, still adding %.2f", value-prev)
+	}
+
+	if _, err := s.Calculate(projection.Context{Year: 2025}); err == nil {
+		t.Error("expected an error without a last year value")
+	}
+}
+
+func TestMeanReversionStrategy(t *testing.T) {
+	s := &projection.MeanReversionStrategy{TargetMean: 0.10, FadeYears: 5}
+
+	value := 0.30
+	for year := 2025; year < 2030; year++ {
+		next, err := s.Calculate(projection.Context{Year: year, LastYearValue: value})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		value = next
+	}
+	// 95% of the 20-point gap closes over the fade period
+	if math.Abs(value-0.11) > 1e-9 {
+		t.Errorf("expected 0.11 after 5 years, got %.6f", value)
+	}
+}
+
+func TestCohortStrategy(t *testing.T) {
+	s := &projection.CohortStrategy{Retention: []float64{1, 0.8, 0.6}}
+	if got := len(s.LaggedReferences()); got != 2 {
+		t.Fatalf("expected two prior cohorts, got %d", got)
+	}
+
+	result, err := s.Calculate(projection.Context{Year: 2025, Drivers: map[string]float64{
+		"new_cohort":      100,
+		"new_cohort[t-1]": 50,
+		"new_cohort[t-2]": 40,
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := 100 + 50*0.8 + 40*0.6; math.Abs(result-expected) > 1e-9 {
+		t.Errorf("expected %.2f, got %.2f", expected, result)
+	}
+}
+
+func TestBacklogStrategy(t *testing.T) {
+	s := &projection.BacklogStrategy{ConversionRate: 0.4}
+	result, err := s.Calculate(projection.Context{Year: 2025, Drivers: map[string]float64{"backlog": 500}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != 200 {
+		t.Errorf("expected 200.00, got %.2f", result)
+	}
+
+	if _, err := (&projection.BacklogStrategy{}).Calculate(projection.Context{Drivers: map[string]float64{"backlog": 500}}); err == nil {
+		t.Error("expected an error without a conversion rate")
+	}
+}
+
+func TestStrategySelector_CreatesNewStrategies(t *testing.T) {
+	selector := projection.NewStrategySelector()
+	for _, name := range []string{"SCurve", "MeanReversion", "Cohort", "Backlog"} {
+		s, err := selector.CreateStrategy(name)
+		if err != nil || s.Name() != name {
+			t.Errorf("CreateStrategy(%q) = %v, %v", name, s, err)
+		}
+	}
+}
+
+func TestProjectHorizon_CohortRevenue(t *testing.T) {
+	skeleton := projection.NewStandardSkeleton()
+	err := projection.NewStrategySelector().ApplyDecision(skeleton, projection.StrategyDecision{
+		NodeID:              "revenue",
+		RecommendedStrategy: "Cohort",
+		StrategyParams:      map[string]float64{"retention_0": 1, "retention_1": 0.9, "retention_2": 0.8},
+		NewDrivers: []*projection.Node{{
+			ID:             "auto_new_cohort",
+			Type:           projection.NodeTypeDriver,
+			StrategyName:   "GrowthRate",
+			StrategyParams: map[string]float64{"growth_rate": 0.10},
+			Values:         map[int]float64{2023: 400, 2024: 500},
+			UpdatedBy:      "AI",
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	path, err := projection.NewProjectionEngine(skeleton).ProjectHorizon(projection.HorizonInput{
+		History:     horizonHistory(),
+		Assumptions: horizonAssumptions(),
+		Years:       2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// New cohorts 550 and 605 join the 2023 and 2024 cohorts
+	for i, want := range []float64{550 + 500*0.9 + 400*0.8, 605 + 550*0.9 + 500*0.8} {
+		if got := getValue(path[i].IncomeStatement.GrossProfitSection.Revenues); math.Abs(got-want) > 1e-6 {
+			t.Errorf("year %d revenue %.4f, want %.4f", path[i].Year, got, want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package projection_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/projection"
+)
+
+func TestTaxPolicy_LossBuildsNOL(t *testing.T) {
+	policy := &projection.TaxPolicy{}
+	roll := policy.Roll(2025, -100, -10, 50, 0.25)
+	if roll.CashTax != 0 || roll.NOLClosing() != 100 {
+		t.Errorf("a loss should pay no cash tax and carry 100 forward: %+v", roll)
+	}
+	if roll.DeferredTaxAssetChange != 25 || roll.BookTax != -25 {
+		t.Errorf("the loss benefit should be booked as a deferred tax asset: %+v", roll)
+	}
+
+	policy.ValuationAllowance = true
+	if roll := policy.Roll(2025, -100, -10, 50, 0.25); roll.BookTax != 0 || roll.DeferredTaxAssetChange != 0 {
+		t.Errorf("a valuation allowance withholds the book benefit: %+v", roll)
+	}
+}
+
+func TestTaxPolicy_UsageCap(t *testing.T) {
+	policy := &projection.TaxPolicy{Jurisdictions: []projection.TaxJurisdiction{{
+		Name: "federal",
+		NOLs: []projection.NOLVintage{
+			{Amount: 40, ExpiryYear: 2024},
+			{Amount: 50, ExpiryYear: 2030},
+			{Amount: 500, Capped: true},
+		},
+	}}}
+	roll := policy.Roll(2025, 200, 0, 0, 0.25)
+	fed := roll.Jurisdictions[0]
+
+	// 40 expired; the 50 expiring loss is uncapped, then 80% of the remaining 150
+	if fed.NOLExpired != 40 || fed.NOLUsed != 170 || math.Abs(roll.CashTax-7.5) > 1e-9 {
+		t.Errorf("unexpected usage: %+v", fed)
+	}
+	if fed.NOLClosing() != 380 {
+		t.Errorf("closing NOL %.2f, want 380", fed.NOLClosing())
+	}
+	if math.Abs(roll.BookTax-(50+0.25*40)) > 1e-9 {
+		t.Errorf("book tax %.4f should be 25%% of pre-tax income plus the expired asset", roll.BookTax)
+	}
+}
+
+func TestTaxPolicy_DeferredTaxLiability(t *testing.T) {
+	policy := &projection.TaxPolicy{TaxDepreciationMultiple: 1.5}
+	roll := policy.Roll(2025, 200, -40, 100, 0.25)
+	if roll.TaxableIncome != 150 || roll.CashTax != 37.5 || roll.DeferredTaxLiabilityChange != 12.5 {
+		t.Errorf("unexpected accelerated depreciation bridge: %+v", roll)
+	}
+	if roll.BookTax != 50 || roll.DeferredTax != 12.5 {
+		t.Errorf("book tax %.2f, deferred %.2f, want 50 and 12.5", roll.BookTax, roll.DeferredTax)
+	}
+	if roll.UnleveredCashTax != 47.5 {
+		t.Errorf("unlevered cash tax %.2f, want 25%% of 190", roll.UnleveredCashTax)
+	}
+}
+
+func TestTaxJurisdictionsFromNotes(t *testing.T) {
+	notes := []*edgar.ExtractedNote{{NoteCategory: edgar.NoteCategoryIncomeTax, StructuredData: map[string]interface{}{
+		"nol_carryforwards": []interface{}{
+			map[string]interface{}{"jurisdiction": "Federal", "amount": 300.0, "expiration": "indefinite"},
+			map[string]interface{}{"jurisdiction": "Federal", "amount": 100.0, "expiration": "2030 through 2037"},
+			map[string]interface{}{"jurisdiction": "State", "amount": 80.0, "expiration": "2029"},
+		},
+	}}}
+	jurisdictions := projection.TaxJurisdictionsFromNotes(notes)
+	if len(jurisdictions) != 2 || jurisdictions[0].Name != "federal" || len(jurisdictions[0].NOLs) != 2 {
+		t.Fatalf("unexpected jurisdictions: %+v", jurisdictions)
+	}
+	fed := jurisdictions[0].NOLs
+	if !fed[0].Capped || fed[0].ExpiryYear != 0 || fed[1].Capped || fed[1].ExpiryYear != 2030 {
+		t.Errorf("unexpected federal vintages: %+v", fed)
+	}
+}
+
+func TestProjectHorizon_Taxes(t *testing.T) {
+	assumptions := horizonAssumptions()
+	assumptions.Tax = &projection.TaxPolicy{}
+	skeleton := projection.NewStandardSkeleton()
+	path, err := projection.NewProjectionEngine(skeleton).ProjectHorizon(projection.HorizonInput{
+		History:     horizonHistory(),
+		Assumptions: assumptions,
+		Schedule:    projection.DriverSchedule{"cogs_percent": {0.95, 0.55}}, // A loss year, then profits
+		Years:       3,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loss := path[0]
+	if loss.Tax == nil || loss.Tax.CashTax != 0 || loss.Tax.NOLClosing() <= 0 {
+		t.Fatalf("the loss year should carry an NOL without cash tax: %+v", loss.Tax)
+	}
+	if got := getValue(loss.BalanceSheet.NoncurrentAssets.DeferredTaxAssetsLT); math.Abs(got-loss.Tax.DeferredTaxAssetChange) > 1e-9 {
+		t.Errorf("deferred tax asset %.4f, want %.4f", got, loss.Tax.DeferredTaxAssetChange)
+	}
+	if got := getValue(loss.CashFlow.OperatingActivities.DeferredTaxes); math.Abs(got-loss.Tax.DeferredTax) > 1e-9 {
+		t.Errorf("deferred taxes in operations %.4f, want %.4f", got, loss.Tax.DeferredTax)
+	}
+
+	// The carryforward shelters at most 80% of the next year's taxable income
+	used := path[1].Tax
+	if used.NOLUsed() <= 0 || used.NOLUsed() > 0.8*used.TaxableIncome+1e-9 {
+		t.Errorf("NOL used %.4f of taxable income %.4f", used.NOLUsed(), used.TaxableIncome)
+	}
+	if math.Abs(used.Jurisdictions[0].NOLOpening-loss.Tax.NOLClosing()) > 1e-9 {
+		t.Errorf("the NOL does not carry between years")
+	}
+
+	for _, p := range path {
+		want := -getValue(p.IncomeStatement.TaxAdjustments.IncomeTaxExpense)
+		if p.NodeValues["tax_expense"] != want || math.Abs(want-p.Tax.BookTax) > 1e-9 {
+			t.Errorf("year %d skeleton tax %.4f, want %.4f", p.Year, p.NodeValues["tax_expense"], want)
+		}
+	}
+
+	if md := projection.TaxMarkdown(path); !strings.Contains(md, "| Closing NOL |") {
+		t.Errorf("markdown missing the NOL row:\n%s", md)
+	}
+}
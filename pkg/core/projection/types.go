@@ -7,10 +7,19 @@ import (
 // ProjectedFinancials holds the articulated statements for a projected year
 type ProjectedFinancials struct {
 	Year            int
+	Quarter         int // Fiscal quarter (1-4) for quarterly projections; 0 = fiscal year
 	IncomeStatement *edgar.IncomeStatement
 	BalanceSheet    *edgar.BalanceSheet
 	CashFlow        *edgar.CashFlowStatement
 	Segments        []edgar.StandardizedSegment // Granular support
+	Interest        *InterestSolve              // How the year's interest was solved
+	Debt            *DebtYear                   // Term debt flows (nil without a DebtSchedule)
+	FixedAssets     *PPERoll                    // PP&E waterfall (nil without a FixedAssetPolicy)
+	Tax             *TaxRoll                    // Book-to-cash tax bridge (nil without a TaxPolicy)
+	Shares          *ShareRoll                  // Share count roll-forward (nil without a ShareCountPolicy)
+	GraphError      error                       // Why the skeleton's strategies could not drive the year
+	NodeValues      map[string]float64          // The year's skeleton node and driver values by ID (nil without a skeleton)
+	Warnings        []string                    // Skeleton strategies skipped by the graph
 }
 
 // ProjectionAssumptions defines the drivers for a specific year
@@ -28,6 +37,9 @@ type ProjectionAssumptions struct {
 	RDPercent float64 // % of Revenue
 	TaxRate   float64 // % of EBT
 
+	// Taxes (nil = TaxRate on pre-tax income, losses included)
+	Tax *TaxPolicy
+
 	// Working Capital Drivers
 	DSO float64 // Days
 	DSI float64 // Days
@@ -40,6 +52,9 @@ type ProjectionAssumptions struct {
 	UsefulLifeForecast  float64 // Years (Gross PPE / Depn)
 	DepreciationPercent float64 // % of Gross PPE
 
+	// Fixed Assets (nil = the depreciation drivers above on gross PP&E)
+	FixedAssets *FixedAssetPolicy
+
 	// Valuation Drivers (Carried through for DCF)
 
 	TerminalGrowth float64 // %
@@ -64,6 +79,14 @@ type ProjectionAssumptions struct {
 	CashInterestRate      float64 // % on Cash Balance
 	DebtInterestRate      float64 // % on Debt Balance
 
+	// Financing Circularity
+	RevolverInterestRate float64            // % on Revolver Balance (0 = DebtInterestRate)
+	MinimumCash          float64            // Cash floor; the revolver funds any shortfall below it
+	InterestConvention   InterestConvention // "" = average balances (solved), or beginning balances
+
+	// Term Debt (nil = LongTermDebt held flat at DebtInterestRate)
+	DebtSchedule *DebtSchedule
+
 	// Working Capital (Percentage Method)
 	ReceivablesPercent     float64 // % of Revenue
 	InventoryPercent       float64 // % of Revenue
@@ -71,5 +94,6 @@ type ProjectionAssumptions struct {
 	DeferredRevenuePercent float64 // % of Revenue
 
 	// Capital Structure
-	SharesOutstanding float64 // Millions
+	SharesOutstanding float64           // Millions
+	Shares            *ShareCountPolicy // Buybacks, SBC and option dilution (nil = SharesOutstanding every year)
 }
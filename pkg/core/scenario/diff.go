@@ -0,0 +1,60 @@
+package scenario
+
+import (
+	"agentic_valuation/pkg/core/assumption"
+	"math"
+)
+
+// Diff applies two scenarios and lists the drivers that differ between them
+func (s *Set) Diff(fromID, toID string) ([]NodeDiff, error) {
+	from, err := s.Apply(fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.Apply(toID)
+	if err != nil {
+		return nil, err
+	}
+	return Diff(from, to), nil
+}
+
+// Diff compares two assumption sets node by node (matched on Variable, sorted by it).
+// Values are compared as decimals so a "%" node and a plain node can be matched.
+func Diff(from, to *assumption.AssumptionSet) []NodeDiff {
+	byVar := func(set *assumption.AssumptionSet) map[string]*assumption.Node {
+		out := make(map[string]*assumption.Node, len(set.Nodes))
+		for _, n := range set.Nodes {
+			if n.Variable != "" {
+				out[n.Variable] = n
+			}
+		}
+		return out
+	}
+	a, b := byVar(from), byVar(to)
+
+	union := make(map[string]bool, len(a)+len(b))
+	for name := range a {
+		union[name] = true
+	}
+	for name := range b {
+		union[name] = true
+	}
+
+	var diffs []NodeDiff
+	for _, name := range sortedKeys(union) {
+		na, inA := a[name]
+		nb, inB := b[name]
+		d := NodeDiff{Variable: name, InFrom: inA, InTo: inB}
+		if inA {
+			d.From, d.Label = na.Decimal(), na.Label
+		}
+		if inB {
+			d.To, d.Label = nb.Decimal(), nb.Label
+		}
+		if inA && inB && math.Abs(d.From-d.To) < 1e-12 {
+			continue
+		}
+		diffs = append(diffs, d)
+	}
+	return diffs
+}
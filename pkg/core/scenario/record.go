@@ -0,0 +1,110 @@
+package scenario
+
+import (
+	"agentic_valuation/pkg/core/assumption"
+	"agentic_valuation/pkg/core/store"
+	"encoding/json"
+	"fmt"
+)
+
+// Records converts a run into projection_scenarios rows (one per outcome)
+func (r *Result) Records() ([]store.ScenarioRecord, error) {
+	out := make([]store.ScenarioRecord, 0, len(r.Outcomes))
+	for i := range r.Outcomes {
+		o := &r.Outcomes[i]
+		rec, err := NewRecord(r.CaseID, o.Scenario, o.Assumptions, o)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rec)
+	}
+	return out, nil
+}
+
+// NewRecord serializes a scenario with its applied set and optional outcome for storage
+func NewRecord(caseID string, sc Scenario, set *assumption.AssumptionSet, outcome *Outcome) (store.ScenarioRecord, error) {
+	rec := store.ScenarioRecord{
+		CaseID:      caseID,
+		ScenarioID:  sc.ID,
+		Name:        sc.Name,
+		Description: sc.Description,
+		Probability: sc.Probability,
+		Overrides:   sc.Overrides,
+	}
+	if set != nil {
+		data, err := set.ToJSON()
+		if err != nil {
+			return rec, fmt.Errorf("scenario '%s': failed to marshal assumption set: %w", sc.ID, err)
+		}
+		rec.AssumptionSet = data
+	}
+	if outcome != nil {
+		data, err := json.Marshal(outcome)
+		if err != nil {
+			return rec, fmt.Errorf("scenario '%s': failed to marshal outcome: %w", sc.ID, err)
+		}
+		rec.Results = data
+	}
+	return rec, nil
+}
+
+// FromRecords rebuilds a scenario set from stored rows around a base assumption set
+func FromRecords(base *assumption.AssumptionSet, records []store.ScenarioRecord) (*Set, error) {
+	set := NewSet(base)
+	for _, rec := range records {
+		sc := Scenario{
+			ID:          rec.ScenarioID,
+			Name:        rec.Name,
+			Description: rec.Description,
+			Probability: rec.Probability,
+			Overrides:   rec.Overrides,
+		}
+		if err := set.Add(sc); err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
+
+// ResultFromRecords rebuilds a run from the outcomes stored with each record,
+// re-weighting by the stored probabilities. Records without results are skipped.
+func ResultFromRecords(caseID string, records []store.ScenarioRecord) (*Result, error) {
+	res := &Result{CaseID: caseID, WeightedByModel: make(map[string]float64)}
+	total := 0.0
+	for _, rec := range records {
+		if len(rec.Results) == 0 {
+			continue
+		}
+		var out Outcome
+		if err := json.Unmarshal(rec.Results, &out); err != nil {
+			return nil, fmt.Errorf("scenario '%s': failed to unmarshal outcome: %w", rec.ScenarioID, err)
+		}
+		out.Probability = rec.Probability
+		total += rec.Probability
+		res.Outcomes = append(res.Outcomes, out)
+	}
+	if len(res.Outcomes) == 0 {
+		return nil, fmt.Errorf("case %s has no stored scenario results", caseID)
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("scenario probabilities must sum to a positive number")
+	}
+	for i := range res.Outcomes {
+		out := &res.Outcomes[i]
+		out.Probability /= total
+		res.WeightedSharePrice += out.Probability * out.SharePrice
+		res.WeightedEquityValue += out.Probability * out.EquityValue
+		for _, item := range out.Valuations {
+			res.WeightedByModel[item.ModelName] += out.Probability * item.SharePrice
+		}
+	}
+	return res, nil
+}
+
+// AppliedSet decodes the assumption set stored with a record
+func AppliedSet(rec store.ScenarioRecord) (*assumption.AssumptionSet, error) {
+	if len(rec.AssumptionSet) == 0 {
+		return nil, fmt.Errorf("scenario '%s' has no stored assumption set", rec.ScenarioID)
+	}
+	return assumption.FromJSON(rec.AssumptionSet)
+}
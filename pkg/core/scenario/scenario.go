@@ -0,0 +1,309 @@
+package scenario
+
+import (
+	"agentic_valuation/pkg/core/assumption"
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/projection"
+	"agentic_valuation/pkg/core/valuation"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NewSet creates an empty scenario set around a base assumption set
+func NewSet(base *assumption.AssumptionSet) *Set {
+	return &Set{CaseID: base.CaseID, Base: base}
+}
+
+// Add registers a scenario (IDs must be unique within the set)
+func (s *Set) Add(sc Scenario) error {
+	if sc.ID == "" {
+		return fmt.Errorf("scenario ID cannot be empty")
+	}
+	if sc.Probability < 0 {
+		return fmt.Errorf("scenario '%s': probability must not be negative", sc.ID)
+	}
+	if _, err := s.Get(sc.ID); err == nil {
+		return fmt.Errorf("scenario '%s' already exists", sc.ID)
+	}
+	if sc.Name == "" {
+		sc.Name = sc.ID
+	}
+	s.Scenarios = append(s.Scenarios, sc)
+	return nil
+}
+
+// Get retrieves a scenario by ID
+func (s *Set) Get(id string) (*Scenario, error) {
+	for i := range s.Scenarios {
+		if s.Scenarios[i].ID == id {
+			return &s.Scenarios[i], nil
+		}
+	}
+	return nil, fmt.Errorf("scenario '%s' not found", id)
+}
+
+// Apply clones the base set and writes the scenario's overrides onto it.
+// An override for a variable without a node adds a constant node for it.
+func (s *Set) Apply(id string) (*assumption.AssumptionSet, error) {
+	sc, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if s.Base == nil {
+		return nil, fmt.Errorf("scenario set has no base assumption set")
+	}
+	set, err := s.Base.Clone()
+	if err != nil {
+		return nil, err
+	}
+	set.ScenarioID = sc.ID
+
+	for _, name := range sortedKeys(sc.Overrides) {
+		v := sc.Overrides[name]
+		if node, ok := set.NodeByVariable(name); ok {
+			node.SetDecimal(v)
+			continue
+		}
+		node := &assumption.Node{
+			ID:        "scenario-" + name,
+			Label:     name,
+			Variable:  name,
+			Value:     v,
+			TrendType: assumption.TrendConstant,
+			IsAtomic:  true,
+		}
+		if err := set.AddNode(node); err != nil {
+			return nil, fmt.Errorf("scenario '%s': %w", sc.ID, err)
+		}
+	}
+	return set, nil
+}
+
+// Run projects and values every scenario and weights the results by probability.
+// Probabilities are normalized to sum to one.
+func (s *Set) Run(in RunInput) (*Result, error) {
+	if len(s.Scenarios) == 0 {
+		return nil, fmt.Errorf("scenario set is empty")
+	}
+	if in.Years <= 0 {
+		return nil, fmt.Errorf("forecast horizon must be at least one year")
+	}
+	total := 0.0
+	for _, sc := range s.Scenarios {
+		total += sc.Probability
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("scenario probabilities must sum to a positive number")
+	}
+
+	res := &Result{CaseID: s.CaseID, WeightedByModel: make(map[string]float64)}
+	for _, sc := range s.Scenarios {
+		set, err := s.Apply(sc.ID)
+		if err != nil {
+			return nil, err
+		}
+		out, err := runScenario(set, in)
+		if err != nil {
+			return nil, fmt.Errorf("scenario '%s': %w", sc.ID, err)
+		}
+		out.Scenario = sc
+		out.Probability = sc.Probability / total
+
+		res.WeightedSharePrice += out.Probability * out.SharePrice
+		res.WeightedEquityValue += out.Probability * out.EquityValue
+		for _, item := range out.Valuations {
+			res.WeightedByModel[item.ModelName] += out.Probability * item.SharePrice
+		}
+		res.Outcomes = append(res.Outcomes, *out)
+	}
+	return res, nil
+}
+
+// FootballBars spans each model's scenario values around its probability-weighted
+// price: the FCFF DCF as the DCF bar, then the other RunAllValuations models
+func (r *Result) FootballBars() []valuation.FootballBar {
+	if len(r.Outcomes) == 0 {
+		return nil
+	}
+	dcf := make([]float64, len(r.Outcomes))
+	for i, out := range r.Outcomes {
+		dcf[i] = out.SharePrice
+	}
+	bars := []valuation.FootballBar{valuation.RangeBar(valuation.FootballDCF, r.WeightedSharePrice, dcf)}
+	for _, item := range r.Outcomes[0].Valuations {
+		if item.ModelName == valuation.ModelFCFF {
+			continue
+		}
+		var prices []float64
+		for _, out := range r.Outcomes {
+			for _, v := range out.Valuations {
+				if v.ModelName == item.ModelName {
+					prices = append(prices, v.SharePrice)
+				}
+			}
+		}
+		bars = append(bars, valuation.RangeBar(item.ModelName, r.WeightedByModel[item.ModelName], prices))
+	}
+	return bars
+}
+
+// Drivers overlays the set on the template via ToProjection (values and yearly
+// paths) and sets valuation-level drivers (WACC, net debt, ...) on the input.
+// Nodes whose variable is not an engine driver are returned as ignored.
+func Drivers(set *assumption.AssumptionSet, template projection.ProjectionAssumptions, input valuation.MasterValuationInput) (projection.ProjectionAssumptions, projection.DriverSchedule, valuation.MasterValuationInput, []string, error) {
+	a, schedule, err := set.ToProjection(template)
+	if err != nil {
+		return a, nil, input, nil, err
+	}
+	var ignored []string
+	for _, id := range sortedKeys(set.Nodes) {
+		node := set.Nodes[id]
+		if node.Variable == "" {
+			continue
+		}
+		if !input.SetDriver(node.Variable, node.Decimal()) && !projection.IsDriverName(node.Variable) {
+			ignored = append(ignored, node.Variable)
+		}
+	}
+	return a, schedule, input, ignored, nil
+}
+
+// runScenario projects and values one applied assumption set
+func runScenario(set *assumption.AssumptionSet, in RunInput) (*Outcome, error) {
+	a, schedule, input, ignored, err := Drivers(set, in.Template, in.Valuation)
+	if err != nil {
+		return nil, err
+	}
+	projections, err := projection.NewProjectionEngine(set.Skeleton).ProjectHorizon(projection.HorizonInput{
+		History:     in.History,
+		Assumptions: a,
+		Schedule:    schedule,
+		Years:       in.Years,
+	})
+	if err != nil {
+		return nil, err
+	}
+	input.Projections = projections
+
+	dcf := valuation.CalculateDCF(input.DCFInput())
+	valuations, warnings := valuation.RunAllValuations(input)
+	out := &Outcome{
+		KeyItems:    keyItems(projections),
+		Valuations:  valuations,
+		Warnings:    warnings,
+		SharePrice:  dcf.SharePrice,
+		EquityValue: dcf.EquityValue,
+		Ignored:     ignored,
+		Assumptions: set,
+		Projections: projections,
+	}
+	for _, p := range projections {
+		out.Years = append(out.Years, p.Year)
+	}
+	return out, nil
+}
+
+// keyItemNames fixes the row order of the comparison
+var keyItemNames = []string{"Revenue", "Operating Income", "Net Income", "Operating Cash Flow", "Free Cash Flow", "Cash", "Debt"}
+
+// keyItems extracts the comparison line items from each projected year
+func keyItems(projections []*projection.ProjectedFinancials) []KeyItem {
+	items := make([]KeyItem, len(keyItemNames))
+	for i, name := range keyItemNames {
+		items[i] = KeyItem{Name: name, Values: make([]float64, len(projections))}
+	}
+	for y, p := range projections {
+		var rev, opInc, ni, cfo, capex, cash, debt float64
+		if is := p.IncomeStatement; is != nil {
+			if is.GrossProfitSection != nil {
+				rev = value(is.GrossProfitSection.Revenues)
+			}
+			if is.OperatingCostSection != nil {
+				opInc = value(is.OperatingCostSection.OperatingIncome)
+			}
+			if is.NetIncomeSection != nil {
+				ni = value(is.NetIncomeSection.NetIncomeToCommon)
+			}
+		}
+		if cf := p.CashFlow; cf != nil {
+			if cf.CashSummary != nil {
+				cfo = value(cf.CashSummary.NetCashOperating)
+			}
+			if cf.InvestingActivities != nil {
+				capex = value(cf.InvestingActivities.Capex) // Negative
+			}
+		}
+		if bs := p.BalanceSheet; bs != nil {
+			cash = value(bs.CurrentAssets.CashAndEquivalents)
+			debt = value(bs.NoncurrentLiabilities.LongTermDebt) +
+				value(bs.CurrentLiabilities.NotesPayableShortTermDebt) +
+				value(bs.CurrentLiabilities.CurrentMaturitiesLTD)
+		}
+		for i, v := range []float64{rev, opInc, ni, cfo, cfo + capex, cash, debt} {
+			items[i].Values[y] = v
+		}
+	}
+	return items
+}
+
+// Comparison lines up the final-year key items and share prices of every scenario
+func (r *Result) Comparison() []ComparisonRow {
+	if len(r.Outcomes) == 0 {
+		return nil
+	}
+	rows := make([]ComparisonRow, 0, len(keyItemNames)+1)
+	for i, name := range keyItemNames {
+		row := ComparisonRow{Name: name, Values: make([]float64, len(r.Outcomes))}
+		for j, o := range r.Outcomes {
+			if vals := o.KeyItems[i].Values; len(vals) > 0 {
+				row.Values[j] = vals[len(vals)-1]
+			}
+			row.Weighted += o.Probability * row.Values[j]
+		}
+		rows = append(rows, row)
+	}
+	price := ComparisonRow{Name: "Share Price (FCFF)", Values: make([]float64, len(r.Outcomes)), Weighted: r.WeightedSharePrice}
+	for j, o := range r.Outcomes {
+		price.Values[j] = o.SharePrice
+	}
+	return append(rows, price)
+}
+
+// Markdown renders the side-by-side comparison for the report
+func (r *Result) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "**Scenario Analysis (probability-weighted $%.2f)**\n\n", r.WeightedSharePrice)
+
+	sb.WriteString("| Line Item |")
+	for _, o := range r.Outcomes {
+		fmt.Fprintf(&sb, " %s (%.0f%%) |", o.Scenario.Name, o.Probability*100)
+	}
+	sb.WriteString(" Weighted |\n|---|")
+	sb.WriteString(strings.Repeat("---|", len(r.Outcomes)+1))
+	sb.WriteString("\n")
+	for _, row := range r.Comparison() {
+		fmt.Fprintf(&sb, "| %s |", row.Name)
+		for _, v := range row.Values {
+			fmt.Fprintf(&sb, " %.2f |", v)
+		}
+		fmt.Fprintf(&sb, " %.2f |\n", row.Weighted)
+	}
+	return sb.String()
+}
+
+func value(v *edgar.FSAPValue) float64 {
+	if v == nil || v.Value == nil {
+		return 0
+	}
+	return *v.Value
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,211 @@
+package scenario
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"agentic_valuation/pkg/core/assumption"
+	"agentic_valuation/pkg/core/internal/testfixture"
+	"agentic_valuation/pkg/core/projection"
+	"agentic_valuation/pkg/core/valuation"
+)
+
+func testInput() RunInput {
+	return RunInput{
+		Years:     5,
+		History:   testfixture.History(),
+		Template:  testfixture.Assumptions(),
+		Valuation: testfixture.Valuation(),
+	}
+}
+
+func testSet(t *testing.T) *Set {
+	t.Helper()
+	base := assumption.NewAssumptionSet("case-1", Base)
+	if err := base.AddNode(&assumption.Node{ID: "rev-growth", Label: "Revenue Growth", Variable: "revenue_growth", Value: 5, Unit: "%"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := base.AddNode(&assumption.Node{ID: "ebit-margin", Label: "EBIT Margin", Variable: "ebit_margin", Value: 30, Unit: "%"}); err != nil {
+		t.Fatal(err)
+	}
+
+	set := NewSet(base)
+	for _, sc := range []Scenario{
+		{ID: Bull, Probability: 1, Overrides: map[string]float64{"revenue_growth": 0.10, "wacc": 0.085}},
+		{ID: Base, Probability: 2},
+		{ID: Bear, Probability: 1, Overrides: map[string]float64{"revenue_growth": 0.0, "cogs_percent": 0.60}},
+	} {
+		if err := set.Add(sc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return set
+}
+
+func TestApply_ClonesAndOverrides(t *testing.T) {
+	set := testSet(t)
+
+	bull, err := set.Apply(Bull)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bull.ScenarioID != Bull {
+		t.Errorf("scenario ID %q, want %q", bull.ScenarioID, Bull)
+	}
+	node, _ := bull.NodeByVariable("revenue_growth")
+	if node.Value != 10 || node.Decimal() != 0.10 {
+		t.Errorf("override written as %.2f (%s), want 10%%", node.Value, node.Unit)
+	}
+	if _, ok := bull.NodeByVariable("wacc"); !ok {
+		t.Error("override without a node should add one")
+	}
+
+	// The base set is untouched
+	if n, _ := set.Base.NodeByVariable("revenue_growth"); n.Value != 5 {
+		t.Errorf("base node mutated to %.2f", n.Value)
+	}
+	if err := set.Add(Scenario{ID: Bull}); err == nil {
+		t.Error("expected duplicate scenario ID to be rejected")
+	}
+}
+
+func TestRun_ProbabilityWeighted(t *testing.T) {
+	set := testSet(t)
+	res, err := set.Run(testInput())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Outcomes) != 3 {
+		t.Fatalf("expected 3 outcomes, got %d", len(res.Outcomes))
+	}
+
+	bull, base, bear := res.Outcomes[0], res.Outcomes[1], res.Outcomes[2]
+	if !(bull.SharePrice > base.SharePrice && base.SharePrice > bear.SharePrice) {
+		t.Errorf("expected bull > base > bear, got %.2f / %.2f / %.2f", bull.SharePrice, base.SharePrice, bear.SharePrice)
+	}
+	if base.Probability != 0.5 {
+		t.Errorf("base weight %.2f, want 0.50 after normalization", base.Probability)
+	}
+	want := 0.25*bull.SharePrice + 0.5*base.SharePrice + 0.25*bear.SharePrice
+	if math.Abs(res.WeightedSharePrice-want) > 1e-9 {
+		t.Errorf("weighted price %.4f, want %.4f", res.WeightedSharePrice, want)
+	}
+	if len(base.Ignored) != 1 || base.Ignored[0] != "ebit_margin" {
+		t.Errorf("expected ebit_margin to be reported as ignored, got %v", base.Ignored)
+	}
+
+	// The base scenario reproduces a direct run of the template
+	in := testInput()
+	projections, err := projection.NewProjectionEngine(nil).ProjectHorizon(projection.HorizonInput{History: in.History, Assumptions: in.Template, Years: in.Years})
+	if err != nil {
+		t.Fatal(err)
+	}
+	in.Valuation.Projections = projections
+	if direct := valuation.CalculateDCF(in.Valuation.DCFInput()).SharePrice; math.Abs(direct-base.SharePrice) > 1e-9 {
+		t.Errorf("base scenario %.4f, direct DCF %.4f", base.SharePrice, direct)
+	}
+
+	rows := res.Comparison()
+	if rows[0].Name != "Revenue" || !(rows[0].Values[0] > rows[0].Values[1] && rows[0].Values[1] > rows[0].Values[2]) {
+		t.Errorf("unexpected revenue comparison: %+v", rows[0])
+	}
+	if md := res.Markdown(); !strings.Contains(md, "| bull (25%) |") {
+		t.Errorf("markdown missing scenario header:\n%s", md)
+	}
+}
+
+func TestRun_RejectsZeroProbabilities(t *testing.T) {
+	set := NewSet(assumption.NewAssumptionSet("case-1", Base))
+	_ = set.Add(Scenario{ID: Base})
+	if _, err := set.Run(testInput()); err == nil {
+		t.Error("expected error when probabilities sum to zero")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	set := testSet(t)
+	diffs, err := set.Diff(Bull, Bear)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := make(map[string]NodeDiff)
+	for _, d := range diffs {
+		got[d.Variable] = d
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected cogs_percent, revenue_growth and wacc to differ, got %+v", diffs)
+	}
+	if d := got["revenue_growth"]; d.From != 0.10 || d.To != 0 {
+		t.Errorf("revenue_growth diff %+v", d)
+	}
+	if d := got["wacc"]; !d.InFrom || d.InTo {
+		t.Errorf("wacc should only exist in bull: %+v", d)
+	}
+	if d := got["cogs_percent"]; d.InFrom || !d.InTo || d.To != 0.60 {
+		t.Errorf("cogs_percent should only exist in bear: %+v", d)
+	}
+}
+
+func TestRun_SkeletonStrategiesDriveScenarios(t *testing.T) {
+	set := testSet(t)
+	selector := projection.NewStrategySelector()
+	decision := selector.SelectStrategy(projection.DriverDiscovery{NodeID: "cogs"})
+	decision.StrategyParams = map[string]float64{"margin_percent": 0.45}
+	if err := selector.ApplyDecision(set.Base.Skeleton, decision); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	res, err := set.Run(testInput())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, out := range res.Outcomes {
+		gp := out.Projections[0].IncomeStatement.GrossProfitSection
+		if rev, cogs := *gp.Revenues.Value, *gp.CostOfGoodsSold.Value; math.Abs(cogs+0.45*rev) > 1e-6 {
+			t.Errorf("%s: COGS %.4f, want the strategy's 45%% of %.4f", out.Scenario.ID, cogs, rev)
+		}
+	}
+}
+
+func TestResultFromRecords_FootballBars(t *testing.T) {
+	res, err := testSet(t).Run(testInput())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	records, err := res.Records()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stored, err := ResultFromRecords("case-1", records)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(stored.WeightedSharePrice-res.WeightedSharePrice) > 1e-9 {
+		t.Errorf("stored weighted price %.4f, run %.4f", stored.WeightedSharePrice, res.WeightedSharePrice)
+	}
+
+	bars := stored.FootballBars()
+	if len(bars) != len(res.Outcomes[0].Valuations) {
+		t.Fatalf("expected the DCF bar plus one per other model, got %d bars", len(bars))
+	}
+	dcf := bars[0]
+	bull, bear := res.Outcomes[0].SharePrice, res.Outcomes[2].SharePrice
+	if dcf.Model != valuation.FootballDCF || dcf.Low != bear || dcf.High != bull || dcf.Base != stored.WeightedSharePrice {
+		t.Errorf("DCF bar %+v, want bear %.2f / weighted / bull %.2f", dcf, bear, bull)
+	}
+	for _, b := range bars[1:] {
+		if b.Model == valuation.ModelFCFF {
+			t.Error("the FCFF line duplicates the DCF bar")
+		}
+	}
+
+	records[0].Results = nil
+	records[1].Results = nil
+	records[2].Results = nil
+	if _, err := ResultFromRecords("case-1", records); err == nil {
+		t.Error("expected error without stored results")
+	}
+}
@@ -0,0 +1,91 @@
+// Package scenario manages bull / base / bear cases for one valuation case.
+// Each scenario clones the case's assumption.AssumptionSet, applies named
+// driver overrides, and is projected and valued on its own; the results are
+// combined into a probability-weighted value and a side-by-side comparison.
+package scenario
+
+import (
+	"agentic_valuation/pkg/core/assumption"
+	"agentic_valuation/pkg/core/projection"
+	"agentic_valuation/pkg/core/valuation"
+)
+
+// Conventional scenario IDs
+const (
+	Bull = "bull"
+	Base = "base"
+	Bear = "bear"
+)
+
+// Scenario is a named set of driver overrides with a probability.
+// Override keys are node variables or driver names (see projection.DriverNames
+// and valuation.MasterValuationInput.SetDriver); values are decimals (0.05 = 5%).
+type Scenario struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Probability float64            `json:"probability"`
+	Overrides   map[string]float64 `json:"overrides"`
+}
+
+// Set holds the scenarios of one case around a shared base assumption set
+type Set struct {
+	CaseID    string                    `json:"case_id"`
+	Base      *assumption.AssumptionSet `json:"base"`
+	Scenarios []Scenario                `json:"scenarios"`
+}
+
+// RunInput is the engine context shared by every scenario
+type RunInput struct {
+	Template  projection.ProjectionAssumptions // Baseline the set's drivers are overlaid on
+	Valuation valuation.MasterValuationInput   // Rates and bridge; Projections are replaced per scenario
+	History   projection.History               // T-0 statements the forecast rolls from
+	Years     int                              // Forecast horizon
+}
+
+// KeyItem is one line item across the forecast years
+type KeyItem struct {
+	Name   string    `json:"name"`
+	Values []float64 `json:"values"` // Per projected year
+}
+
+// Outcome is one scenario after projection and valuation
+type Outcome struct {
+	Scenario    Scenario                          `json:"scenario"`
+	Probability float64                           `json:"probability"` // Normalized weight
+	Years       []int                             `json:"years"`
+	KeyItems    []KeyItem                         `json:"key_items"`
+	Valuations  []valuation.ValuationLineItem     `json:"valuations"`
+	SharePrice  float64                           `json:"share_price"` // FCFF DCF per share
+	EquityValue float64                           `json:"equity_value"`
+	Ignored     []string                          `json:"ignored,omitempty"`  // Node variables the engine has no driver for
+	Warnings    []string                          `json:"warnings,omitempty"` // RunAllValuations model warnings
+	Assumptions *assumption.AssumptionSet         `json:"-"`
+	Projections []*projection.ProjectedFinancials `json:"-"`
+}
+
+// Result combines the scenarios into probability-weighted values
+type Result struct {
+	CaseID              string             `json:"case_id"`
+	Outcomes            []Outcome          `json:"outcomes"`
+	WeightedSharePrice  float64            `json:"weighted_share_price"` // FCFF DCF
+	WeightedEquityValue float64            `json:"weighted_equity_value"`
+	WeightedByModel     map[string]float64 `json:"weighted_by_model"` // Model name -> weighted share price
+}
+
+// ComparisonRow is one line item side by side across scenarios (final forecast year)
+type ComparisonRow struct {
+	Name     string    `json:"name"`
+	Values   []float64 `json:"values"` // In Result.Outcomes order
+	Weighted float64   `json:"weighted"`
+}
+
+// NodeDiff is one driver that differs between two scenarios
+type NodeDiff struct {
+	Variable string  `json:"variable"`
+	Label    string  `json:"label,omitempty"`
+	From     float64 `json:"from"` // Decimal value in the first scenario
+	To       float64 `json:"to"`
+	InFrom   bool    `json:"in_from"` // False when the node only exists in the second scenario
+	InTo     bool    `json:"in_to"`
+}
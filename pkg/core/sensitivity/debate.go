@@ -0,0 +1,31 @@
+package sensitivity
+
+import (
+	"agentic_valuation/pkg/core/debate"
+	"agentic_valuation/pkg/core/valuation"
+)
+
+// debateTornadoDrivers are ranked for the debate's Quant findings
+var debateTornadoDrivers = []string{"revenue_growth", "cogs_percent", "sga_percent", "wacc", "terminal_growth"}
+
+// debateTornadoShock flexes each debate driver by ±10% of its base value
+const debateTornadoShock = 0.10
+
+// DebateTornado ranks the DCF value drivers of the pool's valuation.NewDebateCase
+// and renders the tornado. It satisfies debate.SensitivityRanker.
+func DebateTornado(pool *debate.MaterialPool, baselines map[string]float64) (string, error) {
+	dc, err := valuation.NewDebateCase(pool, baselines)
+	if err != nil {
+		return "", err
+	}
+	base := Base{Valuation: dc.Valuation, Assumptions: dc.Assumptions, History: dc.History}
+	shocks, err := RelativeShocks(base, debateTornadoShock, debateTornadoDrivers...)
+	if err != nil {
+		return "", err
+	}
+	t, err := TornadoAnalysis(base, ModelDCF, shocks)
+	if err != nil {
+		return "", err
+	}
+	return t.Markdown(), nil
+}
@@ -0,0 +1,196 @@
+package sensitivity
+
+import (
+	"agentic_valuation/pkg/core/projection"
+	"agentic_valuation/pkg/core/valuation"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Steps returns n evenly spaced values from lo to hi inclusive (n >= 2)
+func Steps(lo, hi float64, n int) []float64 {
+	if n < 2 {
+		return []float64{lo}
+	}
+	out := make([]float64, n)
+	step := (hi - lo) / float64(n-1)
+	for i := range out {
+		out[i] = lo + float64(i)*step
+	}
+	return out
+}
+
+// Around returns values centered on base: base + k*step for k in -n..n
+func Around(base, step float64, n int) []float64 {
+	out := make([]float64, 0, 2*n+1)
+	for k := -n; k <= n; k++ {
+		out = append(out, base+float64(k)*step)
+	}
+	return out
+}
+
+// TwoWay flexes two drivers over their ranges and values every cell with all models
+func TwoWay(base Base, row, col Range) (*Grid, error) {
+	if row.Driver == col.Driver {
+		return nil, fmt.Errorf("row and column drivers must differ (got '%s')", row.Driver)
+	}
+	if len(row.Values) == 0 || len(col.Values) == 0 {
+		return nil, fmt.Errorf("sensitivity ranges must not be empty")
+	}
+
+	basePrices, err := evaluate(base, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to value base case: %w", err)
+	}
+
+	grid := &Grid{
+		Row:        row,
+		Col:        col,
+		Prices:     make(map[Model][][]float64, len(AllModels)),
+		BasePrices: basePrices,
+	}
+	for _, m := range AllModels {
+		grid.Prices[m] = make([][]float64, len(row.Values))
+		for i := range grid.Prices[m] {
+			grid.Prices[m][i] = make([]float64, len(col.Values))
+		}
+	}
+
+	for i, rv := range row.Values {
+		for j, cv := range col.Values {
+			prices, err := evaluate(base, map[string]float64{row.Driver: rv, col.Driver: cv})
+			if err != nil {
+				return nil, fmt.Errorf("cell %s=%.4f, %s=%.4f: %w", row.Driver, rv, col.Driver, cv, err)
+			}
+			for _, m := range AllModels {
+				grid.Prices[m][i][j] = prices[m]
+			}
+		}
+	}
+	return grid, nil
+}
+
+// TornadoAnalysis moves each driver to its low and high value one at a time
+// and ranks drivers by the resulting swing in the chosen model's share price
+func TornadoAnalysis(base Base, model Model, shocks []Shock) (*Tornado, error) {
+	basePrices, err := evaluate(base, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to value base case: %w", err)
+	}
+	if _, ok := basePrices[model]; !ok {
+		return nil, fmt.Errorf("unknown model '%s'", model)
+	}
+
+	t := &Tornado{Model: model, BasePrice: basePrices[model]}
+	for _, s := range shocks {
+		low, err := evaluate(base, map[string]float64{s.Driver: s.Low})
+		if err != nil {
+			return nil, fmt.Errorf("driver '%s' low case: %w", s.Driver, err)
+		}
+		high, err := evaluate(base, map[string]float64{s.Driver: s.High})
+		if err != nil {
+			return nil, fmt.Errorf("driver '%s' high case: %w", s.Driver, err)
+		}
+		bar := TornadoBar{
+			Driver:    s.Driver,
+			LowValue:  s.Low,
+			HighValue: s.High,
+			LowPrice:  low[model],
+			HighPrice: high[model],
+		}
+		bar.Swing = bar.HighPrice - bar.LowPrice
+		if bar.Swing < 0 {
+			bar.Swing = -bar.Swing
+		}
+		t.Bars = append(t.Bars, bar)
+	}
+
+	sort.SliceStable(t.Bars, func(i, j int) bool { return t.Bars[i].Swing > t.Bars[j].Swing })
+	return t, nil
+}
+
+// FootballBar spans every cell of the grid for one model around its base price
+func (g *Grid) FootballBar(model Model) valuation.FootballBar {
+	var cells []float64
+	for _, row := range g.Prices[model] {
+		cells = append(cells, row...)
+	}
+	name := string(model)
+	if model == ModelDCF {
+		name = valuation.FootballDCF
+	}
+	return valuation.RangeBar(name, g.BasePrices[model], cells)
+}
+
+// RelativeShocks builds symmetric ±pct shocks around the base value of each driver
+// e.g. RelativeShocks(base, 0.10, "wacc", "revenue_growth") flexes each driver by ±10%
+func RelativeShocks(base Base, pct float64, drivers ...string) ([]Shock, error) {
+	shocks := make([]Shock, 0, len(drivers))
+	for _, d := range drivers {
+		v, err := valuation.LookupDriver(&base.Assumptions, &base.Valuation, d)
+		if err != nil {
+			return nil, err
+		}
+		shocks = append(shocks, Shock{Driver: d, Low: v * (1 - pct), High: v * (1 + pct)})
+	}
+	return shocks, nil
+}
+
+// Markdown renders the tornado as a table (DebateTornado feeds it to the debate)
+func (t *Tornado) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "**Value Drivers (%s, base $%.2f)**\n\n", t.Model, t.BasePrice)
+	sb.WriteString("| Driver | Low | High | Price @ Low | Price @ High | Swing |\n")
+	sb.WriteString("|---|---|---|---|---|---|\n")
+	for _, b := range t.Bars {
+		fmt.Fprintf(&sb, "| %s | %.4f | %.4f | %.2f | %.2f | %.2f |\n",
+			b.Driver, b.LowValue, b.HighValue, b.LowPrice, b.HighPrice, b.Swing)
+	}
+	return sb.String()
+}
+
+// evaluate applies the overrides, re-projects when a projection driver moved,
+// and returns the share price of every model
+func evaluate(base Base, overrides map[string]float64) (map[Model]float64, error) {
+	assumptions := base.Assumptions.Clone()
+	input := base.Valuation
+
+	reproject := false
+	for name, v := range overrides {
+		if err := valuation.ApplyDriver(&assumptions, &input, name, v); err != nil {
+			return nil, err
+		}
+		if projection.IsDriverName(name) {
+			reproject = true
+		}
+	}
+
+	if reproject {
+		projections, err := reprojectPath(base.History, base.Skeleton, assumptions, len(base.Valuation.Projections))
+		if err != nil {
+			return nil, err
+		}
+		input.Projections = projections
+	}
+
+	eq := input.EquityInput()
+	return map[Model]float64{
+		ModelDCF:              valuation.CalculateDCF(input.DCFInput()).SharePrice,
+		ModelResidualIncome:   valuation.CalculateResidualIncome(eq).SharePrice,
+		ModelFCFE:             valuation.CalculateFCFE(eq).SharePrice,
+		ModelDividendDiscount: valuation.CalculateDDM(eq).SharePrice,
+	}, nil
+}
+
+// reprojectPath rolls the history forward with the flexed assumptions
+func reprojectPath(hist History, skeleton *projection.StandardSkeleton, assumptions projection.ProjectionAssumptions, years int) ([]*projection.ProjectedFinancials, error) {
+	if years == 0 {
+		return nil, fmt.Errorf("base valuation has no projections to re-create")
+	}
+	return projection.NewProjectionEngine(skeleton).ProjectHorizon(projection.HorizonInput{
+		History:     hist,
+		Assumptions: assumptions,
+		Years:       years,
+	})
+}
@@ -0,0 +1,135 @@
+package sensitivity
+
+import (
+	"strings"
+	"testing"
+
+	"agentic_valuation/pkg/core/debate"
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/internal/testfixture"
+)
+
+func testBase(t *testing.T) Base {
+	t.Helper()
+
+	hist := testfixture.History()
+	assumptions := testfixture.Assumptions()
+
+	projections, err := reprojectPath(hist, nil, assumptions, 5)
+	if err != nil {
+		t.Fatalf("reproject: %v", err)
+	}
+
+	input := testfixture.Valuation()
+	input.Projections = projections
+	return Base{Assumptions: assumptions, History: hist, Valuation: input}
+}
+
+func TestTwoWay_WACCxTerminalGrowth(t *testing.T) {
+	base := testBase(t)
+	grid, err := TwoWay(base,
+		Range{Driver: "wacc", Values: Steps(0.08, 0.10, 3)},
+		Range{Driver: "terminal_growth", Values: Steps(0.01, 0.03, 3)},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dcf := grid.Prices[ModelDCF]
+	if len(dcf) != 3 || len(dcf[0]) != 3 {
+		t.Fatalf("expected 3x3 grid, got %dx%d", len(dcf), len(dcf[0]))
+	}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if i > 0 && dcf[i][j] >= dcf[i-1][j] {
+				t.Errorf("DCF price should fall as WACC rises (row %d col %d)", i, j)
+			}
+			if j > 0 && dcf[i][j] <= dcf[i][j-1] {
+				t.Errorf("DCF price should rise with terminal growth (row %d col %d)", i, j)
+			}
+		}
+	}
+
+	// Centre cell equals the base case (0.09 / 0.02)
+	if diff := dcf[1][1] - grid.BasePrices[ModelDCF]; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("centre cell %.6f != base %.6f", dcf[1][1], grid.BasePrices[ModelDCF])
+	}
+}
+
+func TestTwoWay_ReprojectsOperatingDrivers(t *testing.T) {
+	base := testBase(t)
+	grid, err := TwoWay(base,
+		Range{Driver: "cogs_percent", Values: []float64{0.50, 0.60}},
+		Range{Driver: "revenue_growth", Values: []float64{0.05}},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, m := range []Model{ModelDCF, ModelResidualIncome} {
+		if grid.Prices[m][1][0] >= grid.Prices[m][0][0] {
+			t.Errorf("%s: higher COGS should lower value (%.2f vs %.2f)", m, grid.Prices[m][1][0], grid.Prices[m][0][0])
+		}
+	}
+}
+
+func TestTwoWay_UnknownDriver(t *testing.T) {
+	_, err := TwoWay(testBase(t),
+		Range{Driver: "wacc", Values: []float64{0.09}},
+		Range{Driver: "moon_phase", Values: []float64{1}},
+	)
+	if err == nil {
+		t.Fatal("expected error for unknown driver")
+	}
+}
+
+func TestTornado_RankedBySwing(t *testing.T) {
+	base := testBase(t)
+	shocks, err := RelativeShocks(base, 0.10, "tax_rate", "wacc", "cogs_percent")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tornado, err := TornadoAnalysis(base, ModelDCF, shocks)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tornado.Bars) != 3 {
+		t.Fatalf("expected 3 bars, got %d", len(tornado.Bars))
+	}
+	for i := 1; i < len(tornado.Bars); i++ {
+		if tornado.Bars[i].Swing > tornado.Bars[i-1].Swing {
+			t.Errorf("bars not sorted by swing: %v", tornado.Bars)
+		}
+	}
+	if tornado.Markdown() == "" {
+		t.Error("expected markdown output")
+	}
+}
+
+func TestDebateTornado(t *testing.T) {
+	hist := testfixture.History()
+	price := 25.0
+	pool := &debate.MaterialPool{FinancialHistory: []*edgar.FSAPDataResponse{{HistoricalData: map[int]edgar.YearData{2024: {
+		IncomeStatement: *hist.IncomeStatement,
+		BalanceSheet:    *hist.BalanceSheet,
+		SupplementalData: edgar.SupplementalData{
+			SharesOutstandingDiluted: testfixture.Value(100),
+			SharePriceYearEnd:        &price,
+		},
+	}}}}}
+	baselines := map[string]float64{"rev_growth": 0.05, "cogs_pct": 0.55, "sga_pct": 0.15, "tax_rate": 0.25}
+
+	table, err := DebateTornado(pool, baselines)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, driver := range debateTornadoDrivers {
+		if !strings.Contains(table, "| "+driver+" |") {
+			t.Errorf("table should rank %s:\n%s", driver, table)
+		}
+	}
+
+	if _, err := DebateTornado(&debate.MaterialPool{}, baselines); err == nil {
+		t.Error("expected error without financial history")
+	}
+}
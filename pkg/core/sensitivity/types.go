@@ -0,0 +1,74 @@
+// Package sensitivity builds two-way data tables and tornado rankings for the
+// DCF and equity valuation models. Drivers are addressed by name (see
+// projection.DriverNames and valuation.MasterValuationInput.SetDriver); flexing a
+// projection driver re-projects the statements before re-valuing.
+package sensitivity
+
+import (
+	"agentic_valuation/pkg/core/projection"
+	"agentic_valuation/pkg/core/valuation"
+)
+
+// Model identifies a valuation model covered by the sensitivity tables
+type Model string
+
+const (
+	ModelDCF              Model = "FCFF"
+	ModelResidualIncome   Model = "RIM"
+	ModelFCFE             Model = "FCFE"
+	ModelDividendDiscount Model = "DDM"
+)
+
+// AllModels lists the models evaluated for every grid cell
+var AllModels = []Model{ModelDCF, ModelResidualIncome, ModelFCFE, ModelDividendDiscount}
+
+// History is the T-0 base used when a flexed driver requires re-projection
+type History = projection.History
+
+// Base is the deterministic case the tables are flexed around.
+// Valuation.Projections must have been produced from Assumptions and History.
+type Base struct {
+	Valuation   valuation.MasterValuationInput
+	Assumptions projection.ProjectionAssumptions
+	Skeleton    *projection.StandardSkeleton // Optional: strategies that drive the projections
+	History     History
+}
+
+// Range lists the values a driver takes along one axis of a grid
+type Range struct {
+	Driver string    `json:"driver"` // e.g. "wacc", "terminal_growth", "revenue_growth"
+	Values []float64 `json:"values"`
+}
+
+// Grid is a two-way data table of share prices per model
+// Prices[model][row][col] corresponds to Row.Values[row] × Col.Values[col]
+type Grid struct {
+	Row        Range                 `json:"row"`
+	Col        Range                 `json:"col"`
+	Prices     map[Model][][]float64 `json:"prices"`
+	BasePrices map[Model]float64     `json:"base_prices"`
+}
+
+// Shock defines the low/high values of a driver for a tornado bar
+type Shock struct {
+	Driver string  `json:"driver"`
+	Low    float64 `json:"low"`
+	High   float64 `json:"high"`
+}
+
+// TornadoBar is the one-at-a-time impact of a single driver
+type TornadoBar struct {
+	Driver    string  `json:"driver"`
+	LowValue  float64 `json:"low_value"`
+	HighValue float64 `json:"high_value"`
+	LowPrice  float64 `json:"low_price"`  // Share price with driver at LowValue
+	HighPrice float64 `json:"high_price"` // Share price with driver at HighValue
+	Swing     float64 `json:"swing"`      // |HighPrice - LowPrice|
+}
+
+// Tornado ranks drivers by how much they move one model's value
+type Tornado struct {
+	Model     Model        `json:"model"`
+	BasePrice float64      `json:"base_price"`
+	Bars      []TornadoBar `json:"bars"` // Sorted by Swing, largest first
+}
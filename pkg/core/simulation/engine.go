@@ -0,0 +1,149 @@
+package simulation
+
+import (
+	"agentic_valuation/pkg/core/projection"
+	"agentic_valuation/pkg/core/valuation"
+	"fmt"
+	"math"
+)
+
+const (
+	defaultIterations       = 1000
+	defaultYears            = 5
+	defaultHistogramBuckets = 20
+)
+
+var defaultPercentiles = []float64{5, 10, 25, 50, 75, 90, 95}
+
+// Run executes the Monte Carlo simulation.
+// Identical Input/Config (including Seed) always produce identical results.
+func Run(input Input, cfg Config) (*Result, error) {
+	if input.Assumptions == nil {
+		return nil, fmt.Errorf("simulation requires an AssumptionSet")
+	}
+	if err := input.History.Validate(); err != nil {
+		return nil, err
+	}
+	cfg = withDefaults(cfg)
+
+	smp, err := newSampler(input.Assumptions, cfg.Correlations, cfg.Seed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sampler: %w", err)
+	}
+
+	// Fail fast on node variables that address no driver
+	probeAssumptions := input.BaseAssumptions.Clone()
+	probeValuation := input.Valuation
+	for _, d := range smp.drivers {
+		if err := valuation.ApplyDriver(&probeAssumptions, &probeValuation, d.Driver, 0); err != nil {
+			return nil, fmt.Errorf("node '%s': %w", d.NodeID, err)
+		}
+	}
+
+	engine := projection.NewProjectionEngine(input.Assumptions.Skeleton)
+
+	var modelNames []string
+	outcomes := make(map[string][]float64)
+
+	for iter := 0; iter < cfg.Iterations; iter++ {
+		draws := smp.Draw()
+
+		assumptions := input.BaseAssumptions.Clone()
+		valInput := input.Valuation
+		for i, d := range smp.drivers {
+			if err := valuation.ApplyDriver(&assumptions, &valInput, d.Driver, draws[i]); err != nil {
+				return nil, fmt.Errorf("iteration %d, node '%s': %w", iter, d.NodeID, err)
+			}
+		}
+
+		projections, err := engine.ProjectHorizon(projection.HorizonInput{
+			History:     input.History,
+			Assumptions: assumptions,
+			Years:       cfg.Years,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("iteration %d: %w", iter, err)
+		}
+		valInput.Projections = projections
+
+		items, _ := valuation.RunAllValuations(valInput)
+		for _, item := range items {
+			if _, seen := outcomes[item.ModelName]; !seen {
+				modelNames = append(modelNames, item.ModelName)
+			}
+			outcomes[item.ModelName] = append(outcomes[item.ModelName], item.SharePrice)
+		}
+	}
+
+	result := &Result{
+		Iterations: cfg.Iterations,
+		Seed:       cfg.Seed,
+		Drivers:    make([]string, len(smp.drivers)),
+		Models:     make([]ModelDistribution, 0, len(modelNames)),
+	}
+	for i, d := range smp.drivers {
+		result.Drivers[i] = d.NodeID
+	}
+	for _, name := range modelNames {
+		result.Models = append(result.Models, summarize(name, outcomes[name], cfg))
+	}
+	return result, nil
+}
+
+func withDefaults(cfg Config) Config {
+	if cfg.Iterations <= 0 {
+		cfg.Iterations = defaultIterations
+	}
+	if cfg.Years <= 0 {
+		cfg.Years = defaultYears
+	}
+	if cfg.HistogramBuckets <= 0 {
+		cfg.HistogramBuckets = defaultHistogramBuckets
+	}
+	if len(cfg.Percentiles) == 0 {
+		cfg.Percentiles = defaultPercentiles
+	}
+	return cfg
+}
+
+// summarize turns raw outcomes into distribution statistics
+func summarize(name string, raw []float64, cfg Config) ModelDistribution {
+	dist := ModelDistribution{ModelName: name}
+
+	values := make([]float64, 0, len(raw))
+	for _, v := range raw {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			dist.Discarded++
+			continue
+		}
+		values = append(values, v)
+	}
+	dist.Samples = len(values)
+	if len(values) == 0 {
+		return dist
+	}
+
+	sorted := sortedCopy(values)
+	dist.Mean, dist.StdDev = meanStd(sorted)
+	dist.Min = sorted[0]
+	dist.Max = sorted[len(sorted)-1]
+
+	for _, p := range cfg.Percentiles {
+		dist.Percentiles = append(dist.Percentiles, PercentileValue{
+			Percentile: p,
+			Value:      valuation.Quantile(sorted, p/100),
+		})
+	}
+	dist.Histogram = histogram(sorted, cfg.HistogramBuckets)
+
+	if cfg.MarketPrice > 0 {
+		below := 0
+		for _, v := range sorted {
+			if v < cfg.MarketPrice {
+				below++
+			}
+		}
+		dist.ProbBelowMarket = float64(below) / float64(len(sorted))
+	}
+	return dist
+}
@@ -0,0 +1,197 @@
+package simulation
+
+import (
+	"agentic_valuation/pkg/core/assumption"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// stochasticDriver is a node whose value is drawn each iteration
+type stochasticDriver struct {
+	NodeID string
+	Driver string  // Driver name passed to valuation.ApplyDriver
+	Scale  float64 // 0.01 for "%" nodes (frontend stores 5.0 for 5%)
+	Node   *assumption.Node
+}
+
+// sampler draws correlated driver values using a Gaussian copula
+type sampler struct {
+	rng      *rand.Rand
+	drivers  []stochasticDriver
+	cholesky [][]float64 // Lower-triangular factor of the correlation matrix
+}
+
+// newSampler collects the stochastic nodes (sorted by ID for reproducibility) and factors the correlation matrix
+func newSampler(set *assumption.AssumptionSet, correlations []Correlation, seed int64) (*sampler, error) {
+	ids := make([]string, 0, len(set.Nodes))
+	for id, node := range set.Nodes {
+		if node.Distribution != "" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	drivers := make([]stochasticDriver, 0, len(ids))
+	index := make(map[string]int, len(ids))
+	for _, id := range ids {
+		node := set.Nodes[id]
+		if err := validateDistribution(node); err != nil {
+			return nil, err
+		}
+		driver := node.Variable
+		if driver == "" {
+			driver = node.ID
+		}
+		scale := 1.0
+		if node.Unit == "%" {
+			scale = 0.01
+		}
+		index[id] = len(drivers)
+		drivers = append(drivers, stochasticDriver{NodeID: id, Driver: driver, Scale: scale, Node: node})
+	}
+
+	// Correlation matrix (identity + user pairs)
+	n := len(drivers)
+	corr := make([][]float64, n)
+	for i := range corr {
+		corr[i] = make([]float64, n)
+		corr[i][i] = 1.0
+	}
+	for _, c := range correlations {
+		i, okA := index[c.NodeA]
+		j, okB := index[c.NodeB]
+		if !okA || !okB {
+			return nil, fmt.Errorf("correlation %s/%s references a node without a distribution", c.NodeA, c.NodeB)
+		}
+		if i == j {
+			return nil, fmt.Errorf("correlation of node '%s' with itself", c.NodeA)
+		}
+		if c.Rho < -1 || c.Rho > 1 {
+			return nil, fmt.Errorf("correlation %s/%s: rho %.3f outside [-1, 1]", c.NodeA, c.NodeB, c.Rho)
+		}
+		corr[i][j] = c.Rho
+		corr[j][i] = c.Rho
+	}
+
+	chol, err := choleskyDecompose(corr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sampler{
+		rng:      rand.New(rand.NewSource(seed)),
+		drivers:  drivers,
+		cholesky: chol,
+	}, nil
+}
+
+// Draw returns one correlated sample per driver (already scaled to decimals)
+func (s *sampler) Draw() []float64 {
+	n := len(s.drivers)
+	independent := make([]float64, n)
+	for i := range independent {
+		independent[i] = s.rng.NormFloat64()
+	}
+
+	out := make([]float64, n)
+	for i := 0; i < n; i++ {
+		z := 0.0
+		for k := 0; k <= i; k++ {
+			z += s.cholesky[i][k] * independent[k]
+		}
+		d := s.drivers[i]
+		out[i] = transform(d.Node, z) * d.Scale
+	}
+	return out
+}
+
+// validateDistribution checks that a node carries usable parameters for its distribution
+func validateDistribution(node *assumption.Node) error {
+	switch node.Distribution {
+	case assumption.DistNormal:
+		if node.Std < 0 {
+			return fmt.Errorf("node '%s': normal distribution requires std >= 0", node.ID)
+		}
+	case assumption.DistUniform:
+		if node.Max <= node.Min {
+			return fmt.Errorf("node '%s': uniform distribution requires max > min", node.ID)
+		}
+	case assumption.DistTriangular:
+		if node.Max <= node.Min {
+			return fmt.Errorf("node '%s': triangular distribution requires max > min", node.ID)
+		}
+	case assumption.DistLognormal:
+		if node.Mean <= 0 || node.Std < 0 {
+			return fmt.Errorf("node '%s': lognormal distribution requires mean > 0 and std >= 0", node.ID)
+		}
+	default:
+		return fmt.Errorf("node '%s': unsupported distribution '%s'", node.ID, node.Distribution)
+	}
+	return nil
+}
+
+// transform maps a standard normal draw onto the node's distribution (inverse CDF method)
+func transform(node *assumption.Node, z float64) float64 {
+	switch node.Distribution {
+	case assumption.DistUniform:
+		u := normalCDF(z)
+		return node.Min + u*(node.Max-node.Min)
+
+	case assumption.DistTriangular:
+		// Mode = Mean, clamped into [Min, Max]
+		a, b := node.Min, node.Max
+		mode := math.Max(a, math.Min(b, node.Mean))
+		u := normalCDF(z)
+		c := (mode - a) / (b - a)
+		if u < c {
+			return a + math.Sqrt(u*(b-a)*(mode-a))
+		}
+		return b - math.Sqrt((1-u)*(b-a)*(b-mode))
+
+	case assumption.DistLognormal:
+		// Mean/Std are given in value space; convert to log-space parameters
+		variance := math.Log(1 + (node.Std*node.Std)/(node.Mean*node.Mean))
+		mu := math.Log(node.Mean) - variance/2
+		return math.Exp(mu + math.Sqrt(variance)*z)
+
+	default: // normal
+		v := node.Mean + node.Std*z
+		if node.Max > node.Min {
+			v = math.Max(node.Min, math.Min(node.Max, v)) // Optional truncation bounds
+		}
+		return v
+	}
+}
+
+// normalCDF is the standard normal cumulative distribution function
+func normalCDF(z float64) float64 {
+	return 0.5 * math.Erfc(-z/math.Sqrt2)
+}
+
+// choleskyDecompose factors a symmetric positive-definite matrix as L·Lᵀ
+func choleskyDecompose(m [][]float64) ([][]float64, error) {
+	n := len(m)
+	l := make([][]float64, n)
+	for i := range l {
+		l[i] = make([]float64, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			sum := m[i][j]
+			for k := 0; k < j; k++ {
+				sum -= l[i][k] * l[j][k]
+			}
+			if i == j {
+				if sum <= 0 {
+					return nil, fmt.Errorf("correlation matrix is not positive definite")
+				}
+				l[i][i] = math.Sqrt(sum)
+			} else {
+				l[i][j] = sum / l[j][j]
+			}
+		}
+	}
+	return l, nil
+}
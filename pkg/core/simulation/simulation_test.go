@@ -0,0 +1,159 @@
+package simulation
+
+import (
+	"math"
+	"testing"
+
+	"agentic_valuation/pkg/core/assumption"
+	"agentic_valuation/pkg/core/internal/testfixture"
+	"agentic_valuation/pkg/core/valuation"
+)
+
+func testInput(t *testing.T) Input {
+	t.Helper()
+
+	set := assumption.NewAssumptionSet("case-mc", "base")
+	nodes := []*assumption.Node{
+		{ID: "rev-growth", Variable: "revenue_growth", Unit: "%", Distribution: assumption.DistNormal, Mean: 8, Std: 3},
+		{ID: "cogs", Variable: "cogs_percent", Unit: "%", Distribution: assumption.DistTriangular, Min: 55, Mean: 60, Max: 65},
+		{ID: "wacc", Variable: "wacc", Distribution: assumption.DistUniform, Min: 0.08, Max: 0.10},
+	}
+	for _, n := range nodes {
+		if err := set.AddNode(n); err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+	}
+
+	base := testfixture.Assumptions()
+	base.COGSPercent = 0.60
+
+	return Input{
+		Assumptions:     set,
+		BaseAssumptions: base,
+		History:         testfixture.History(),
+		Valuation:       testfixture.Valuation(),
+	}
+}
+
+func TestRun_Deterministic(t *testing.T) {
+	cfg := Config{Iterations: 200, Seed: 42, Years: 3, MarketPrice: 10}
+
+	a, err := Run(testInput(t), cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := Run(testInput(t), cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(a.Models) != 7 {
+		t.Fatalf("expected 7 model distributions, got %d", len(a.Models))
+	}
+	for i := range a.Models {
+		if a.Models[i].Mean != b.Models[i].Mean || a.Models[i].StdDev != b.Models[i].StdDev {
+			t.Errorf("%s: same seed produced different results", a.Models[i].ModelName)
+		}
+	}
+
+	c, err := Run(testInput(t), Config{Iterations: 200, Seed: 7, Years: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fcff(t, c).Mean == fcff(t, a).Mean {
+		t.Error("different seeds should produce different draws")
+	}
+}
+
+// fcff looks up the FCFF DCF distribution by its RunAllValuations line name
+func fcff(t *testing.T, res *Result) ModelDistribution {
+	t.Helper()
+	for _, m := range res.Models {
+		if m.ModelName == valuation.ModelFCFF {
+			return m
+		}
+	}
+	t.Fatalf("no %s distribution", valuation.ModelFCFF)
+	return ModelDistribution{}
+}
+
+func TestRun_DistributionShape(t *testing.T) {
+	res, err := Run(testInput(t), Config{Iterations: 500, Seed: 1, Years: 3, MarketPrice: 1e9})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, m := range res.Models {
+		if m.Samples+m.Discarded != 500 {
+			t.Errorf("%s: samples %d + discarded %d != 500", m.ModelName, m.Samples, m.Discarded)
+		}
+		for i := 1; i < len(m.Percentiles); i++ {
+			if m.Percentiles[i].Value < m.Percentiles[i-1].Value {
+				t.Errorf("%s: percentiles not monotonic", m.ModelName)
+			}
+		}
+		total := 0
+		for _, b := range m.Histogram {
+			total += b.Count
+		}
+		if total != m.Samples {
+			t.Errorf("%s: histogram counts %d != samples %d", m.ModelName, total, m.Samples)
+		}
+		if m.ProbBelowMarket != 1 {
+			t.Errorf("%s: expected all outcomes below a huge market price, got %.2f", m.ModelName, m.ProbBelowMarket)
+		}
+	}
+
+	// FCFF is driven by the sampled WACC and margins, so it must have spread
+	if dcf := fcff(t, res); dcf.StdDev <= 0 {
+		t.Errorf("expected dispersion in %s, got std %.4f", dcf.ModelName, dcf.StdDev)
+	}
+}
+
+func TestRun_UnknownDriver(t *testing.T) {
+	input := testInput(t)
+	_ = input.Assumptions.AddNode(&assumption.Node{ID: "mystery", Distribution: assumption.DistNormal, Mean: 1, Std: 1})
+
+	if _, err := Run(input, Config{Iterations: 10}); err == nil {
+		t.Fatal("expected error for node without a known driver")
+	}
+}
+
+func TestSampler_Correlation(t *testing.T) {
+	set := assumption.NewAssumptionSet("case", "base")
+	_ = set.AddNode(&assumption.Node{ID: "a", Distribution: assumption.DistNormal, Std: 1})
+	_ = set.AddNode(&assumption.Node{ID: "b", Distribution: assumption.DistNormal, Std: 1})
+
+	smp, err := newSampler(set, []Correlation{{NodeA: "a", NodeB: "b", Rho: 0.9}}, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	const n = 5000
+	var sumAB, sumA2, sumB2 float64
+	for i := 0; i < n; i++ {
+		d := smp.Draw()
+		sumAB += d[0] * d[1]
+		sumA2 += d[0] * d[0]
+		sumB2 += d[1] * d[1]
+	}
+	rho := sumAB / math.Sqrt(sumA2*sumB2)
+	if math.Abs(rho-0.9) > 0.03 {
+		t.Errorf("expected sample correlation ~0.9, got %.3f", rho)
+	}
+}
+
+func TestSampler_NotPositiveDefinite(t *testing.T) {
+	set := assumption.NewAssumptionSet("case", "base")
+	for _, id := range []string{"a", "b", "c"} {
+		_ = set.AddNode(&assumption.Node{ID: id, Distribution: assumption.DistNormal, Std: 1})
+	}
+	corr := []Correlation{
+		{NodeA: "a", NodeB: "b", Rho: 0.9},
+		{NodeA: "b", NodeB: "c", Rho: 0.9},
+		{NodeA: "a", NodeB: "c", Rho: -0.9},
+	}
+	if _, err := newSampler(set, corr, 1); err == nil {
+		t.Fatal("expected error for inconsistent correlation matrix")
+	}
+}
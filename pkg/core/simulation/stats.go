@@ -0,0 +1,57 @@
+package simulation
+
+import (
+	"math"
+	"sort"
+)
+
+func sortedCopy(values []float64) []float64 {
+	out := make([]float64, len(values))
+	copy(out, values)
+	sort.Float64s(out)
+	return out
+}
+
+// meanStd returns the mean and sample standard deviation
+func meanStd(values []float64) (float64, float64) {
+	n := float64(len(values))
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / n
+	if len(values) < 2 {
+		return mean, 0
+	}
+	sq := 0.0
+	for _, v := range values {
+		sq += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(sq / (n - 1))
+}
+
+// histogram splits [min, max] into equal-width buckets (input sorted)
+func histogram(sorted []float64, buckets int) []HistogramBucket {
+	lo, hi := sorted[0], sorted[len(sorted)-1]
+	if hi == lo {
+		return []HistogramBucket{{Lower: lo, Upper: hi, Count: len(sorted), Frequency: 1}}
+	}
+
+	width := (hi - lo) / float64(buckets)
+	out := make([]HistogramBucket, buckets)
+	for i := range out {
+		out[i].Lower = lo + float64(i)*width
+		out[i].Upper = lo + float64(i+1)*width
+	}
+	for _, v := range sorted {
+		idx := int((v - lo) / width)
+		if idx >= buckets {
+			idx = buckets - 1 // Max lands in the last bucket
+		}
+		out[idx].Count++
+	}
+	for i := range out {
+		out[i].Frequency = float64(out[i].Count) / float64(len(sorted))
+	}
+	return out
+}
@@ -0,0 +1,76 @@
+// Package simulation implements Monte Carlo valuation on top of the projection engine.
+// Each iteration samples the distributions carried on assumption.Node, rolls the
+// statements forward with projection.ProjectionEngine and values them with
+// valuation.RunAllValuations, producing a share-price distribution per model.
+package simulation
+
+import (
+	"agentic_valuation/pkg/core/assumption"
+	"agentic_valuation/pkg/core/projection"
+	"agentic_valuation/pkg/core/valuation"
+)
+
+// History is the T-0 base that every simulated path rolls forward from
+type History = projection.History
+
+// Correlation links the draws of two stochastic nodes (Gaussian copula)
+type Correlation struct {
+	NodeA string  `json:"node_a"`
+	NodeB string  `json:"node_b"`
+	Rho   float64 `json:"rho"` // -1..1
+}
+
+// Config controls the simulation run
+type Config struct {
+	Iterations       int           `json:"iterations"` // Number of scenarios (default 1000)
+	Seed             int64         `json:"seed"`       // Same seed => identical results
+	Years            int           `json:"years"`      // Projection horizon (default 5)
+	Correlations     []Correlation `json:"correlations,omitempty"`
+	MarketPrice      float64       `json:"market_price"`      // For probability-below-market (0 = skip)
+	HistogramBuckets int           `json:"histogram_buckets"` // Default 20
+	Percentiles      []float64     `json:"percentiles,omitempty"`
+}
+
+// Input bundles the assumption set and the deterministic base case
+type Input struct {
+	Assumptions     *assumption.AssumptionSet        // Nodes with Distribution set are sampled
+	BaseAssumptions projection.ProjectionAssumptions // Drivers not sampled keep these values
+	History         History                          // T-0 statements
+	Valuation       valuation.MasterValuationInput   // Rates, shares, net debt (Projections ignored)
+}
+
+// PercentileValue is one point of the empirical distribution
+type PercentileValue struct {
+	Percentile float64 `json:"percentile"` // e.g. 5, 50, 95
+	Value      float64 `json:"value"`
+}
+
+// HistogramBucket counts outcomes in [Lower, Upper)
+type HistogramBucket struct {
+	Lower     float64 `json:"lower"`
+	Upper     float64 `json:"upper"`
+	Count     int     `json:"count"`
+	Frequency float64 `json:"frequency"` // Count / valid samples
+}
+
+// ModelDistribution summarizes simulated share prices for one valuation model
+type ModelDistribution struct {
+	ModelName       string            `json:"model_name"`
+	Samples         int               `json:"samples"`   // Valid (finite) outcomes
+	Discarded       int               `json:"discarded"` // NaN/Inf outcomes (e.g. zero shares)
+	Mean            float64           `json:"mean"`
+	StdDev          float64           `json:"std_dev"`
+	Min             float64           `json:"min"`
+	Max             float64           `json:"max"`
+	Percentiles     []PercentileValue `json:"percentiles"`
+	Histogram       []HistogramBucket `json:"histogram"`
+	ProbBelowMarket float64           `json:"prob_below_market"` // Share of outcomes < Config.MarketPrice
+}
+
+// Result is the output of a simulation run
+type Result struct {
+	Iterations int                 `json:"iterations"`
+	Seed       int64               `json:"seed"`
+	Drivers    []string            `json:"drivers"` // Sampled node IDs in draw order
+	Models     []ModelDistribution `json:"models"`
+}
@@ -0,0 +1,158 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ScenarioRecord is one row of projection_scenarios.
+// The assumption set and results are stored as opaque JSON (see scenario.NewRecord).
+type ScenarioRecord struct {
+	CaseID        string
+	ScenarioID    string
+	Name          string
+	Description   string
+	Probability   float64
+	Overrides     map[string]float64
+	AssumptionSet json.RawMessage // assumption.AssumptionSet after overrides
+	Results       json.RawMessage // scenario.Outcome (optional)
+	AssumptionsID string          // projection_assumptions row the scenario was projected from ("" = none)
+	UpdatedAt     time.Time
+}
+
+// ScenarioRepo persists bull / base / bear scenarios next to projection_assumptions
+type ScenarioRepo struct {
+	pool *pgxpool.Pool
+}
+
+// NewScenarioRepo creates a new scenario repository
+func NewScenarioRepo(pool *pgxpool.Pool) *ScenarioRepo {
+	return &ScenarioRepo{pool: pool}
+}
+
+// SaveScenario upserts a scenario on (case_id, scenario_id)
+func (r *ScenarioRepo) SaveScenario(ctx context.Context, rec ScenarioRecord) error {
+	if r.pool == nil {
+		return fmt.Errorf("database pool not configured")
+	}
+
+	overridesJSON, err := json.Marshal(rec.Overrides)
+	if err != nil {
+		return fmt.Errorf("failed to marshal overrides: %w", err)
+	}
+	var assumptionsID *string
+	if rec.AssumptionsID != "" {
+		assumptionsID = &rec.AssumptionsID
+	}
+
+	query := `
+		INSERT INTO projection_scenarios (
+			case_id, scenario_id, name, description, probability,
+			overrides, assumption_set, results, assumptions_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (case_id, scenario_id)
+		DO UPDATE SET
+			name = EXCLUDED.name,
+			description = EXCLUDED.description,
+			probability = EXCLUDED.probability,
+			overrides = EXCLUDED.overrides,
+			assumption_set = EXCLUDED.assumption_set,
+			results = EXCLUDED.results,
+			assumptions_id = EXCLUDED.assumptions_id,
+			updated_at = NOW()
+	`
+	_, err = r.pool.Exec(ctx, query, rec.CaseID, rec.ScenarioID, rec.Name, rec.Description, rec.Probability,
+		overridesJSON, []byte(rec.AssumptionSet), []byte(rec.Results), assumptionsID)
+	if err != nil {
+		return fmt.Errorf("failed to save scenario '%s': %w", rec.ScenarioID, err)
+	}
+	return nil
+}
+
+// LoadScenarios returns every scenario of a case in creation order
+func (r *ScenarioRepo) LoadScenarios(ctx context.Context, caseID string) ([]ScenarioRecord, error) {
+	if r.pool == nil {
+		return nil, fmt.Errorf("database pool not configured")
+	}
+
+	query := `
+		SELECT scenario_id, name, description, probability, overrides,
+		       assumption_set, results, COALESCE(assumptions_id::text, ''), updated_at
+		FROM projection_scenarios
+		WHERE case_id = $1
+		ORDER BY created_at, scenario_id
+	`
+	rows, err := r.pool.Query(ctx, query, caseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load scenarios: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ScenarioRecord
+	for rows.Next() {
+		rec := ScenarioRecord{CaseID: caseID}
+		var overridesJSON, setJSON, resultsJSON []byte
+		if err := rows.Scan(&rec.ScenarioID, &rec.Name, &rec.Description, &rec.Probability,
+			&overridesJSON, &setJSON, &resultsJSON, &rec.AssumptionsID, &rec.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan scenario: %w", err)
+		}
+		if len(overridesJSON) > 0 {
+			if err := json.Unmarshal(overridesJSON, &rec.Overrides); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal overrides of '%s': %w", rec.ScenarioID, err)
+			}
+		}
+		rec.AssumptionSet = setJSON
+		rec.Results = resultsJSON
+		out = append(out, rec)
+	}
+	return out, rows.Err()
+}
+
+// LoadScenario returns one scenario of a case
+func (r *ScenarioRepo) LoadScenario(ctx context.Context, caseID, scenarioID string) (*ScenarioRecord, error) {
+	if r.pool == nil {
+		return nil, fmt.Errorf("database pool not configured")
+	}
+
+	query := `
+		SELECT name, description, probability, overrides,
+		       assumption_set, results, COALESCE(assumptions_id::text, ''), updated_at
+		FROM projection_scenarios
+		WHERE case_id = $1 AND scenario_id = $2
+	`
+	rec := ScenarioRecord{CaseID: caseID, ScenarioID: scenarioID}
+	var overridesJSON, setJSON, resultsJSON []byte
+	err := r.pool.QueryRow(ctx, query, caseID, scenarioID).Scan(&rec.Name, &rec.Description, &rec.Probability,
+		&overridesJSON, &setJSON, &resultsJSON, &rec.AssumptionsID, &rec.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("no scenario '%s' for case %s", scenarioID, caseID)
+		}
+		return nil, fmt.Errorf("failed to load scenario: %w", err)
+	}
+	if len(overridesJSON) > 0 {
+		if err := json.Unmarshal(overridesJSON, &rec.Overrides); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal overrides of '%s': %w", scenarioID, err)
+		}
+	}
+	rec.AssumptionSet = setJSON
+	rec.Results = resultsJSON
+	return &rec, nil
+}
+
+// DeleteScenario removes one scenario of a case
+func (r *ScenarioRepo) DeleteScenario(ctx context.Context, caseID, scenarioID string) error {
+	if r.pool == nil {
+		return fmt.Errorf("database pool not configured")
+	}
+	_, err := r.pool.Exec(ctx, `DELETE FROM projection_scenarios WHERE case_id = $1 AND scenario_id = $2`, caseID, scenarioID)
+	if err != nil {
+		return fmt.Errorf("failed to delete scenario: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package synthesis
+
+import (
+	"agentic_valuation/pkg/core/edgar"
+	"reflect"
+)
+
+// nonAdditiveLines are per-share, share-count and balance lines that do not add
+// across periods; callers restate them (EPS, opening and closing cash)
+var nonAdditiveLines = map[string]bool{
+	"EPSBasic":              true,
+	"EPSDiluted":            true,
+	"WeightedAverageShares": true,
+	"CashBeginning":         true,
+	"CashEnding":            true,
+}
+
+// CombineFlows adds statements of one type (pointers), each scaled by its sign
+// (e.g. FY - Q1 - Q2 - Q3, or H1 - Q1). Flow lines are combined; per-share,
+// share-count and cash balance lines and additional items are left out.
+func CombineFlows(parts []interface{}, signs []float64) interface{} {
+	out := reflect.New(reflect.TypeOf(parts[0]).Elem())
+	for i, p := range parts {
+		addLines(out.Elem(), reflect.ValueOf(p).Elem(), signs[i])
+	}
+	return out.Interface()
+}
+
+var fsapValueType = reflect.TypeOf(&edgar.FSAPValue{})
+
+func addLines(dst, src reflect.Value, sign float64) {
+	switch {
+	case src.Type() == fsapValueType:
+		if src.IsNil() || src.Elem().FieldByName("Value").IsNil() {
+			return
+		}
+		v := src.Elem().FieldByName("Value").Elem().Float() * sign
+		if dst.IsNil() {
+			dst.Set(reflect.ValueOf(&edgar.FSAPValue{Value: &v}))
+			return
+		}
+		*dst.Interface().(*edgar.FSAPValue).Value += v
+	case src.Kind() == reflect.Ptr && src.Type().Elem().Kind() == reflect.Struct:
+		if src.IsNil() {
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.New(src.Type().Elem()))
+		}
+		addLines(dst.Elem(), src.Elem(), sign)
+	case src.Kind() == reflect.Struct:
+		for i := 0; i < src.NumField(); i++ {
+			if f := src.Type().Field(i); f.IsExported() && !nonAdditiveLines[f.Name] {
+				addLines(dst.Field(i), src.Field(i), sign)
+			}
+		}
+	}
+}
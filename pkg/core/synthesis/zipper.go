@@ -17,6 +17,7 @@ import (
 	"agentic_valuation/pkg/core/edgar"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 )
 
@@ -27,11 +28,12 @@ import (
 // GoldenRecord represents the synthesized, authoritative time-series for a single company.
 // This is the output of the Zipper engine.
 type GoldenRecord struct {
-	Ticker       string                  `json:"ticker"`
-	CIK          string                  `json:"cik"`
-	LastUpdated  time.Time               `json:"last_updated"`
-	Timeline     map[int]*YearlySnapshot `json:"timeline"` // Key: Fiscal Year (e.g., 2023)
-	Restatements []RestatementLog        `json:"restatements"`
+	Ticker       string                        `json:"ticker"`
+	CIK          string                        `json:"cik"`
+	LastUpdated  time.Time                     `json:"last_updated"`
+	Timeline     map[int]*YearlySnapshot       `json:"timeline"`           // Key: Fiscal Year (e.g., 2023)
+	Quarters     map[string]*QuarterlySnapshot `json:"quarters,omitempty"` // Key: QuarterKey (e.g., "2023Q2"), from 10-Qs
+	Restatements []RestatementLog              `json:"restatements"`
 }
 
 // YearlySnapshot contains the final, authoritative financial data for a single fiscal year.
@@ -45,11 +47,48 @@ type YearlySnapshot struct {
 	Completeness      float64                 `json:"completeness"`  // 0-1 coverage ratio
 }
 
+// QuarterlySnapshot contains the discrete three-month statements of a fiscal quarter.
+// The balance sheet is at quarter end, so it is nil for a quarter seen only as a
+// comparative column (a 10-Q compares against the prior fiscal year end). 10-Q cash
+// flows are year to date: CashFlowYearToDate holds them as reported, and
+// CashFlowStatement the three months less the prior quarter's year to date (nil
+// until that quarter is known).
+type QuarterlySnapshot struct {
+	FiscalYear         int                      `json:"fiscal_year"`
+	Quarter            int                      `json:"quarter"` // 1-4
+	BalanceSheet       *edgar.BalanceSheet      `json:"balance_sheet,omitempty"`
+	IncomeStatement    edgar.IncomeStatement    `json:"income_statement"`
+	CashFlowStatement  *edgar.CashFlowStatement `json:"cash_flow_statement,omitempty"`
+	CashFlowYearToDate edgar.CashFlowStatement  `json:"cash_flow_ytd"`
+	SourceFiling       SourceMetadata           `json:"source_filing"`
+}
+
+// QuarterKey is the GoldenRecord.Quarters key of a fiscal quarter (e.g., "2023Q2")
+func QuarterKey(fiscalYear, quarter int) string {
+	return fmt.Sprintf("%dQ%d", fiscalYear, quarter)
+}
+
+// FiscalQuarter parses a fiscal period ("Q1".."Q4") into its quarter, or 0 for "FY" and unknown periods
+func FiscalQuarter(period string) int {
+	switch strings.ToUpper(strings.TrimSpace(period)) {
+	case "Q1":
+		return 1
+	case "Q2":
+		return 2
+	case "Q3":
+		return 3
+	case "Q4":
+		return 4
+	}
+	return 0
+}
+
 // SourceMetadata identifies the origin of a piece of data.
 type SourceMetadata struct {
 	AccessionNumber string `json:"accession_number"`
 	FilingDate      string `json:"filing_date"`
-	Form            string `json:"form"` // "10-K", "10-K/A"
+	Form            string `json:"form"`                    // "10-K", "10-K/A", "10-Q"
+	FiscalPeriod    string `json:"fiscal_period,omitempty"` // "FY", "Q1".."Q4" (empty = FY)
 	IsAmended       bool   `json:"is_amended"`
 }
 
@@ -107,6 +146,7 @@ func (z *ZipperEngine) Stitch(ticker, cik string, snapshots []ExtractionSnapshot
 		CIK:          cik,
 		LastUpdated:  time.Now(),
 		Timeline:     make(map[int]*YearlySnapshot),
+		Quarters:     make(map[string]*QuarterlySnapshot),
 		Restatements: []RestatementLog{},
 	}
 
@@ -133,11 +173,20 @@ func (z *ZipperEngine) MergeSnapshots(record *GoldenRecord, snapshots []Extracti
 
 // mergeSnapshot integrates a single ExtractionSnapshot into the GoldenRecord.
 // It handles multi-year data within a single filing (Comparative Columns).
+// Quarterly filings go to the quarterly timeline and never replace annual data.
 func (z *ZipperEngine) mergeSnapshot(record *GoldenRecord, snap *ExtractionSnapshot) {
 	data := snap.Data
 	if data == nil {
 		return
 	}
+	period := snap.FilingMetadata.FiscalPeriod
+	if period == "" {
+		period = data.FiscalPeriod
+	}
+	if quarter := FiscalQuarter(period); quarter > 0 {
+		z.mergeQuarter(record, snap, quarter)
+		return
+	}
 
 	// Extract all years present in the filing's data
 	yearsInFiling := z.findAllYears(data)
@@ -160,6 +209,79 @@ func (z *ZipperEngine) mergeSnapshot(record *GoldenRecord, snap *ExtractionSnaps
 	}
 }
 
+// mergeQuarter integrates a 10-Q: the current quarter and its prior-year comparative column.
+// Only the filing's own column carries a quarter-end balance sheet; the comparative
+// balance sheet is the prior fiscal year end and is already on the annual timeline.
+func (z *ZipperEngine) mergeQuarter(record *GoldenRecord, snap *ExtractionSnapshot, quarter int) {
+	if record.Quarters == nil {
+		record.Quarters = make(map[string]*QuarterlySnapshot)
+	}
+	years := z.findAllYears(snap.Data)
+	current := snap.FiscalYear
+	if current == 0 {
+		current = snap.Data.FiscalYear
+	}
+	if current == 0 && len(years) > 0 {
+		current = years[len(years)-1]
+	}
+	for _, year := range years {
+		key := QuarterKey(year, quarter)
+		existing, ok := record.Quarters[key]
+		if ok && !z.shouldSupersede(existing.SourceFiling, snap.FilingMetadata) {
+			continue
+		}
+		yearStr := fmt.Sprintf("%d", year)
+		q := &QuarterlySnapshot{
+			FiscalYear:         year,
+			Quarter:            quarter,
+			IncomeStatement:    sliceIncomeStatement(snap.Data.IncomeStatement, yearStr),
+			CashFlowYearToDate: sliceCashFlowStatement(snap.Data.CashFlowStatement, yearStr),
+			SourceFiling:       snap.FilingMetadata,
+		}
+		if year == current {
+			bs := sliceBalanceSheet(snap.Data.BalanceSheet, yearStr)
+			q.BalanceSheet = &bs
+		} else if ok {
+			q.BalanceSheet = existing.BalanceSheet
+		}
+		record.Quarters[key] = q
+		restateQuarterFlows(record, year)
+	}
+}
+
+// restateQuarterFlows turns a fiscal year's year-to-date cash flows into three-month
+// flows: Q1 as reported, Q2 = H1 - Q1, Q3 = 9M - H1. Opening cash is the
+// prior quarter's closing cash.
+func restateQuarterFlows(record *GoldenRecord, year int) {
+	for quarter := 1; quarter <= 4; quarter++ {
+		q := record.Quarters[QuarterKey(year, quarter)]
+		if q == nil {
+			continue
+		}
+		if quarter == 1 {
+			cf := q.CashFlowYearToDate
+			q.CashFlowStatement = &cf
+			continue
+		}
+		prev := record.Quarters[QuarterKey(year, quarter-1)]
+		if prev == nil {
+			q.CashFlowStatement = nil
+			continue
+		}
+		cf := CombineFlows([]interface{}{&q.CashFlowYearToDate, &prev.CashFlowYearToDate}, []float64{1, -1}).(*edgar.CashFlowStatement)
+		if ytd := q.CashFlowYearToDate.CashSummary; ytd != nil {
+			if cf.CashSummary == nil {
+				cf.CashSummary = &edgar.CashSummarySection{}
+			}
+			cf.CashSummary.CashEnding = ytd.CashEnding
+			if prior := prev.CashFlowYearToDate.CashSummary; prior != nil {
+				cf.CashSummary.CashBeginning = prior.CashEnding
+			}
+		}
+		q.CashFlowStatement = cf
+	}
+}
+
 // shouldSupersede determines if a new filing should replace the existing one.
 // Rule: 10-K/A always wins. Otherwise, newer filing date wins.
 func (z *ZipperEngine) shouldSupersede(existing, incoming SourceMetadata) bool {
@@ -432,3 +432,102 @@ func TestSingleSnapshot(t *testing.T) {
 		t.Errorf("Expected 0 restatements for single snapshot, got %d", len(record.Restatements))
 	}
 }
+
+func TestQuarterlyFilingsStayOffAnnualTimeline(t *testing.T) {
+	zipper := NewZipperEngine()
+	annual := makeSnapshot(
+		"0001234-24-000001", "2024-02-28", "10-K", false, 2023,
+		makeRevenue(map[int]float64{2023: 400}),
+		makeTotalAssets(map[int]float64{2023: 500}),
+	)
+	q2 := makeSnapshot(
+		"0001234-24-000010", "2024-08-05", "10-Q", false, 2024,
+		makeRevenue(map[int]float64{2024: 110, 2023: 95}),
+		makeTotalAssets(map[int]float64{2024: 520}),
+	)
+	q2.FilingMetadata.FiscalPeriod = "Q2"
+
+	record, err := zipper.Stitch("AAPL", "0000320193", []ExtractionSnapshot{annual, q2})
+	if err != nil {
+		t.Fatalf("Stitch failed: %v", err)
+	}
+	if len(record.Timeline) != 1 || record.Timeline[2023] == nil {
+		t.Fatalf("the 10-Q should not create fiscal years, got %d", len(record.Timeline))
+	}
+	if got := *record.Timeline[2023].IncomeStatement.GrossProfitSection.Revenues.Value; got != 400 {
+		t.Errorf("FY2023 revenue %v, want 400", got)
+	}
+
+	current, prior := record.Quarters[QuarterKey(2024, 2)], record.Quarters[QuarterKey(2023, 2)]
+	if current == nil || prior == nil {
+		t.Fatalf("expected 2024Q2 and its 2023Q2 comparative, got %v", record.Quarters)
+	}
+	if got := *current.IncomeStatement.GrossProfitSection.Revenues.Value; got != 110 {
+		t.Errorf("2024Q2 revenue %v, want 110", got)
+	}
+	if got := *prior.IncomeStatement.GrossProfitSection.Revenues.Value; got != 95 {
+		t.Errorf("2023Q2 revenue %v, want 95", got)
+	}
+}
+
+func TestQuarterlyFilingsRestateYearToDate(t *testing.T) {
+	zipper := NewZipperEngine()
+	tenQ := func(accession, filingDate, period string, totalAssets, operating, cashEnding map[int]float64) ExtractionSnapshot {
+		snap := makeSnapshot(accession, filingDate, "10-Q", false, 2024,
+			makeRevenue(map[int]float64{2024: 100, 2023: 90}), makeTotalAssets(totalAssets))
+		snap.FilingMetadata.FiscalPeriod = period
+		snap.Data.CashFlowStatement.CashSummary = &edgar.CashSummarySection{
+			NetCashOperating: makeRevenue(operating),
+			CashEnding:       makeRevenue(cashEnding),
+		}
+		return snap
+	}
+	// The comparative balance sheet column (2023: 500) is the prior fiscal year end
+	q1 := tenQ("0001234-24-000005", "2024-05-03", "Q1",
+		map[int]float64{2024: 520, 2023: 500}, map[int]float64{2024: 30, 2023: 25}, map[int]float64{2024: 110, 2023: 95})
+	h1 := tenQ("0001234-24-000010", "2024-08-05", "Q2",
+		map[int]float64{2024: 540, 2023: 500}, map[int]float64{2024: 70, 2023: 60}, map[int]float64{2024: 130, 2023: 105})
+
+	record, err := zipper.Stitch("AAPL", "0000320193", []ExtractionSnapshot{h1, q1})
+	if err != nil {
+		t.Fatalf("Stitch failed: %v", err)
+	}
+
+	current, prior := record.Quarters[QuarterKey(2024, 2)], record.Quarters[QuarterKey(2023, 2)]
+	if current == nil || prior == nil {
+		t.Fatalf("expected 2024Q2 and 2023Q2, got %v", record.Quarters)
+	}
+	if current.BalanceSheet == nil || *current.BalanceSheet.ReportedForValidation.TotalAssets.Value != 540 {
+		t.Errorf("2024Q2 should carry the quarter-end balance sheet, got %+v", current.BalanceSheet)
+	}
+	if prior.BalanceSheet != nil {
+		t.Errorf("2023Q2 should have no balance sheet: the comparative column is the prior year end")
+	}
+	if q := record.Quarters[QuarterKey(2023, 1)]; q == nil || q.BalanceSheet != nil {
+		t.Errorf("2023Q1 should exist without a balance sheet, got %+v", q)
+	}
+
+	for _, tc := range []struct {
+		key       string
+		operating float64
+	}{
+		{QuarterKey(2024, 1), 30},
+		{QuarterKey(2024, 2), 40}, // H1 70 less Q1 30
+		{QuarterKey(2023, 2), 35}, // H1 60 less Q1 25
+	} {
+		q := record.Quarters[tc.key]
+		if q == nil || q.CashFlowStatement == nil {
+			t.Fatalf("%s: expected three-month cash flows", tc.key)
+		}
+		if got := *q.CashFlowStatement.CashSummary.NetCashOperating.Value; got != tc.operating {
+			t.Errorf("%s operating cash flow %v, want %v", tc.key, got, tc.operating)
+		}
+	}
+	if got := *current.CashFlowYearToDate.CashSummary.NetCashOperating.Value; got != 70 {
+		t.Errorf("2024Q2 year-to-date operating cash flow %v, want 70", got)
+	}
+	sum := current.CashFlowStatement.CashSummary
+	if *sum.CashBeginning.Value != 110 || *sum.CashEnding.Value != 130 {
+		t.Errorf("2024Q2 cash should run from Q1's close 110 to 130, got %v to %v", *sum.CashBeginning.Value, *sum.CashEnding.Value)
+	}
+}
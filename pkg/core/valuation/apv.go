@@ -0,0 +1,121 @@
+package valuation
+
+import (
+	"agentic_valuation/pkg/core/projection"
+	"fmt"
+)
+
+// APVInput values the unlevered firm at Ku and adds the financing side effects separately
+type APVInput struct {
+	Projections       []*projection.ProjectedFinancials
+	UnleveredCost     float64 // Ku: cost of capital of the all-equity firm
+	PreTaxCostOfDebt  float64 // Rate on opening debt for the shields (0 = use projected interest expense)
+	TaxRate           float64
+	TerminalGrowth    float64
+	OpeningDebt       float64 // Debt at the valuation date (drives the first year's shield)
+	SharesOutstanding float64
+	NetDebt           float64       // Ignored when Bridge is set
+	Bridge            *EquityBridge // Optional: itemized EV-to-equity adjustments
+	Timing            DiscountTiming
+	Terminal          TerminalValueConfig
+}
+
+// APVResult splits enterprise value into operations and tax shields
+type APVResult struct {
+	UnleveredValue     float64 // PV of UFCF and unlevered terminal value at Ku
+	PV_FCF             float64
+	PV_Terminal        float64
+	PV_TaxShields      float64 // Explicit years
+	PV_TerminalShields float64 // Shields beyond the horizon (growth methods only)
+	InterestTaxShields []float64
+	EnterpriseValue    float64
+	EquityValue        float64
+	SharePrice         float64
+	NetDebt            float64
+	Warnings           []string
+}
+
+// CalculateAPV performs an Adjusted Present Value valuation.
+// Shields are t × Kd × opening debt of each year from the projected balance
+// sheets and, like the cash flows, are discounted at Ku (Harris-Pringle), so
+// the result reconciles to FCFF when debt is a constant share of value and
+// WACC = Ku − t × Kd × D/V.
+func CalculateAPV(input APVInput) APVResult {
+	var res APVResult
+	var terminal terminalBase
+	if input.UnleveredCost <= 0 {
+		res.Warnings = append(res.Warnings, "APV needs an unlevered cost of capital (Ku)")
+		return res
+	}
+
+	disc := newDiscounter(input.Timing)
+	openingDebt := input.OpeningDebt
+	for i, proj := range input.Projections {
+		ufcf, base := dcfYearMetrics(proj, input.TaxRate)
+
+		// Interest on the debt outstanding at the start of the year
+		interest := input.PreTaxCostOfDebt * openingDebt
+		if input.PreTaxCostOfDebt == 0 {
+			interest = projectedInterest(proj)
+		}
+		shield := input.TaxRate * interest
+		res.InterestTaxShields = append(res.InterestTaxShields, shield)
+
+		fraction, factor := disc.next(input.UnleveredCost)
+		res.PV_FCF += ufcf * fraction * factor
+		res.PV_TaxShields += shield * fraction * factor
+
+		openingDebt = projectedDebt(proj)
+		if i == len(input.Projections)-1 {
+			terminal = base
+		}
+	}
+
+	// Growth methods capitalize unlevered flows at Ku and add the shields on the
+	// final debt balance as a growing perpetuity. Exit multiples already price a levered EV.
+	tv, perpetuity, err := terminalValue(input.Terminal, terminal, input.UnleveredCost, input.TerminalGrowth)
+	if err != nil {
+		res.Warnings = append(res.Warnings, fmt.Sprintf("terminal value set to zero: %v", err))
+	}
+	if perpetuity {
+		res.PV_Terminal = tv * disc.terminal(input.UnleveredCost)
+		if err == nil && input.PreTaxCostOfDebt > 0 {
+			terminalShields := input.TaxRate * input.PreTaxCostOfDebt * openingDebt / (input.UnleveredCost - input.TerminalGrowth)
+			res.PV_TerminalShields = terminalShields * disc.terminal(input.UnleveredCost)
+		}
+	} else {
+		res.PV_Terminal = tv * disc.horizon()
+	}
+
+	res.UnleveredValue = res.PV_FCF + res.PV_Terminal
+	res.EnterpriseValue = res.UnleveredValue + res.PV_TaxShields + res.PV_TerminalShields
+
+	bridge := input.Bridge
+	if bridge == nil {
+		bridge = NewNetDebtBridge(input.NetDebt)
+	}
+	res.NetDebt = bridge.Total()
+	res.EquityValue = bridge.EquityValue(res.EnterpriseValue)
+	if input.SharesOutstanding != 0 {
+		res.SharePrice = res.EquityValue / input.SharesOutstanding
+	}
+	return res
+}
+
+// projectedDebt is the interest-bearing debt on a projected balance sheet
+// projectedInterest is the year's interest expense as a positive amount
+func projectedInterest(proj *projection.ProjectedFinancials) float64 {
+	if proj.IncomeStatement == nil || proj.IncomeStatement.NonOperatingSection == nil {
+		return 0
+	}
+	return -getValSafe(proj.IncomeStatement.NonOperatingSection.InterestExpense) // Projected expense is negative
+}
+
+func projectedDebt(proj *projection.ProjectedFinancials) float64 {
+	if proj.BalanceSheet == nil {
+		return 0
+	}
+	return getValSafe(proj.BalanceSheet.NoncurrentLiabilities.LongTermDebt) +
+		getValSafe(proj.BalanceSheet.CurrentLiabilities.NotesPayableShortTermDebt) +
+		getValSafe(proj.BalanceSheet.CurrentLiabilities.CurrentMaturitiesLTD)
+}
@@ -0,0 +1,116 @@
+package valuation
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"agentic_valuation/pkg/core/edgar"
+)
+
+func TestCalculateAPV_ReconcilesToFCFF(t *testing.T) {
+	const (
+		ku, kd, tax, leverage = 0.10, 0.06, 0.25, 0.4
+		ufcf                  = 100.0
+	)
+	// Harris-Pringle: constant D/V keeps WACC at Ku - t*Kd*D/V
+	wacc := ku - tax*kd*leverage
+	value := ufcf / wacc // Flat perpetuity => value is the same at every year end
+	debt := leverage * value
+
+	projections := flatProjections(3, ufcf, 0)
+	for _, p := range projections {
+		p.BalanceSheet = &edgar.BalanceSheet{NoncurrentLiabilities: edgar.NoncurrentLiabilities{LongTermDebt: fsap(debt)}}
+	}
+
+	apv := CalculateAPV(APVInput{
+		Projections:       projections,
+		UnleveredCost:     ku,
+		PreTaxCostOfDebt:  kd,
+		TaxRate:           tax,
+		OpeningDebt:       debt,
+		SharesOutstanding: 1,
+		NetDebt:           debt,
+	})
+	dcf := CalculateDCF(DCFInput{Projections: projections, WACC: wacc, SharesOutstanding: 1, NetDebt: debt, TaxRate: tax})
+
+	if !almostEqual(apv.EnterpriseValue, dcf.EnterpriseValue) {
+		t.Errorf("APV EV %.6f, FCFF EV %.6f", apv.EnterpriseValue, dcf.EnterpriseValue)
+	}
+	if !almostEqual(apv.UnleveredValue, ufcf/ku) {
+		t.Errorf("unlevered value %.6f, want %.6f", apv.UnleveredValue, ufcf/ku)
+	}
+	if len(apv.InterestTaxShields) != 3 || !almostEqual(apv.InterestTaxShields[0], tax*kd*debt) {
+		t.Errorf("unexpected shields %v", apv.InterestTaxShields)
+	}
+	if math.Abs(apv.SharePrice-dcf.SharePrice) > 1e-9 {
+		t.Errorf("APV price %.6f, FCFF price %.6f", apv.SharePrice, dcf.SharePrice)
+	}
+}
+
+func TestRunAllValuations_IncludesAPVAndEconomicProfit(t *testing.T) {
+	items, _ := RunAllValuations(MasterValuationInput{
+		Projections:       operatingProjections(3),
+		SharesOutstanding: 1,
+		WACC:              0.09,
+		CostOfEquity:      0.10,
+		TaxRate:           0.25,
+	})
+	names := map[string]bool{}
+	for _, it := range items {
+		names[it.ModelName] = true
+	}
+	for _, want := range []string{ModelAPV, "Economic Profit Valuation (EVA)"} {
+		if !names[want] {
+			t.Errorf("missing line item %q", want)
+		}
+	}
+}
+
+func TestAPVInput_UnleversWACC(t *testing.T) {
+	// 40% debt at Kd 5%, Ke 10%, t 25% => WACC 7.5%, Ku 8%
+	m := MasterValuationInput{WACC: 0.075, CostOfEquity: 0.10, PreTaxCostOfDebt: 0.05, TaxRate: 0.25, NetDebt: 100}
+	if ku := m.APVInput().UnleveredCost; !almostEqual(ku, 0.08) {
+		t.Errorf("Ku = %.6f, want 0.08", ku)
+	}
+
+	m.UnleveredCost = 0.11
+	if ku := m.APVInput().UnleveredCost; ku != 0.11 {
+		t.Errorf("explicit Ku overridden: %.4f", ku)
+	}
+
+	// Levered without a cost of debt: Ku is not derivable and APV declines to value
+	m.UnleveredCost, m.PreTaxCostOfDebt = 0, 0
+	res := CalculateAPV(m.APVInput())
+	if res.EnterpriseValue != 0 || len(res.Warnings) == 0 {
+		t.Errorf("expected a missing-Ku warning, got EV %.2f %v", res.EnterpriseValue, res.Warnings)
+	}
+}
+
+func TestRunAllValuations_APVWithoutCostOfDebt(t *testing.T) {
+	// Kd implied from projected interest: 5 on opening debt of 100 => 5%, so Ku = 8% as above
+	projections := operatingProjections(3)
+	for _, p := range projections {
+		p.IncomeStatement.NonOperatingSection = &edgar.NonOperatingSection{InterestExpense: fsap(-5)}
+	}
+	m := MasterValuationInput{Projections: projections, SharesOutstanding: 1, WACC: 0.075, CostOfEquity: 0.10, TaxRate: 0.25, NetDebt: 100}
+	if ku := m.APVInput().UnleveredCost; !almostEqual(ku, 0.08) {
+		t.Errorf("Ku = %.6f, want 0.08 from the implied cost of debt", ku)
+	}
+
+	// No interest either: APV is left out rather than reported at zero, with its warning
+	m.Projections = operatingProjections(3)
+	items, warnings := RunAllValuations(m)
+	for _, it := range items {
+		if it.ModelName == ModelAPV {
+			t.Errorf("APV should be omitted without Ku, got %.2f per share", it.SharePrice)
+		}
+	}
+	found := false
+	for _, w := range warnings {
+		found = found || strings.HasPrefix(w, ModelAPV)
+	}
+	if !found {
+		t.Errorf("expected the APV warning, got %v", warnings)
+	}
+}
@@ -0,0 +1,171 @@
+package valuation
+
+import (
+	"agentic_valuation/pkg/core/edgar"
+	"math"
+	"strings"
+)
+
+// BridgeLine is one adjustment between enterprise value and common equity value.
+// Amount is what gets deducted from EV: claims (debt, leases, pensions, preferred,
+// minorities) are positive, non-operating assets (cash, investments) are negative.
+type BridgeLine struct {
+	Key    string           `json:"key"`   // e.g. "long_term_debt"
+	Label  string           `json:"label"` // Display label (source 10-K label when available)
+	Amount float64          `json:"amount"`
+	Source *edgar.FSAPValue `json:"source,omitempty"` // Provenance of the balance sheet value (nil for manual lines)
+}
+
+// EquityBridge lists every adjustment from EV to equity value
+type EquityBridge struct {
+	Lines []BridgeLine `json:"lines"`
+}
+
+// bridgeItem maps a balance sheet field onto a bridge line
+type bridgeItem struct {
+	key   string
+	label string
+	value func(bs *edgar.BalanceSheet) *edgar.FSAPValue
+	claim bool // true = deducted from EV, false = added to EV
+}
+
+var bridgeItems = []bridgeItem{
+	// Debt and debt-like claims
+	{"short_term_debt", "Short-term debt", func(bs *edgar.BalanceSheet) *edgar.FSAPValue { return bs.CurrentLiabilities.NotesPayableShortTermDebt }, true},
+	{"current_maturities_ltd", "Current maturities of long-term debt", func(bs *edgar.BalanceSheet) *edgar.FSAPValue { return bs.CurrentLiabilities.CurrentMaturitiesLTD }, true},
+	{"long_term_debt", "Long-term debt", func(bs *edgar.BalanceSheet) *edgar.FSAPValue { return bs.NoncurrentLiabilities.LongTermDebt }, true},
+	{"operating_lease_current", "Operating lease liabilities (current)", func(bs *edgar.BalanceSheet) *edgar.FSAPValue { return bs.CurrentLiabilities.CurrentOperatingLeaseLiab }, true},
+	{"operating_lease_noncurrent", "Operating lease liabilities (non-current)", func(bs *edgar.BalanceSheet) *edgar.FSAPValue {
+		return bs.NoncurrentLiabilities.LongTermOperatingLeaseLiab
+	}, true},
+	{"pension_obligations", "Pension obligations", func(bs *edgar.BalanceSheet) *edgar.FSAPValue { return bs.NoncurrentLiabilities.PensionObligations }, true},
+	{"preferred_stock", "Preferred stock", func(bs *edgar.BalanceSheet) *edgar.FSAPValue { return bs.Equity.PreferredStock }, true},
+	{"noncontrolling_interests", "Noncontrolling interests", func(bs *edgar.BalanceSheet) *edgar.FSAPValue { return bs.Equity.NoncontrollingInterests }, true},
+
+	// Non-operating assets
+	{"cash_and_equivalents", "Cash and equivalents", func(bs *edgar.BalanceSheet) *edgar.FSAPValue { return bs.CurrentAssets.CashAndEquivalents }, false},
+	{"short_term_investments", "Short-term investments", func(bs *edgar.BalanceSheet) *edgar.FSAPValue { return bs.CurrentAssets.ShortTermInvestments }, false},
+	{"long_term_investments", "Long-term investments", func(bs *edgar.BalanceSheet) *edgar.FSAPValue { return bs.NoncurrentAssets.LongTermInvestments }, false},
+}
+
+// equityMethodKeywords identify equity-method stakes among non-current additional items
+var equityMethodKeywords = []string{"equity method", "equity-method", "equity_method", "equity investee", "investments in affiliates", "unconsolidated affiliates"}
+
+// NewEquityBridge builds the bridge from the T-0 balance sheet.
+// Magnitudes are used so the sign convention of the extraction does not matter.
+func NewEquityBridge(bs *edgar.BalanceSheet) *EquityBridge {
+	b := &EquityBridge{}
+	if bs == nil {
+		return b
+	}
+
+	for _, item := range bridgeItems {
+		v := item.value(bs)
+		if v == nil || v.Value == nil || *v.Value == 0 {
+			continue
+		}
+		amount := math.Abs(*v.Value)
+		if !item.claim {
+			amount = -amount
+		}
+		b.Lines = append(b.Lines, BridgeLine{Key: item.key, Label: bridgeLabel(item.label, v), Amount: amount, Source: v})
+	}
+
+	for i := range bs.NoncurrentAssets.AdditionalItems {
+		v := &bs.NoncurrentAssets.AdditionalItems[i]
+		if v.Value == nil || *v.Value == 0 || !isEquityMethodStake(v) {
+			continue
+		}
+		b.Lines = append(b.Lines, BridgeLine{Key: "equity_method_investments", Label: bridgeLabel("Equity-method investments", v), Amount: -math.Abs(*v.Value), Source: v})
+	}
+	return b
+}
+
+// NewNetDebtBridge wraps a single net debt figure (legacy DCFInput.NetDebt)
+func NewNetDebtBridge(netDebt float64) *EquityBridge {
+	return &EquityBridge{Lines: []BridgeLine{{Key: "net_debt", Label: "Net debt", Amount: netDebt}}}
+}
+
+// Add appends a manual adjustment (e.g. litigation reserve, tax asset)
+func (b *EquityBridge) Add(key, label string, amount float64) {
+	b.Lines = append(b.Lines, BridgeLine{Key: key, Label: label, Amount: amount})
+}
+
+// netDebtAdjustmentKey is the line that carries a flexed "net_debt" driver on an itemized bridge
+const netDebtAdjustmentKey = "net_debt_adjustment"
+
+// WithTotal returns a copy of the bridge that deducts total from EV. The itemized
+// lines are kept and the difference is carried on a single adjustment line.
+func (b *EquityBridge) WithTotal(total float64) *EquityBridge {
+	out := &EquityBridge{}
+	for _, l := range b.Lines {
+		if l.Key != netDebtAdjustmentKey {
+			out.Lines = append(out.Lines, l)
+		}
+	}
+	if diff := total - out.Total(); diff != 0 {
+		out.Add(netDebtAdjustmentKey, "Net debt adjustment", diff)
+	}
+	return out
+}
+
+// Total is the net deduction from EV (the "net debt" equivalent)
+func (b *EquityBridge) Total() float64 {
+	total := 0.0
+	for _, l := range b.Lines {
+		total += l.Amount
+	}
+	return total
+}
+
+// Debt is the interest-bearing borrowing in the bridge (excludes leases,
+// pensions and other debt-like claims). A legacy net debt line counts when positive.
+func (b *EquityBridge) Debt() float64 {
+	total := 0.0
+	for _, l := range b.Lines {
+		switch l.Key {
+		case "short_term_debt", "current_maturities_ltd", "long_term_debt":
+			total += l.Amount
+		case "net_debt":
+			total += math.Max(l.Amount, 0)
+		}
+	}
+	return total
+}
+
+// Cash is the cash and equivalents in the bridge (excludes investments), as a
+// positive amount. A legacy net debt line counts when negative (net cash).
+func (b *EquityBridge) Cash() float64 {
+	total := 0.0
+	for _, l := range b.Lines {
+		switch l.Key {
+		case "cash_and_equivalents":
+			total -= l.Amount
+		case "net_debt":
+			total += math.Max(-l.Amount, 0)
+		}
+	}
+	return total
+}
+
+// EquityValue walks EV down to common equity value
+func (b *EquityBridge) EquityValue(enterpriseValue float64) float64 {
+	return enterpriseValue - b.Total()
+}
+
+func bridgeLabel(fallback string, v *edgar.FSAPValue) string {
+	if v.Label != "" {
+		return v.Label
+	}
+	return fallback
+}
+
+func isEquityMethodStake(v *edgar.FSAPValue) bool {
+	text := strings.ToLower(v.Label + " " + v.FSAPVariable)
+	for _, kw := range equityMethodKeywords {
+		if strings.Contains(text, kw) {
+			return true
+		}
+	}
+	return false
+}
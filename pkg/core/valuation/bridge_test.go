@@ -0,0 +1,110 @@
+package valuation
+
+import (
+	"testing"
+
+	"agentic_valuation/pkg/core/edgar"
+)
+
+func TestNewEquityBridge(t *testing.T) {
+	debt := fsap(300)
+	debt.Label = "Term debt"
+	debt.Provenance = &edgar.SourceTrace{SectionTitle: "Balance Sheet", RowLabel: "Term debt"}
+
+	stake := fsap(40)
+	stake.Label = "Investments in equity method investees"
+
+	bs := &edgar.BalanceSheet{
+		CurrentAssets: edgar.CurrentAssets{
+			CashAndEquivalents:   fsap(100),
+			ShortTermInvestments: fsap(50),
+		},
+		NoncurrentAssets: edgar.NoncurrentAssets{
+			LongTermInvestments: fsap(20),
+			AdditionalItems:     []edgar.FSAPValue{*stake, {Label: "Other", Value: fsap(99).Value}},
+		},
+		CurrentLiabilities: edgar.CurrentLiabilities{
+			CurrentMaturitiesLTD:      fsap(25),
+			CurrentOperatingLeaseLiab: fsap(10),
+		},
+		NoncurrentLiabilities: edgar.NoncurrentLiabilities{
+			LongTermDebt:               debt,
+			LongTermOperatingLeaseLiab: fsap(60),
+			PensionObligations:         fsap(15),
+		},
+		Equity: edgar.Equity{
+			PreferredStock:          fsap(5),
+			NoncontrollingInterests: fsap(-8), // Extracted with a negative sign
+		},
+	}
+
+	b := NewEquityBridge(bs)
+	if len(b.Lines) != 11 {
+		t.Fatalf("expected 11 bridge lines, got %d: %+v", len(b.Lines), b.Lines)
+	}
+
+	// Claims 300+25+10+60+15+5+8 = 423; assets 100+50+20+40 = 210
+	if got := b.Total(); !almostEqual(got, 213) {
+		t.Errorf("bridge total = %.2f, want 213", got)
+	}
+	if got := b.EquityValue(1000); !almostEqual(got, 787) {
+		t.Errorf("equity value = %.2f, want 787", got)
+	}
+
+	for _, l := range b.Lines {
+		if l.Key == "long_term_debt" {
+			if l.Source != debt || l.Label != "Term debt" {
+				t.Errorf("long-term debt line lost its provenance: %+v", l)
+			}
+		}
+		if l.Source == nil {
+			t.Errorf("line %s has no source", l.Key)
+		}
+	}
+}
+
+func TestCalculateDCF_BridgeOverridesNetDebt(t *testing.T) {
+	input := DCFInput{
+		Projections:       flatProjections(2, 100, 0),
+		WACC:              0.10,
+		SharesOutstanding: 10,
+		NetDebt:           999,
+	}
+	bridge := NewNetDebtBridge(200)
+	bridge.Add("litigation", "Litigation reserve", 50)
+	input.Bridge = bridge
+
+	res := CalculateDCF(input)
+	if !almostEqual(res.EquityValue, res.EnterpriseValue-250) {
+		t.Errorf("equity %.2f should be EV %.2f less bridge 250", res.EquityValue, res.EnterpriseValue)
+	}
+	if res.NetDebt != 250 || len(res.Bridge) != 2 {
+		t.Errorf("expected itemized bridge in result, got %.2f / %d lines", res.NetDebt, len(res.Bridge))
+	}
+}
+
+func TestSetDriver_NetDebtMovesBridge(t *testing.T) {
+	bridge := NewEquityBridge(&edgar.BalanceSheet{
+		CurrentAssets:         edgar.CurrentAssets{CashAndEquivalents: fsap(100)},
+		NoncurrentLiabilities: edgar.NoncurrentLiabilities{LongTermDebt: fsap(300)},
+	})
+	input := MasterValuationInput{NetDebt: 999, Bridge: bridge}
+	if got, _ := input.GetDriver("net_debt"); !almostEqual(got, 200) {
+		t.Fatalf("net_debt should read the bridge total, got %.2f", got)
+	}
+
+	input.SetDriver("net_debt", 150)
+	input.SetDriver("net_debt", 120) // Flexing again replaces the adjustment
+	if got, _ := input.GetDriver("net_debt"); !almostEqual(got, 120) {
+		t.Errorf("net_debt = %.2f after setting 120", got)
+	}
+	if got := input.bridge().EquityValue(1000); !almostEqual(got, 880) {
+		t.Errorf("equity value = %.2f, want 880", got)
+	}
+	if len(input.Bridge.Lines) != 3 || input.Bridge.Debt() != 300 || input.Bridge.Cash() != 100 {
+		t.Errorf("itemized lines should be kept beside one adjustment: %+v", input.Bridge.Lines)
+	}
+	if !almostEqual(bridge.Total(), 200) {
+		t.Errorf("the original bridge was modified: total %.2f", bridge.Total())
+	}
+}
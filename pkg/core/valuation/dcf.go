@@ -1,19 +1,22 @@
 package valuation
 
 import (
-	"agentic_valuation/pkg/core/edgar"
 	"agentic_valuation/pkg/core/projection"
+	"fmt"
 )
 
 // DCFInput encapsulates all inputs required for a Discounted Cash Flow valuation
 type DCFInput struct {
 	Projections       []*projection.ProjectedFinancials
-	WACC              float64   // Deprecated: Use PeriodWACC or single value as fallback
-	PeriodWACCs       []float64 // Optional: WACC per projection year
-	TerminalGrowth    float64   // e.g. 0.025
-	SharesOutstanding float64   // Millions
-	NetDebt           float64   // Millions
-	TaxRate           float64   // Used for adjustment
+	WACC              float64        // Deprecated: Use PeriodWACC or single value as fallback
+	PeriodWACCs       []float64      // Optional: WACC per projection year
+	TerminalGrowth    float64        // e.g. 0.025
+	SharesOutstanding float64        // Millions
+	NetDebt           float64        // Millions (ignored when Bridge is set)
+	Bridge            *EquityBridge  // Optional: itemized EV-to-equity adjustments
+	TaxRate           float64        // Used for adjustment
+	Timing            DiscountTiming // Valuation date, stub period and mid-year convention
+	Terminal          TerminalValueConfig
 }
 
 // DCFResult holds the valuation outputs
@@ -24,56 +27,30 @@ type DCFResult struct {
 	PV_FCF          float64
 	PV_Terminal     float64
 	ImpliedMultiple float64 // EV / EBITDA (Terminal Year)
+	NetDebt         float64 // Total EV-to-equity deduction applied
+	Bridge          []BridgeLine
+
+	// Terminal value reconciliation
+	TerminalMethod       TerminalMethod
+	TerminalValue        float64  // Undiscounted TV at the horizon
+	ImpliedEBITMultiple  float64  // TV / final-year EBIT
+	ImpliedGrowth        float64  // Perpetuity growth implied by TV (equals TerminalGrowth for the growth method)
+	TerminalShareOfEV    float64  // PV_Terminal / EnterpriseValue
+	TerminalShareWarning bool     // TerminalShareOfEV exceeded the configured threshold
+	Warnings             []string // Cross-check and input messages
 }
 
 // CalculateDCF performs a standard 2-stage DCF analysis
 func CalculateDCF(input DCFInput) DCFResult {
 	var pvFCF float64
-	var terminalEBITDA float64
-	var terminalUFCF float64
+	var terminal terminalBase
 
 	// Track cumulative discount factor for dynamic WACC
-	cumDiscountFactor := 1.0
-
-	// Helper for safe value extraction
-	getVal := func(v *edgar.FSAPValue) float64 {
-		if v != nil && v.Value != nil {
-			return *v.Value
-		}
-		return 0.0
-	}
+	disc := newDiscounter(input.Timing)
 
 	for i, proj := range input.Projections {
 		// 1. Calculate UFCF
-		// Start with Cash Flow From Operations (CFO)
-		var cfo float64
-		if proj.CashFlow != nil {
-			if proj.CashFlow.CashSummary != nil {
-				cfo = getVal(proj.CashFlow.CashSummary.NetCashOperating)
-			}
-			// If missing, try to sum OperatingActivities?
-			// For Projections, CashSummary is always populated by Engine.
-		}
-
-		// Add back tax-shielded interest (to get to Unlevered)
-		var interest float64
-		if proj.IncomeStatement != nil {
-			if proj.IncomeStatement.NonOperatingSection != nil {
-				interest = getVal(proj.IncomeStatement.NonOperatingSection.InterestExpense)
-			}
-		}
-		interestAdj := interest * (1 - input.TaxRate)
-
-		// Subtract CapEx (add signed negative)
-		var capex float64
-		if proj.CashFlow != nil {
-			if proj.CashFlow.InvestingActivities != nil {
-				capex = getVal(proj.CashFlow.InvestingActivities.Capex)
-			}
-		}
-
-		// UFCF = CFO + Interest(1-t) + CapEx(Negative)
-		ufcf := cfo + interestAdj + capex
+		ufcf, base := dcfYearMetrics(proj, input.TaxRate)
 
 		// 2. Discount (Dynamic WACC)
 		wacc := input.WACC
@@ -81,62 +58,141 @@ func CalculateDCF(input DCFInput) DCFResult {
 			wacc = input.PeriodWACCs[i]
 		}
 
-		cumDiscountFactor /= (1.0 + wacc)
-		pvFCF += ufcf * cumDiscountFactor
+		fraction, factor := disc.next(wacc)
+		pvFCF += ufcf * fraction * factor
 
 		// Store last year metrics for Terminal Value
 		if i == len(input.Projections)-1 {
-			// Terminal UFCF
-			terminalUFCF = ufcf * (1 + input.TerminalGrowth)
-
-			// EBITDA for implied multiple
-			var opIncome, depn float64
-			if proj.IncomeStatement != nil && proj.IncomeStatement.OperatingCostSection != nil {
-				opIncome = getVal(proj.IncomeStatement.OperatingCostSection.OperatingIncome)
-			}
-			if proj.CashFlow != nil && proj.CashFlow.OperatingActivities != nil {
-				depn = getVal(proj.CashFlow.OperatingActivities.DepreciationAmortization)
-			}
-			terminalEBITDA = opIncome + depn
+			terminal = base
 		}
 	}
 
-	// 3. Terminal Value (Gordon Growth)
-	// TV = (TerminalUFCF) / (WACC_final - g)
+	// 3. Terminal Value
 	// Use final year WACC for capitalization
 	finalWACC := input.WACC
 	if len(input.PeriodWACCs) > 0 {
 		finalWACC = input.PeriodWACCs[len(input.PeriodWACCs)-1]
 	}
 
-	tv := 0.0
-	if finalWACC > input.TerminalGrowth {
-		tv = terminalUFCF / (finalWACC - input.TerminalGrowth)
+	method := input.Terminal.Method
+	if method == "" {
+		method = TerminalPerpetuityGrowth
+	}
+	var warnings []string
+	if err := input.Timing.Validate(); err != nil {
+		warnings = append(warnings, err.Error())
+	}
+	tv, perpetuity, err := terminalValue(input.Terminal, terminal, finalWACC, input.TerminalGrowth)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("terminal value set to zero: %v", err))
 	}
 
-	// Discount TV
-	pvTerminal := tv * cumDiscountFactor
+	// Discount TV (exit prices are struck at the horizon; perpetuities follow the mid-year convention)
+	pvTerminal := tv * disc.horizon()
+	if perpetuity {
+		pvTerminal = tv * disc.terminal(finalWACC)
+	}
 
 	// 4. Aggregation
 	ev := pvFCF + pvTerminal
-	eqVal := ev - input.NetDebt
+	bridge := input.Bridge
+	if bridge == nil {
+		bridge = NewNetDebtBridge(input.NetDebt)
+	}
+	eqVal := bridge.EquityValue(ev)
 	sharePrice := 0.0
 	if input.SharesOutstanding != 0 {
 		sharePrice = eqVal / input.SharesOutstanding
 	}
 
-	// Implied Exit Multiple
-	impliedMultiple := 0.0
-	if terminalEBITDA != 0 {
-		impliedMultiple = tv / terminalEBITDA
+	// Implied counterparts: exit multiples for growth methods, growth for exit methods
+	impliedMultiple, impliedEBITMultiple := 0.0, 0.0
+	if terminal.EBITDA != 0 {
+		impliedMultiple = tv / terminal.EBITDA
+	}
+	if terminal.EBIT != 0 {
+		impliedEBITMultiple = tv / terminal.EBIT
+	}
+	impliedGrowth := input.TerminalGrowth
+	if method != TerminalPerpetuityGrowth {
+		impliedGrowth = impliedPerpetuityGrowth(tv, terminal.UFCF, finalWACC)
+	}
+
+	// Cross-check: a terminal value dominating EV means the explicit forecast carries little weight
+	tvShare := 0.0
+	if ev != 0 {
+		tvShare = pvTerminal / ev
+	}
+	maxShare := input.Terminal.MaxShareOfEV
+	if maxShare == 0 {
+		maxShare = DefaultMaxTerminalShare
+	}
+	shareWarning := tvShare > maxShare
+	if shareWarning {
+		warnings = append(warnings, fmt.Sprintf("terminal value is %.0f%% of enterprise value (threshold %.0f%%)", tvShare*100, maxShare*100))
 	}
 
 	return DCFResult{
-		EnterpriseValue: ev,
-		EquityValue:     eqVal,
-		SharePrice:      sharePrice,
-		PV_FCF:          pvFCF,
-		PV_Terminal:     pvTerminal,
-		ImpliedMultiple: impliedMultiple,
+		EnterpriseValue:      ev,
+		EquityValue:          eqVal,
+		SharePrice:           sharePrice,
+		PV_FCF:               pvFCF,
+		PV_Terminal:          pvTerminal,
+		ImpliedMultiple:      impliedMultiple,
+		NetDebt:              bridge.Total(),
+		Bridge:               bridge.Lines,
+		TerminalMethod:       method,
+		TerminalValue:        tv,
+		ImpliedEBITMultiple:  impliedEBITMultiple,
+		ImpliedGrowth:        impliedGrowth,
+		TerminalShareOfEV:    tvShare,
+		TerminalShareWarning: shareWarning,
+		Warnings:             warnings,
+	}
+}
+
+// dcfYearMetrics returns a projection year's unlevered free cash flow and the
+// metrics the terminal value methods need if it is the final year
+func dcfYearMetrics(proj *projection.ProjectedFinancials, taxRate float64) (float64, terminalBase) {
+	// Start with Cash Flow From Operations (CFO)
+	// For Projections, CashSummary is always populated by Engine.
+	var cfo, capex, depn float64
+	if proj.CashFlow != nil {
+		if proj.CashFlow.CashSummary != nil {
+			cfo = getValSafe(proj.CashFlow.CashSummary.NetCashOperating)
+		}
+		// CapEx is signed negative
+		if proj.CashFlow.InvestingActivities != nil {
+			capex = getValSafe(proj.CashFlow.InvestingActivities.Capex)
+		}
+		if proj.CashFlow.OperatingActivities != nil {
+			depn = getValSafe(proj.CashFlow.OperatingActivities.DepreciationAmortization)
+		}
+	}
+
+	// Add back tax-shielded interest (to get to Unlevered)
+	var interest, opIncome float64
+	if proj.IncomeStatement != nil {
+		if proj.IncomeStatement.NonOperatingSection != nil {
+			interest = getValSafe(proj.IncomeStatement.NonOperatingSection.InterestExpense)
+		}
+		if proj.IncomeStatement.OperatingCostSection != nil {
+			opIncome = getValSafe(proj.IncomeStatement.OperatingCostSection.OperatingIncome)
+		}
+	}
+
+	// UFCF = CFO + Interest Expense(1-t) + CapEx(Negative); projected interest is
+	// signed negative for an expense, so the after-tax expense is -interest(1-t)
+	ufcf := cfo - interest*(1-taxRate) + capex
+	if proj.Tax != nil {
+		// CFO already pays cash taxes (NOLs, deferred taxes): add back the net interest
+		// expense and remove the cash tax it saved
+		ufcf = cfo - interest - (proj.Tax.UnleveredCashTax - proj.Tax.CashTax) + capex
+	}
+	return ufcf, terminalBase{
+		UFCF:   ufcf,
+		EBITDA: opIncome + depn,
+		EBIT:   opIncome,
+		NOPAT:  opIncome * (1 - taxRate), // Terminal years are taxed at the statutory rate; NOLs run out
 	}
 }
@@ -0,0 +1,143 @@
+package valuation
+
+import (
+	"fmt"
+	"sort"
+
+	"agentic_valuation/pkg/core/debate"
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/projection"
+)
+
+// debateHorizonYears is the forecast the debate's reverse DCF projects over
+const debateHorizonYears = 5
+
+// debateReverseDrivers are back-solved for the Skeptic and Optimist
+var debateReverseDrivers = []ReverseDriver{ReverseRevenueGrowth, ReverseOperatingMargin}
+
+// DebateCase is the deterministic case a debate's material pool is valued on
+type DebateCase struct {
+	Valuation   MasterValuationInput
+	Assumptions projection.ProjectionAssumptions
+	History     projection.History
+	MarketPrice float64 // Year-end share price
+}
+
+// NewDebateCase projects a debate's material pool: the latest fiscal year is T-0,
+// the Quant baselines overlay the default assumptions, the pool's debt and tax notes
+// seed the debt schedule and NOLs, and the year-end share price is the market price.
+func NewDebateCase(pool *debate.MaterialPool, baselines map[string]float64) (*DebateCase, error) {
+	if pool == nil || len(pool.FinancialHistory) == 0 {
+		return nil, fmt.Errorf("material pool has no financial history")
+	}
+	latestYear := 0
+	for year := range pool.FinancialHistory[0].HistoricalData {
+		if year > latestYear {
+			latestYear = year
+		}
+	}
+	data, ok := pool.FinancialHistory[0].HistoricalData[latestYear]
+	if !ok {
+		return nil, fmt.Errorf("material pool has no historical year data")
+	}
+	supp := data.SupplementalData
+	if supp.SharePriceYearEnd == nil || *supp.SharePriceYearEnd <= 0 {
+		return nil, fmt.Errorf("FY%d has no year-end share price", latestYear)
+	}
+	shares := getValSafe(supp.SharesOutstandingDiluted)
+	if shares <= 0 {
+		shares = getValSafe(supp.SharesOutstandingBasic)
+	}
+	if shares <= 0 {
+		return nil, fmt.Errorf("FY%d has no shares outstanding", latestYear)
+	}
+
+	a := projection.ConvertDebateReportToAssumptions(nil)
+	a.SellingMarketingPercent, a.GeneralAdminPercent = 0, 0 // the baselines carry aggregate SG&A
+	a.SharesOutstanding = shares
+	names := make([]string, 0, len(baselines))
+	for name := range baselines {
+		if projection.IsDriverName(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := a.SetDriver(name, baselines[name]); err != nil {
+			return nil, err
+		}
+	}
+	notes := make([]*edgar.ExtractedNote, len(pool.ExtractedNotes))
+	for i := range pool.ExtractedNotes {
+		notes[i] = &pool.ExtractedNotes[i]
+	}
+	a.ApplyNotes(notes, &data.BalanceSheet, latestYear)
+
+	hist := projection.History{IncomeStatement: &data.IncomeStatement, BalanceSheet: &data.BalanceSheet, FiscalYear: latestYear}
+	projections, err := projection.NewProjectionEngine(nil).ProjectHorizon(projection.HorizonInput{
+		History:     hist,
+		Assumptions: a,
+		Years:       debateHorizonYears,
+	})
+	if err != nil {
+		return nil, err
+	}
+	wacc := CalculateWACC(WACCInput{
+		UnleveredBeta:     a.UnleveredBeta,
+		RiskFreeRate:      a.RiskFreeRate,
+		MarketRiskPremium: a.MarketRiskPremium,
+		PreTaxCostOfDebt:  a.PreTaxCostOfDebt,
+		TaxRate:           a.TaxRate,
+		DebtToEquityRatio: a.TargetDebtEquity,
+	})
+	return &DebateCase{
+		Valuation: MasterValuationInput{
+			Projections:       projections,
+			SharesOutstanding: shares,
+			Bridge:            NewEquityBridge(&data.BalanceSheet),
+			WACC:              wacc.WACC,
+			CostOfEquity:      wacc.CostOfEquity,
+			TerminalGrowth:    a.TerminalGrowth,
+			TaxRate:           a.TaxRate,
+		},
+		Assumptions: a,
+		History:     hist,
+		MarketPrice: *supp.SharePriceYearEnd,
+	}, nil
+}
+
+// DebateExpectations back-solves the market-implied drivers of the pool's
+// NewDebateCase. It satisfies debate.ExpectationSolver.
+func DebateExpectations(pool *debate.MaterialPool, baselines map[string]float64) ([]debate.ImpliedExpectation, error) {
+	dc, err := NewDebateCase(pool, baselines)
+	if err != nil {
+		return nil, err
+	}
+
+	var expectations []debate.ImpliedExpectation
+	var lastErr error
+	for _, driver := range debateReverseDrivers {
+		res, err := SolveReverseDCF(ReverseDCFInput{
+			Base:        dc.Valuation,
+			Assumptions: dc.Assumptions,
+			History:     dc.History,
+			MarketPrice: dc.MarketPrice,
+			Driver:      driver,
+		})
+		if err != nil {
+			lastErr = fmt.Errorf("%s: %w", driver, err)
+			continue
+		}
+		expectations = append(expectations, debate.ImpliedExpectation{
+			Driver:      string(res.Driver),
+			Implied:     res.ImpliedValue,
+			Base:        res.BaseValue,
+			MarketPrice: res.MarketPrice,
+			BasePrice:   res.BasePrice,
+		})
+	}
+	if len(expectations) == 0 {
+		return nil, lastErr
+	}
+	return expectations, nil
+}
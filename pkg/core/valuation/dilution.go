@@ -0,0 +1,184 @@
+package valuation
+
+import (
+	"agentic_valuation/pkg/core/edgar"
+	"math"
+	"strings"
+)
+
+// OptionTranche is a block of options sharing a (weighted average) strike
+type OptionTranche struct {
+	Count  float64 `json:"count"`  // Same unit as SharesOutstanding
+	Strike float64 `json:"strike"` // Exercise price per share
+}
+
+// Convertible is a convertible bond treated with the if-converted method
+type Convertible struct {
+	Name            string  `json:"name,omitempty"`
+	Principal       float64 `json:"principal"`        // Same unit as equity value
+	ConversionPrice float64 `json:"conversion_price"` // Per share
+}
+
+// DilutiveSecurities are the instruments that dilute the basic share count
+type DilutiveSecurities struct {
+	Options      []OptionTranche `json:"options,omitempty"`
+	RSUs         float64         `json:"rsus,omitempty"` // Unvested units, always dilutive
+	Convertibles []Convertible   `json:"convertibles,omitempty"`
+}
+
+// DilutionResult is the fixed point of price and diluted share count
+type DilutionResult struct {
+	BasicShares       float64 `json:"basic_shares"`
+	DilutedShares     float64 `json:"diluted_shares"`
+	OptionShares      float64 `json:"option_shares"` // Net new shares under the treasury stock method
+	RSUShares         float64 `json:"rsu_shares"`
+	ConvertibleShares float64 `json:"convertible_shares"` // In-the-money conversions
+	EquityValue       float64 `json:"equity_value"`       // Plus principal of converted bonds
+	SharePrice        float64 `json:"share_price"`        // Diluted value per share
+	Iterations        int     `json:"iterations"`
+	Converged         bool    `json:"converged"`
+}
+
+const (
+	dilutionMaxIterations = 100
+	dilutionTolerance     = 1e-9
+)
+
+// SolveDilutedShares finds the diluted share count at the implied share price.
+// Dilution depends on price (options and convertibles are only exercised when in
+// the money) and price depends on dilution, so the two are iterated to a fixed point.
+// equityValue is the common equity value with convertibles still counted as debt.
+func SolveDilutedShares(equityValue, basicShares float64, sec DilutiveSecurities) DilutionResult {
+	res := DilutionResult{BasicShares: basicShares, DilutedShares: basicShares, EquityValue: equityValue}
+	if basicShares <= 0 {
+		return res
+	}
+
+	price := equityValue / basicShares
+	for res.Iterations < dilutionMaxIterations {
+		res.Iterations++
+
+		res.OptionShares = 0
+		for _, o := range sec.Options {
+			if price > o.Strike && price > 0 {
+				// Treasury stock method: proceeds buy back Count*Strike/price shares
+				res.OptionShares += o.Count * (1 - o.Strike/price)
+			}
+		}
+		res.RSUShares = sec.RSUs
+
+		res.ConvertibleShares = 0
+		res.EquityValue = equityValue
+		for _, c := range sec.Convertibles {
+			if c.ConversionPrice > 0 && price > c.ConversionPrice {
+				res.ConvertibleShares += c.Principal / c.ConversionPrice
+				res.EquityValue += c.Principal // Debt no longer repaid in cash
+			}
+		}
+
+		res.DilutedShares = basicShares + res.OptionShares + res.RSUShares + res.ConvertibleShares
+		next := res.EquityValue / res.DilutedShares
+		if math.Abs(next-price) <= dilutionTolerance*math.Max(1, math.Abs(price)) {
+			res.SharePrice = next
+			res.Converged = true
+			return res
+		}
+		price = next
+	}
+	res.SharePrice = price
+	return res
+}
+
+// DilutiveSecuritiesFromNotes reads options, RSUs and convertibles from the
+// stock-compensation and debt notes produced by edgar.NoteExtractor.
+// LLM structured fields are preferred; note tables are the fallback for equity awards.
+func DilutiveSecuritiesFromNotes(notes []*edgar.ExtractedNote, fiscalYear int) DilutiveSecurities {
+	var sec DilutiveSecurities
+	for _, n := range notes {
+		if n == nil {
+			continue
+		}
+		switch n.NoteCategory {
+		case edgar.NoteCategoryStockComp:
+			count, okCount := structuredFloat(n.StructuredData, "options_outstanding")
+			strike, _ := structuredFloat(n.StructuredData, "weighted_average_exercise_price")
+			rsus, okRSU := structuredFloat(n.StructuredData, "rsus_outstanding")
+			if !okCount {
+				count, okCount = noteTableValue(n.Tables, fiscalYear, isOptionCountRow)
+			}
+			if strike == 0 {
+				strike, _ = noteTableValue(n.Tables, fiscalYear, isExercisePriceRow)
+			}
+			if !okRSU {
+				rsus, _ = noteTableValue(n.Tables, fiscalYear, isRSUCountRow)
+			}
+			if okCount && count > 0 {
+				sec.Options = append(sec.Options, OptionTranche{Count: count, Strike: strike})
+			}
+			sec.RSUs += rsus
+		case edgar.NoteCategoryDebt:
+			instruments, _ := n.StructuredData["debt_instruments"].([]interface{})
+			for _, raw := range instruments {
+				inst, ok := raw.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				convPrice, ok := structuredFloat(inst, "conversion_price")
+				if !ok || convPrice <= 0 {
+					continue
+				}
+				principal, _ := structuredFloat(inst, "principal")
+				name, _ := inst["type"].(string)
+				sec.Convertibles = append(sec.Convertibles, Convertible{Name: name, Principal: principal, ConversionPrice: convPrice})
+			}
+		}
+	}
+	return sec
+}
+
+func structuredFloat(data map[string]interface{}, key string) (float64, bool) {
+	if data == nil {
+		return 0, false
+	}
+	v, ok := data[key].(float64)
+	return v, ok
+}
+
+// noteTableValue returns the fiscal-year value of the first matching row
+// (latest year when the fiscal year column is absent)
+func noteTableValue(tables []edgar.NoteTable, fiscalYear int, match func(label string) bool) (float64, bool) {
+	var best *edgar.NoteTableRow
+	for ti := range tables {
+		for ri := range tables[ti].Rows {
+			row := &tables[ti].Rows[ri]
+			if row.Value == nil || !match(strings.ToLower(row.RowLabel)) {
+				continue
+			}
+			if row.ColumnYear == fiscalYear {
+				return *row.Value, true
+			}
+			if best == nil || row.ColumnYear > best.ColumnYear {
+				best = row
+			}
+		}
+	}
+	if best != nil {
+		return *best.Value, true
+	}
+	return 0, false
+}
+
+func isOptionCountRow(label string) bool {
+	return strings.Contains(label, "option") && strings.Contains(label, "outstanding") &&
+		!strings.Contains(label, "price") && !strings.Contains(label, "intrinsic") && !strings.Contains(label, "term")
+}
+
+func isExercisePriceRow(label string) bool {
+	return strings.Contains(label, "exercise price")
+}
+
+func isRSUCountRow(label string) bool {
+	isRSU := strings.Contains(label, "rsu") || strings.Contains(label, "restricted stock unit") || strings.Contains(label, "restricted share unit")
+	isBalance := strings.Contains(label, "outstanding") || strings.Contains(label, "unvested") || strings.Contains(label, "nonvested")
+	return isRSU && isBalance && !strings.Contains(label, "fair value") && !strings.Contains(label, "grant date")
+}
@@ -0,0 +1,81 @@
+package valuation
+
+import (
+	"testing"
+
+	"agentic_valuation/pkg/core/edgar"
+)
+
+func TestSolveDilutedShares_TreasuryStockMethod(t *testing.T) {
+	// E = 1000, B = 100, 10 options @ 5 and 5 RSUs.
+	// Fixed point: p = (E + n*K) / (B + n + RSU) = 1050 / 115
+	res := SolveDilutedShares(1000, 100, DilutiveSecurities{
+		Options: []OptionTranche{{Count: 10, Strike: 5}, {Count: 50, Strike: 20}}, // Second tranche out of the money
+		RSUs:    5,
+	})
+	if !res.Converged {
+		t.Fatalf("did not converge after %d iterations", res.Iterations)
+	}
+	want := 1050.0 / 115.0
+	if !almostEqual(res.SharePrice, want) {
+		t.Errorf("price = %.6f, want %.6f", res.SharePrice, want)
+	}
+	if !almostEqual(res.DilutedShares, 1000/want) {
+		t.Errorf("diluted shares = %.4f inconsistent with price", res.DilutedShares)
+	}
+	if res.RSUShares != 5 {
+		t.Errorf("RSUs should always dilute, got %.2f", res.RSUShares)
+	}
+}
+
+func TestSolveDilutedShares_Convertible(t *testing.T) {
+	conv := DilutiveSecurities{Convertibles: []Convertible{{Principal: 100, ConversionPrice: 8}}}
+
+	// In the money: 12.5 new shares, principal added back to equity
+	res := SolveDilutedShares(1000, 100, conv)
+	if !almostEqual(res.SharePrice, 1100/112.5) || res.ConvertibleShares != 12.5 {
+		t.Errorf("unexpected if-converted result: %+v", res)
+	}
+
+	// Out of the money: stays debt
+	res = SolveDilutedShares(500, 100, conv)
+	if res.ConvertibleShares != 0 || !almostEqual(res.SharePrice, 5) {
+		t.Errorf("out-of-the-money convertible should not dilute: %+v", res)
+	}
+}
+
+func TestDilutiveSecuritiesFromNotes(t *testing.T) {
+	v := func(f float64) *float64 { return &f }
+	notes := []*edgar.ExtractedNote{
+		{
+			NoteCategory: edgar.NoteCategoryStockComp,
+			Tables: []edgar.NoteTable{{Rows: []edgar.NoteTableRow{
+				{RowLabel: "Options outstanding, end of year", ColumnYear: 2023, Value: v(12)},
+				{RowLabel: "Options outstanding, end of year", ColumnYear: 2024, Value: v(10)},
+				{RowLabel: "Weighted average exercise price", ColumnYear: 2024, Value: v(42.5)},
+				{RowLabel: "Unvested RSUs outstanding", ColumnYear: 2024, Value: v(3)},
+				{RowLabel: "RSU grant date fair value", ColumnYear: 2024, Value: v(99)},
+			}}},
+		},
+		{
+			NoteCategory: edgar.NoteCategoryDebt,
+			StructuredData: map[string]interface{}{
+				"debt_instruments": []interface{}{
+					map[string]interface{}{"type": "Senior notes", "principal": 500.0},
+					map[string]interface{}{"type": "0.5% Convertible notes", "principal": 200.0, "conversion_price": 80.0},
+				},
+			},
+		},
+	}
+
+	sec := DilutiveSecuritiesFromNotes(notes, 2024)
+	if len(sec.Options) != 1 || sec.Options[0].Count != 10 || sec.Options[0].Strike != 42.5 {
+		t.Errorf("unexpected options: %+v", sec.Options)
+	}
+	if sec.RSUs != 3 {
+		t.Errorf("RSUs = %.2f, want 3", sec.RSUs)
+	}
+	if len(sec.Convertibles) != 1 || sec.Convertibles[0].ConversionPrice != 80 {
+		t.Errorf("unexpected convertibles: %+v", sec.Convertibles)
+	}
+}
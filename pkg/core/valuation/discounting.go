@@ -0,0 +1,132 @@
+package valuation
+
+import (
+	"agentic_valuation/pkg/core/edgar"
+	"fmt"
+	"math"
+	"time"
+)
+
+// DiscountTiming places the projection periods relative to the valuation date.
+// The zero value reproduces the legacy convention: valuation at the last fiscal
+// year end, full-year periods, cash flows at period end.
+type DiscountTiming struct {
+	ValuationDate time.Time // Date the value is struck at (zero = FiscalYearEnd)
+	FiscalYearEnd time.Time // Balance sheet date of the last actual year (T-0)
+	MidYear       bool      // Discount each period's flow from the middle of the period
+}
+
+// NewDiscountTiming derives the timing from the filing the projections are based on
+func NewDiscountTiming(meta *edgar.FilingMetadata, valuationDate time.Time, midYear bool) (DiscountTiming, error) {
+	fye, err := FiscalYearEndDate(meta)
+	if err != nil {
+		return DiscountTiming{}, err
+	}
+	t := DiscountTiming{ValuationDate: valuationDate, FiscalYearEnd: fye, MidYear: midYear}
+	if err := t.Validate(); err != nil {
+		return DiscountTiming{}, err
+	}
+	return t, nil
+}
+
+// Validate checks the valuation date falls within the first projection year.
+// A later date means a whole projected year has already elapsed: the forecast
+// should be rebased on the next fiscal year's filing rather than discounted from here.
+func (t DiscountTiming) Validate() error {
+	if t.ValuationDate.IsZero() || t.FiscalYearEnd.IsZero() {
+		return nil
+	}
+	if t.ValuationDate.Before(t.FiscalYearEnd) {
+		return fmt.Errorf("valuation date %s precedes fiscal year end %s",
+			t.ValuationDate.Format(time.DateOnly), t.FiscalYearEnd.Format(time.DateOnly))
+	}
+	if periodEnd := t.FiscalYearEnd.AddDate(1, 0, 0); t.ValuationDate.After(periodEnd) {
+		return fmt.Errorf("valuation date %s is more than a year after fiscal year end %s; project from a later fiscal year",
+			t.ValuationDate.Format(time.DateOnly), t.FiscalYearEnd.Format(time.DateOnly))
+	}
+	return nil
+}
+
+// FiscalYearEndDate resolves the period end of a 10-K.
+// Prefers the SEC report date (exact for 52/53-week years), then the "MMDD"
+// fiscal year end combined with FiscalYear, then December 31.
+func FiscalYearEndDate(meta *edgar.FilingMetadata) (time.Time, error) {
+	if meta == nil {
+		return time.Time{}, fmt.Errorf("filing metadata is required to resolve the fiscal year end")
+	}
+	if meta.ReportDate != "" {
+		d, err := time.Parse(time.DateOnly, meta.ReportDate)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid report date '%s': %w", meta.ReportDate, err)
+		}
+		return d, nil
+	}
+	if meta.FiscalYear == 0 {
+		return time.Time{}, fmt.Errorf("filing %s has neither a report date nor a fiscal year", meta.AccessionNumber)
+	}
+	if meta.FiscalYearEnd != "" {
+		d, err := time.Parse("20060102", fmt.Sprintf("%04d%s", meta.FiscalYear, meta.FiscalYearEnd))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid fiscal year end '%s': %w", meta.FiscalYearEnd, err)
+		}
+		return d, nil
+	}
+	return time.Date(meta.FiscalYear, time.December, 31, 0, 0, 0, 0, time.UTC), nil
+}
+
+// StubFraction is the share of the first projection year remaining after the
+// valuation date (1 = full year, legacy behaviour). Timings that fail Validate
+// are clamped to [0, 1].
+func (t DiscountTiming) StubFraction() float64 {
+	if t.ValuationDate.IsZero() || t.FiscalYearEnd.IsZero() {
+		return 1
+	}
+	periodEnd := t.FiscalYearEnd.AddDate(1, 0, 0)
+	remaining := periodEnd.Sub(t.ValuationDate).Hours() / periodEnd.Sub(t.FiscalYearEnd).Hours()
+	return math.Max(0, math.Min(1, remaining))
+}
+
+// discounter walks the projection periods accumulating discount factors.
+// Only the stub fraction of the first year's flow is counted; later years count in full.
+type discounter struct {
+	midYear   bool
+	stub      float64
+	period    int
+	endFactor float64 // Cumulative discount factor at the end of the last period
+}
+
+func newDiscounter(t DiscountTiming) *discounter {
+	return &discounter{midYear: t.MidYear, stub: t.StubFraction(), endFactor: 1.0}
+}
+
+// next advances one period at the given rate and returns the share of the
+// period's flow to count and the discount factor to apply to it
+func (d *discounter) next(rate float64) (fraction, factor float64) {
+	fraction = 1.0
+	if d.period == 0 {
+		fraction = d.stub
+	}
+	d.period++
+
+	start := d.endFactor
+	d.endFactor = start / math.Pow(1+rate, fraction)
+	if d.midYear {
+		return fraction, start / math.Pow(1+rate, fraction/2)
+	}
+	return fraction, d.endFactor
+}
+
+// horizon returns the discount factor at the end of the last period walked
+func (d *discounter) horizon() float64 {
+	return d.endFactor
+}
+
+// terminal returns the discount factor for a growing perpetuity valued at the
+// end of the horizon. Under the mid-year convention the perpetuity's flows also
+// arrive mid-period, so it is pulled forward half a year at the capitalization rate.
+func (d *discounter) terminal(rate float64) float64 {
+	if d.midYear {
+		return d.endFactor * math.Sqrt(1+rate)
+	}
+	return d.endFactor
+}
@@ -0,0 +1,149 @@
+package valuation
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/projection"
+)
+
+func fsap(v float64) *edgar.FSAPValue {
+	return &edgar.FSAPValue{Value: &v}
+}
+
+// flatProjections builds n years with CFO = cfo, no capex, no interest and dividends = div
+func flatProjections(n int, cfo, div float64) []*projection.ProjectedFinancials {
+	out := make([]*projection.ProjectedFinancials, n)
+	for i := range out {
+		out[i] = &projection.ProjectedFinancials{
+			Year:            2025 + i,
+			IncomeStatement: &edgar.IncomeStatement{},
+			CashFlow: &edgar.CashFlowStatement{
+				CashSummary:         &edgar.CashSummarySection{NetCashOperating: fsap(cfo)},
+				FinancingActivities: &edgar.CFFinancingSection{DividendsPaid: fsap(div)},
+			},
+		}
+	}
+	return out
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9*math.Max(1, math.Abs(b))
+}
+
+func TestCalculateDCF_ZeroTimingMatchesEndOfYear(t *testing.T) {
+	res := CalculateDCF(DCFInput{
+		Projections:       flatProjections(2, 100, 0),
+		WACC:              0.10,
+		SharesOutstanding: 1,
+	})
+	want := 100/1.1 + 100/1.21 // g = 0 => TV = 100/0.10 discounted two years
+	want += 1000 / 1.21
+	if !almostEqual(res.EnterpriseValue, want) {
+		t.Errorf("EV = %.6f, want %.6f", res.EnterpriseValue, want)
+	}
+}
+
+func TestCalculateDCF_MidYear(t *testing.T) {
+	res := CalculateDCF(DCFInput{
+		Projections:       flatProjections(2, 100, 0),
+		WACC:              0.10,
+		SharesOutstanding: 1,
+		Timing:            DiscountTiming{MidYear: true},
+	})
+	want := 100/math.Pow(1.1, 0.5) + 100/math.Pow(1.1, 1.5) + 1000/math.Pow(1.1, 1.5)
+	if !almostEqual(res.EnterpriseValue, want) {
+		t.Errorf("EV = %.6f, want %.6f", res.EnterpriseValue, want)
+	}
+}
+
+func TestCalculateDCF_StubPeriod(t *testing.T) {
+	fye := time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)
+	timing := DiscountTiming{FiscalYearEnd: fye, ValuationDate: fye.AddDate(0, 6, 0)}
+	stub := timing.StubFraction()
+	if stub <= 0.49 || stub >= 0.51 {
+		t.Fatalf("expected a ~half-year stub, got %.4f", stub)
+	}
+
+	res := CalculateDCF(DCFInput{
+		Projections:       flatProjections(2, 100, 0),
+		WACC:              0.10,
+		SharesOutstanding: 1,
+		Timing:            timing,
+	})
+	want := 100*stub/math.Pow(1.1, stub) + 100/math.Pow(1.1, 1+stub) + 1000/math.Pow(1.1, 1+stub)
+	if !almostEqual(res.EnterpriseValue, want) {
+		t.Errorf("EV = %.6f, want %.6f", res.EnterpriseValue, want)
+	}
+}
+
+func TestDCFYearMetrics_CashTaxes(t *testing.T) {
+	proj := flatProjections(1, 100, 0)[0]
+	proj.IncomeStatement.NonOperatingSection = &edgar.NonOperatingSection{InterestExpense: fsap(-20)}
+
+	// Flat statutory rate without a tax roll: add back the after-tax interest expense
+	statutory, _ := dcfYearMetrics(proj, 0.25)
+	if !almostEqual(statutory, 100+20*0.75) {
+		t.Errorf("statutory UFCF = %.4f, want 115", statutory)
+	}
+
+	// A tax roll without NOLs or deferred taxes saves exactly the statutory shield,
+	// so both branches must agree
+	proj.Tax = &projection.TaxRoll{CashTax: 20, UnleveredCashTax: 25}
+	if ufcf, _ := dcfYearMetrics(proj, 0.25); !almostEqual(ufcf, statutory) {
+		t.Errorf("tax-roll UFCF = %.4f, want the statutory %.4f", ufcf, statutory)
+	}
+
+	// An NOL shelters all cash tax on the levered income but not all of it unlevered
+	proj.Tax = &projection.TaxRoll{CashTax: 0, UnleveredCashTax: 5}
+	if ufcf, _ := dcfYearMetrics(proj, 0.25); !almostEqual(ufcf, 115) {
+		t.Errorf("cash-tax UFCF = %.4f, want 115", ufcf)
+	}
+}
+
+func TestFiscalYearEndDate(t *testing.T) {
+	cases := []struct {
+		meta edgar.FilingMetadata
+		want string
+	}{
+		{edgar.FilingMetadata{FiscalYear: 2024, ReportDate: "2024-09-28", FiscalYearEnd: "0930"}, "2024-09-28"},
+		{edgar.FilingMetadata{FiscalYear: 2024, FiscalYearEnd: "0630"}, "2024-06-30"},
+		{edgar.FilingMetadata{FiscalYear: 2024}, "2024-12-31"},
+	}
+	for _, c := range cases {
+		got, err := FiscalYearEndDate(&c.meta)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Format(time.DateOnly) != c.want {
+			t.Errorf("got %s, want %s", got.Format(time.DateOnly), c.want)
+		}
+	}
+
+	if _, err := NewDiscountTiming(&edgar.FilingMetadata{FiscalYear: 2024}, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), false); err == nil {
+		t.Error("expected error for valuation date before fiscal year end")
+	}
+	if _, err := NewDiscountTiming(&edgar.FilingMetadata{FiscalYear: 2024}, time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC), false); err == nil {
+		t.Error("expected error for valuation date more than a year after fiscal year end")
+	}
+	timing, err := NewDiscountTiming(&edgar.FilingMetadata{FiscalYear: 2024}, time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), false)
+	if err != nil || timing.StubFraction() != 0 {
+		t.Errorf("a valuation at the next fiscal year end should leave a zero stub: %.4f, %v", timing.StubFraction(), err)
+	}
+}
+
+func TestCalculateDCF_StaleValuationDateWarns(t *testing.T) {
+	fye := time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)
+	res := CalculateDCF(DCFInput{
+		Projections:       flatProjections(2, 100, 0),
+		WACC:              0.10,
+		SharesOutstanding: 1,
+		Timing:            DiscountTiming{FiscalYearEnd: fye, ValuationDate: fye.AddDate(1, 3, 0)},
+	})
+	if len(res.Warnings) == 0 || !strings.Contains(res.Warnings[0], "more than a year after") {
+		t.Errorf("expected a stale valuation date warning, got %v", res.Warnings)
+	}
+}
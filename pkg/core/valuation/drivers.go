@@ -0,0 +1,82 @@
+package valuation
+
+import (
+	"agentic_valuation/pkg/core/projection"
+	"fmt"
+)
+
+// GetDriver reads a valuation-level driver (rates and capital structure that are not projected).
+// "net_debt" is the bridge total when a Bridge is set.
+// Returns false if the name does not address MasterValuationInput
+func (m *MasterValuationInput) GetDriver(name string) (float64, bool) {
+	switch name {
+	case "wacc":
+		if len(m.PeriodWACCs) > 0 {
+			return m.PeriodWACCs[0], true
+		}
+		return m.WACC, true
+	case "cost_of_equity":
+		return m.CostOfEquity, true
+	case "terminal_growth":
+		return m.TerminalGrowth, true
+	case "tax_rate":
+		return m.TaxRate, true
+	case "shares_outstanding":
+		return m.SharesOutstanding, true
+	case "net_debt":
+		return m.bridge().Total(), true
+	}
+	return 0, false
+}
+
+// SetDriver writes a valuation-level driver. Setting "wacc" replaces any PeriodWACCs
+// with a flat rate so the flexed value is actually used for discounting, and setting
+// "net_debt" moves a copy of the Bridge (which overrides NetDebt) to the new total.
+// Returns false if the name does not address MasterValuationInput
+func (m *MasterValuationInput) SetDriver(name string, value float64) bool {
+	switch name {
+	case "wacc":
+		m.WACC = value
+		m.PeriodWACCs = nil
+	case "cost_of_equity":
+		m.CostOfEquity = value
+	case "terminal_growth":
+		m.TerminalGrowth = value
+	case "tax_rate":
+		m.TaxRate = value
+	case "shares_outstanding":
+		m.SharesOutstanding = value
+	case "net_debt":
+		m.NetDebt = value
+		if m.Bridge != nil {
+			m.Bridge = m.Bridge.WithTotal(value)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// ApplyDriver sets a named driver wherever it lives: on the projection assumptions,
+// on the valuation input, or both (e.g. tax_rate drives both NOPAT and the DCF interest shield)
+func ApplyDriver(assumptions *projection.ProjectionAssumptions, input *MasterValuationInput, name string, value float64) error {
+	applied := input.SetDriver(name, value)
+	if projection.IsDriverName(name) {
+		if err := assumptions.SetDriver(name, value); err != nil {
+			return err
+		}
+		applied = true
+	}
+	if !applied {
+		return fmt.Errorf("unknown driver '%s'", name)
+	}
+	return nil
+}
+
+// LookupDriver reads a named driver, preferring the valuation input for shared names
+func LookupDriver(assumptions *projection.ProjectionAssumptions, input *MasterValuationInput, name string) (float64, error) {
+	if v, ok := input.GetDriver(name); ok {
+		return v, nil
+	}
+	return assumptions.GetDriver(name)
+}
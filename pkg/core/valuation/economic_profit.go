@@ -0,0 +1,124 @@
+package valuation
+
+import (
+	"agentic_valuation/pkg/core/projection"
+	"fmt"
+)
+
+// EconomicProfitInput values the firm as invested capital plus the present value
+// of economic profit. NOPAT and invested capital follow calc.ROCEDecomposition:
+// NOPAT = EBIT × (1 − t) and NOA = common equity + net debt (debt − cash).
+type EconomicProfitInput struct {
+	Projections            []*projection.ProjectedFinancials
+	WACC                   float64
+	PeriodWACCs            []float64 // Optional: WACC per projection year
+	TaxRate                float64
+	TerminalGrowth         float64
+	OpeningInvestedCapital float64 // NOA at the valuation date
+	SharesOutstanding      float64
+	NetDebt                float64       // Ignored when Bridge is set
+	Bridge                 *EquityBridge // Optional: itemized EV-to-equity adjustments
+	Timing                 DiscountTiming
+	Terminal               TerminalValueConfig
+}
+
+// EconomicProfitYear is one year of the EVA build-up
+type EconomicProfitYear struct {
+	Year            int
+	NOPAT           float64
+	InvestedCapital float64 // Opening NOA the capital charge is levied on
+	ROIC            float64 // NOPAT / opening NOA
+	WACC            float64
+	EconomicProfit  float64 // NOA × (ROIC − WACC)
+}
+
+// EconomicProfitResult holds the EVA valuation
+type EconomicProfitResult struct {
+	Years              []EconomicProfitYear
+	PV_EconomicProfit  float64
+	PV_ContinuingValue float64 // PV of economic profit beyond the horizon
+	EnterpriseValue    float64 // Opening NOA + PV of economic profit
+	EquityValue        float64
+	SharePrice         float64
+	NetDebt            float64
+	Warnings           []string
+}
+
+// CalculateEconomicProfit performs an economic profit (EVA) valuation.
+// With clean-surplus projections (UFCF = NOPAT − ΔNOA) and steady-state growth
+// in the final year it reconciles to the FCFF DCF under end-of-year discounting.
+func CalculateEconomicProfit(input EconomicProfitInput) EconomicProfitResult {
+	var res EconomicProfitResult
+	var terminal terminalBase
+
+	disc := newDiscounter(input.Timing)
+	ic := input.OpeningInvestedCapital
+	wacc := input.WACC
+	for i, proj := range input.Projections {
+		_, base := dcfYearMetrics(proj, input.TaxRate)
+
+		if len(input.PeriodWACCs) > i {
+			wacc = input.PeriodWACCs[i]
+		}
+		year := EconomicProfitYear{
+			Year:            proj.Year,
+			NOPAT:           base.NOPAT,
+			InvestedCapital: ic,
+			WACC:            wacc,
+			EconomicProfit:  base.NOPAT - wacc*ic,
+		}
+		if ic != 0 {
+			year.ROIC = base.NOPAT / ic
+		}
+		res.Years = append(res.Years, year)
+
+		fraction, factor := disc.next(wacc)
+		res.PV_EconomicProfit += year.EconomicProfit * fraction * factor
+
+		ic = projectedNOA(proj)
+		if i == len(input.Projections)-1 {
+			terminal = base
+		}
+	}
+
+	// Continuing value: for growth methods the next year's economic profit as a
+	// growing perpetuity; exit multiples imply the premium of TV over closing NOA
+	if len(input.Projections) > 0 {
+		tv, perpetuity, err := terminalValue(input.Terminal, terminal, wacc, input.TerminalGrowth)
+		switch {
+		case err != nil:
+			res.Warnings = append(res.Warnings, fmt.Sprintf("continuing value set to zero: %v", err))
+		case perpetuity && input.Terminal.Method != TerminalValueDriver:
+			nextEP := terminal.NOPAT*(1+input.TerminalGrowth) - wacc*ic
+			res.PV_ContinuingValue = nextEP / (wacc - input.TerminalGrowth) * disc.terminal(wacc)
+		case perpetuity:
+			res.PV_ContinuingValue = (tv - ic) * disc.terminal(wacc)
+		default:
+			res.PV_ContinuingValue = (tv - ic) * disc.horizon()
+		}
+	}
+
+	res.EnterpriseValue = input.OpeningInvestedCapital + res.PV_EconomicProfit + res.PV_ContinuingValue
+
+	bridge := input.Bridge
+	if bridge == nil {
+		bridge = NewNetDebtBridge(input.NetDebt)
+	}
+	res.NetDebt = bridge.Total()
+	res.EquityValue = bridge.EquityValue(res.EnterpriseValue)
+	if input.SharesOutstanding != 0 {
+		res.SharePrice = res.EquityValue / input.SharesOutstanding
+	}
+	return res
+}
+
+// projectedNOA applies the ROCE identity NOA = equity + (debt − cash) to a projected balance sheet
+func projectedNOA(proj *projection.ProjectedFinancials) float64 {
+	if proj.BalanceSheet == nil {
+		return 0
+	}
+	eq := proj.BalanceSheet.Equity
+	equity := getValSafe(eq.CommonStockAPIC) + getValSafe(eq.RetainedEarningsDeficit) +
+		getValSafe(eq.AccumOtherComprehensiveIncome) + getValSafe(eq.TreasuryStock)
+	return equity + projectedDebt(proj) - getValSafe(proj.BalanceSheet.CurrentAssets.CashAndEquivalents)
+}
@@ -0,0 +1,77 @@
+package valuation
+
+import (
+	"math"
+	"testing"
+
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/projection"
+)
+
+// steadyStateProjections grows NOPAT and NOA at g with clean surplus:
+// UFCF = NOPAT - ΔNOA, NOPAT_1 = 100, NOA_0 = 500
+func steadyStateProjections(n int, g, tax float64) []*projection.ProjectedFinancials {
+	out := make([]*projection.ProjectedFinancials, n)
+	for i := range out {
+		growth := math.Pow(1+g, float64(i))
+		nopat := 100 * growth
+		noa := 500 * growth * (1 + g)
+		out[i] = &projection.ProjectedFinancials{
+			Year: 2025 + i,
+			IncomeStatement: &edgar.IncomeStatement{
+				OperatingCostSection: &edgar.OperatingCostSection{OperatingIncome: fsap(nopat / (1 - tax))},
+			},
+			BalanceSheet: &edgar.BalanceSheet{Equity: edgar.Equity{CommonStockAPIC: fsap(noa)}},
+			CashFlow: &edgar.CashFlowStatement{
+				CashSummary: &edgar.CashSummarySection{NetCashOperating: fsap(nopat - 500*growth*g)},
+			},
+		}
+	}
+	return out
+}
+
+func TestCalculateEconomicProfit_ReconcilesToFCFF(t *testing.T) {
+	const g, tax, wacc = 0.02, 0.25, 0.09
+	projections := steadyStateProjections(4, g, tax)
+
+	ep := CalculateEconomicProfit(EconomicProfitInput{
+		Projections:            projections,
+		WACC:                   wacc,
+		TaxRate:                tax,
+		TerminalGrowth:         g,
+		OpeningInvestedCapital: 500,
+		SharesOutstanding:      1,
+	})
+	dcf := CalculateDCF(DCFInput{Projections: projections, WACC: wacc, TerminalGrowth: g, TaxRate: tax, SharesOutstanding: 1})
+
+	if !almostEqual(ep.EnterpriseValue, dcf.EnterpriseValue) {
+		t.Errorf("EVA EV %.6f, FCFF EV %.6f", ep.EnterpriseValue, dcf.EnterpriseValue)
+	}
+
+	first := ep.Years[0]
+	if !almostEqual(first.ROIC, 0.2) || !almostEqual(first.EconomicProfit, 500*(0.2-wacc)) {
+		t.Errorf("year 1: ROIC %.4f, EP %.4f", first.ROIC, first.EconomicProfit)
+	}
+	if len(ep.Warnings) != 0 {
+		t.Errorf("unexpected warnings %v", ep.Warnings)
+	}
+}
+
+func TestEconomicProfitInput_InvestedCapitalMatchesNOA(t *testing.T) {
+	bridge := &EquityBridge{}
+	bridge.Add("long_term_debt", "Long-term debt", 300)
+	bridge.Add("cash_and_equivalents", "Cash", -80)
+	bridge.Add("pension_obligations", "Pensions", 50)
+	bridge.Add("long_term_investments", "Investments", -40)
+
+	in := MasterValuationInput{CurrentBookValue: 500, Bridge: bridge}.EconomicProfitInput()
+	if !almostEqual(in.OpeningInvestedCapital, 720) {
+		t.Errorf("opening NOA %.2f, want equity + debt - cash = 720", in.OpeningInvestedCapital)
+	}
+
+	// A legacy net cash figure reduces NOA like cash on the balance sheet
+	in = MasterValuationInput{CurrentBookValue: 500, NetDebt: -100}.EconomicProfitInput()
+	if !almostEqual(in.OpeningInvestedCapital, 400) {
+		t.Errorf("opening NOA %.2f with net cash, want 400", in.OpeningInvestedCapital)
+	}
+}
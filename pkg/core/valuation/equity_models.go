@@ -12,7 +12,12 @@ type EquityModelInput struct {
 	CostOfEquity      float64 // Ke
 	TerminalGrowth    float64 // g
 	SharesOutstanding float64
-	CurrentBookValue  float64 // B_0 (Initial Book Value of Equity)
+	CurrentBookValue  float64        // B_0 (Initial Book Value of Equity)
+	Timing            DiscountTiming // Valuation date, stub period and mid-year convention
+
+	// Share count by projection year (nil = SharesOutstanding throughout). When set,
+	// DDM and RIM discount per-share dividends and residual income.
+	Shares []projection.ShareRoll
 }
 
 // EquityValuationResult holds the valuation outputs for a specific model
@@ -36,31 +41,18 @@ func getValSafe(v *edgar.FSAPValue) float64 {
 // 1. Dividend Discount Model (Dividend Based Valuation)
 // Value = Sum(PV of Divs) + PV(Terminal Price based on Div growth)
 func CalculateDDM(input EquityModelInput) EquityValuationResult {
+	if input.perShare() {
+		return calculateDDMPerShare(input)
+	}
 	var pvDivs float64
 	var lastDiv float64
+	disc := newDiscounter(input.Timing)
 
 	for i, proj := range input.Projections {
-		// Extract Dividends
-		div := 0.0
-		if proj.CashFlow != nil && proj.CashFlow.FinancingActivities != nil {
-			// DividendsPaid is usually negative inflow (outflow)
-			val := getValSafe(proj.CashFlow.FinancingActivities.DividendsPaid)
-			if val < 0 {
-				div = -val
-			} else {
-				div = val
-			}
-		} else if proj.CashFlow != nil && proj.CashFlow.Dividends != nil {
-			val := getValSafe(proj.CashFlow.Dividends)
-			if val < 0 {
-				div = -val
-			} else {
-				div = val
-			}
-		}
+		div := projectedDividends(proj)
 
-		discountFactor := 1.0 / math.Pow(1.0+input.CostOfEquity, float64(i+1))
-		pvDivs += div * discountFactor
+		fraction, discountFactor := disc.next(input.CostOfEquity)
+		pvDivs += div * fraction * discountFactor
 
 		if i == len(input.Projections)-1 {
 			lastDiv = div
@@ -73,7 +65,7 @@ func CalculateDDM(input EquityModelInput) EquityValuationResult {
 		terminalVal = lastDiv * (1 + input.TerminalGrowth) / (input.CostOfEquity - input.TerminalGrowth)
 	}
 
-	pvTerminal := terminalVal / math.Pow(1.0+input.CostOfEquity, float64(len(input.Projections)))
+	pvTerminal := terminalVal * disc.terminal(input.CostOfEquity)
 
 	totalEquityVal := pvDivs + pvTerminal
 	sharePrice := totalEquityVal / input.SharesOutstanding
@@ -88,15 +80,49 @@ func CalculateDDM(input EquityModelInput) EquityValuationResult {
 	}
 }
 
+// calculateDDMPerShare discounts dividends per weighted-average share, so buybacks
+// that shrink the count raise the dividend each remaining share receives
+func calculateDDMPerShare(input EquityModelInput) EquityValuationResult {
+	var pvDPS, lastDPS float64
+	disc := newDiscounter(input.Timing)
+	for i, proj := range input.Projections {
+		dps := projectedDividends(proj) / input.Shares[i].WeightedAverage
+		fraction, discountFactor := disc.next(input.CostOfEquity)
+		pvDPS += dps * fraction * discountFactor
+		lastDPS = dps
+	}
+
+	terminalDPS := 0.0
+	if input.CostOfEquity > input.TerminalGrowth {
+		terminalDPS = lastDPS * (1 + input.TerminalGrowth) / (input.CostOfEquity - input.TerminalGrowth)
+	}
+	pvTerminalDPS := terminalDPS * disc.terminal(input.CostOfEquity)
+
+	shares := input.currentShares()
+	sharePrice := pvDPS + pvTerminalDPS
+	return EquityValuationResult{
+		ModelName:     "Dividend Based Valuation",
+		EquityValue:   sharePrice * shares,
+		SharePrice:    sharePrice,
+		PV_Stream:     pvDPS * shares,
+		PV_Terminal:   pvTerminalDPS * shares,
+		TerminalValue: terminalDPS * input.Shares[len(input.Shares)-1].Closing,
+	}
+}
+
 // 2. Residual Income Valuation (RIM)
 // Value = BookValue_0 + Sum(PV of Residual Income) + PV(Terminal RI)
 // RI_t = NetIncome_t - (Ke * BookValue_{t-1})
 func CalculateResidualIncome(input EquityModelInput) EquityValuationResult {
+	if input.perShare() {
+		return calculateResidualIncomePerShare(input)
+	}
 	var pvRI float64
 	prevBookValue := input.CurrentBookValue
 	var lastRI float64
+	disc := newDiscounter(input.Timing)
 
-	for i, proj := range input.Projections {
+	for _, proj := range input.Projections {
 		// Extract Net Income
 		ni := 0.0
 		if proj.IncomeStatement != nil {
@@ -115,9 +141,9 @@ func CalculateResidualIncome(input EquityModelInput) EquityValuationResult {
 		ri := ni - capitalCharge
 		lastRI = ri
 
-		// Discount
-		discountFactor := 1.0 / math.Pow(1.0+input.CostOfEquity, float64(i+1))
-		pvRI += ri * discountFactor
+		// Discount (stub year counts only the portion after the valuation date)
+		fraction, discountFactor := disc.next(input.CostOfEquity)
+		pvRI += ri * fraction * discountFactor
 
 		// Update Book Value: B_t = B_{t-1} + NI - Div
 		div := 0.0
@@ -139,7 +165,7 @@ func CalculateResidualIncome(input EquityModelInput) EquityValuationResult {
 		terminalRIVal = lastRI * (1 + input.TerminalGrowth) / (input.CostOfEquity - input.TerminalGrowth)
 	}
 
-	pvTerminalRI := terminalRIVal / math.Pow(1.0+input.CostOfEquity, float64(len(input.Projections)))
+	pvTerminalRI := terminalRIVal * disc.terminal(input.CostOfEquity)
 
 	totalEquityVal := input.CurrentBookValue + pvRI + pvTerminalRI
 	sharePrice := totalEquityVal / input.SharesOutstanding
@@ -154,13 +180,99 @@ func CalculateResidualIncome(input EquityModelInput) EquityValuationResult {
 	}
 }
 
+// calculateResidualIncomePerShare runs RIM on per-share figures: EPS on the
+// weighted-average count against the cost of equity on opening book value per
+// share, with book value rolled as the engine rolls equity (clean surplus): net
+// income less dividends, plus SBC credited to APIC and option exercises, less buybacks
+func calculateResidualIncomePerShare(input EquityModelInput) EquityValuationResult {
+	bookValue := input.CurrentBookValue
+	openingBVPS := bookValue / input.Shares[0].Opening
+	prevBVPS := openingBVPS
+	var pvRIPS, lastRIPS float64
+	disc := newDiscounter(input.Timing)
+
+	for i, proj := range input.Projections {
+		roll := input.Shares[i]
+		ni := 0.0
+		if proj.IncomeStatement != nil && proj.IncomeStatement.NetIncomeSection != nil {
+			ni = getValSafe(proj.IncomeStatement.NetIncomeSection.NetIncomeToCommon)
+		}
+		rips := ni/roll.WeightedAverage - input.CostOfEquity*prevBVPS
+		fraction, discountFactor := disc.next(input.CostOfEquity)
+		pvRIPS += rips * fraction * discountFactor
+		lastRIPS = rips
+
+		bookValue += ni - projectedDividends(proj) + projectedSBC(proj) - roll.BuybackSpend + roll.ExerciseProceeds
+		prevBVPS = bookValue / roll.Closing
+	}
+
+	terminalRIPS := 0.0
+	if input.CostOfEquity > input.TerminalGrowth {
+		terminalRIPS = lastRIPS * (1 + input.TerminalGrowth) / (input.CostOfEquity - input.TerminalGrowth)
+	}
+	pvTerminalRIPS := terminalRIPS * disc.terminal(input.CostOfEquity)
+
+	shares := input.currentShares()
+	sharePrice := openingBVPS + pvRIPS + pvTerminalRIPS
+	return EquityValuationResult{
+		ModelName:     "Residual Income Valuation",
+		EquityValue:   sharePrice * shares,
+		SharePrice:    sharePrice,
+		PV_Stream:     pvRIPS * shares,
+		PV_Terminal:   pvTerminalRIPS * shares,
+		TerminalValue: terminalRIPS * input.Shares[len(input.Shares)-1].Closing,
+	}
+}
+
+// perShare reports whether every projection year has a usable share count
+func (input EquityModelInput) perShare() bool {
+	if len(input.Shares) == 0 || len(input.Shares) < len(input.Projections) {
+		return false
+	}
+	for _, s := range input.Shares[:len(input.Projections)] {
+		if s.WeightedAverage <= 0 || s.Closing <= 0 || s.Opening <= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// currentShares is today's share count: SharesOutstanding, else the first year's opening count
+func (input EquityModelInput) currentShares() float64 {
+	if input.SharesOutstanding > 0 {
+		return input.SharesOutstanding
+	}
+	return input.Shares[0].Opening
+}
+
+// projectedDividends is a projection year's dividends as a positive amount
+func projectedDividends(proj *projection.ProjectedFinancials) float64 {
+	if proj.CashFlow == nil {
+		return 0
+	}
+	if proj.CashFlow.FinancingActivities != nil {
+		// DividendsPaid is usually negative inflow (outflow)
+		return math.Abs(getValSafe(proj.CashFlow.FinancingActivities.DividendsPaid))
+	}
+	return math.Abs(getValSafe(proj.CashFlow.Dividends))
+}
+
+// projectedSBC is the year's stock-based compensation, credited to APIC
+func projectedSBC(proj *projection.ProjectedFinancials) float64 {
+	if proj.CashFlow == nil || proj.CashFlow.OperatingActivities == nil {
+		return 0
+	}
+	return getValSafe(proj.CashFlow.OperatingActivities.StockBasedCompensation)
+}
+
 // 3. Free Cash Flow to Equity (FCFE) Valuation
 // FCFE = CFO - CapEx + NetBorrowing
 func CalculateFCFE(input EquityModelInput) EquityValuationResult {
 	var pvFCFE float64
 	var lastFCFE float64
+	disc := newDiscounter(input.Timing)
 
-	for i, proj := range input.Projections {
+	for _, proj := range input.Projections {
 		// CFO
 		cfo := 0.0
 		if proj.CashFlow != nil {
@@ -194,8 +306,8 @@ func CalculateFCFE(input EquityModelInput) EquityValuationResult {
 		fcfe := cfo + capex + netBorrowing
 		lastFCFE = fcfe
 
-		discountFactor := 1.0 / math.Pow(1.0+input.CostOfEquity, float64(i+1))
-		pvFCFE += fcfe * discountFactor
+		fraction, discountFactor := disc.next(input.CostOfEquity)
+		pvFCFE += fcfe * fraction * discountFactor
 	}
 
 	// Terminal Value
@@ -204,7 +316,7 @@ func CalculateFCFE(input EquityModelInput) EquityValuationResult {
 		terminalVal = lastFCFE * (1 + input.TerminalGrowth) / (input.CostOfEquity - input.TerminalGrowth)
 	}
 
-	pvTerminal := terminalVal / math.Pow(1.0+input.CostOfEquity, float64(len(input.Projections)))
+	pvTerminal := terminalVal * disc.terminal(input.CostOfEquity)
 
 	totalEquityVal := pvFCFE + pvTerminal
 	sharePrice := totalEquityVal / input.SharesOutstanding
@@ -0,0 +1,98 @@
+package valuation
+
+import (
+	"testing"
+
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/projection"
+)
+
+// shareRolls is n years of a constant count, or one shrinking by retire shares a year
+func shareRolls(n int, opening, retire float64) []projection.ShareRoll {
+	out := make([]projection.ShareRoll, n)
+	for i := range out {
+		closing := opening - retire
+		out[i] = projection.ShareRoll{Year: 2025 + i, Opening: opening, Closing: closing, WeightedAverage: (opening + closing) / 2}
+		opening = closing
+	}
+	return out
+}
+
+func TestCalculateDDM_PerShare(t *testing.T) {
+	input := EquityModelInput{
+		Projections:       flatProjections(3, 0, -100),
+		CostOfEquity:      0.10,
+		TerminalGrowth:    0.02,
+		SharesOutstanding: 50,
+	}
+	total := CalculateDDM(input)
+
+	// A constant count reproduces the aggregate model
+	input.Shares = shareRolls(3, 50, 0)
+	flat := CalculateDDM(input)
+	if !almostEqual(flat.SharePrice, total.SharePrice) || !almostEqual(flat.EquityValue, total.EquityValue) {
+		t.Errorf("per-share DDM %.6f, aggregate %.6f", flat.SharePrice, total.SharePrice)
+	}
+
+	// Buybacks concentrate the same dividends on fewer shares
+	input.Shares = shareRolls(3, 50, 2)
+	if shrinking := CalculateDDM(input); shrinking.SharePrice <= flat.SharePrice {
+		t.Errorf("expected buybacks to raise the DDM price: %.4f vs %.4f", shrinking.SharePrice, flat.SharePrice)
+	}
+}
+
+func TestCalculateResidualIncome_PerShare(t *testing.T) {
+	projections := flatProjections(3, 0, -40)
+	for _, p := range projections {
+		p.IncomeStatement.NetIncomeSection = &edgar.NetIncomeSection{NetIncomeToCommon: fsap(120)}
+	}
+	input := EquityModelInput{
+		Projections:       projections,
+		CostOfEquity:      0.10,
+		TerminalGrowth:    0.02,
+		SharesOutstanding: 50,
+		CurrentBookValue:  1000,
+	}
+	total := CalculateResidualIncome(input)
+
+	input.Shares = shareRolls(3, 50, 0)
+	flat := CalculateResidualIncome(input)
+	if !almostEqual(flat.SharePrice, total.SharePrice) {
+		t.Errorf("per-share RIM %.6f, aggregate %.6f", flat.SharePrice, total.SharePrice)
+	}
+
+	// One year with g = 0: EPS 120 / 49 less 10% of opening book value per share (20), held in perpetuity
+	res := CalculateResidualIncome(EquityModelInput{
+		Projections:      projections[:1],
+		CostOfEquity:     0.10,
+		CurrentBookValue: 1000,
+		Shares:           shareRolls(1, 50, 2),
+	})
+	rips := 120.0/49 - 2
+	if want := 20 + rips/1.1 + rips/0.10/1.1; !almostEqual(res.SharePrice, want) {
+		t.Errorf("per-share RIM %.6f, want %.6f", res.SharePrice, want)
+	}
+	if !almostEqual(res.EquityValue, res.SharePrice*50) {
+		t.Errorf("equity value %.4f should be the price on today's 50 shares", res.EquityValue)
+	}
+}
+
+func TestCalculateResidualIncome_PerShareBookIncludesSBC(t *testing.T) {
+	projections := flatProjections(2, 0, -40)
+	for _, p := range projections {
+		p.IncomeStatement.NetIncomeSection = &edgar.NetIncomeSection{NetIncomeToCommon: fsap(120)}
+		p.CashFlow.OperatingActivities = &edgar.CFOperatingSection{StockBasedCompensation: fsap(30)}
+	}
+	res := CalculateResidualIncome(EquityModelInput{
+		Projections:      projections,
+		CostOfEquity:     0.10,
+		CurrentBookValue: 1000,
+		Shares:           shareRolls(2, 50, 0),
+	})
+
+	// Year-2 book value per share is (1000 + 120 - 40 + 30) / 50 = 22.2, so the capital charge is 2.22
+	ri1, ri2 := 120.0/50-2, 120.0/50-2.22
+	if want := 20 + ri1/1.1 + ri2/1.21 + ri2/0.10/1.21; !almostEqual(res.SharePrice, want) {
+		t.Errorf("per-share RIM %.6f, want %.6f", res.SharePrice, want)
+	}
+}
@@ -0,0 +1,7 @@
+package valuation
+
+// Internals reached by the external tests (package valuation_test)
+var (
+	ReversePrice         = reversePrice
+	DebateReverseDrivers = debateReverseDrivers
+)
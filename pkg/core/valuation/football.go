@@ -0,0 +1,145 @@
+package valuation
+
+import (
+	"agentic_valuation/pkg/core/edgar"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Football field bar names used by the constructors below (weights are keyed by Model)
+const (
+	FootballDCF          = "DCF"
+	FootballComps        = "Trading Comps"
+	FootballTransactions = "Precedent Transactions"
+	FootballLBO          = "LBO"
+)
+
+// FootballBar is one model's low / base / high value per share
+type FootballBar struct {
+	Model  string  `json:"model"`
+	Low    float64 `json:"low"`
+	Base   float64 `json:"base"`
+	High   float64 `json:"high"`
+	Weight float64 `json:"weight"` // Normalized weight in the blended target (0 = shown but not blended)
+}
+
+// FootballField aggregates the model ranges into a blended target price
+type FootballField struct {
+	Bars          []FootballBar `json:"bars"`
+	BlendedLow    float64       `json:"blended_low"`
+	BlendedTarget float64       `json:"blended_target"`
+	BlendedHigh   float64       `json:"blended_high"`
+	CurrentPrice  float64       `json:"current_price"`
+	Upside        float64       `json:"upside"` // BlendedTarget / CurrentPrice - 1 (0 without a price)
+}
+
+// RangeBar spans the min and max of values around a base price,
+// e.g. every cell of a WACC x terminal growth sensitivity table
+func RangeBar(model string, base float64, values []float64) FootballBar {
+	bar := FootballBar{Model: model, Low: base, Base: base, High: base}
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		bar.Low = math.Min(bar.Low, v)
+		bar.High = math.Max(bar.High, v)
+	}
+	return bar
+}
+
+// PointBar turns a single model value (e.g. a RunAllValuations line) into a zero-width bar
+func PointBar(item ValuationLineItem) FootballBar {
+	return FootballBar{Model: item.ModelName, Low: item.SharePrice, Base: item.SharePrice, High: item.SharePrice}
+}
+
+// RelativeBar uses the composite 25th-75th percentile price of a comps or
+// precedent transaction analysis, with the midpoint as base
+func RelativeBar(model string, r RelativeValuationResult) FootballBar {
+	lo, hi := r.CompositePrice[0], r.CompositePrice[1]
+	return FootballBar{Model: model, Low: lo, Base: (lo + hi) / 2, High: hi}
+}
+
+// LBOBar converts sponsor entry prices into per-share values. The first run is the
+// base case; the others (e.g. higher and lower target IRRs) set the range.
+func LBOBar(runs []LBOResult, netDebt, shares float64) (FootballBar, error) {
+	if len(runs) == 0 {
+		return FootballBar{}, fmt.Errorf("LBO bar needs at least one run")
+	}
+	if shares <= 0 {
+		return FootballBar{}, fmt.Errorf("LBO bar needs a positive share count")
+	}
+	prices := make([]float64, len(runs))
+	for i, r := range runs {
+		prices[i] = (r.EntryEV - netDebt) / shares
+	}
+	return RangeBar(FootballLBO, prices[0], prices[1:]), nil
+}
+
+// LBOIRRBar solves the sponsor's maximum entry price at the target IRR and at
+// the target ± spread: demanding a higher return sets the low end of the range
+func LBOIRRBar(input LBOInput, spread, netDebt, shares float64) (FootballBar, error) {
+	input.Mode = LBOModeTargetIRR
+	var runs []LBOResult
+	for _, irr := range []float64{input.TargetIRR, input.TargetIRR + spread, input.TargetIRR - spread} {
+		run := input
+		run.TargetIRR = irr
+		res, err := CalculateLBO(run)
+		if err != nil {
+			return FootballBar{}, fmt.Errorf("LBO at %.1f%% IRR: %w", irr*100, err)
+		}
+		runs = append(runs, res)
+	}
+	return LBOBar(runs, netDebt, shares)
+}
+
+// CurrentSharePrice reads the fiscal year-end share price (0 when not reported)
+func CurrentSharePrice(sd edgar.SupplementalData) float64 {
+	if sd.SharePriceYearEnd != nil {
+		return *sd.SharePriceYearEnd
+	}
+	return 0
+}
+
+// BuildFootballField blends the bars into a target price.
+// weights is keyed by bar Model; nil weights every bar equally, and models
+// missing from a non-nil map are shown but excluded from the blend.
+func BuildFootballField(bars []FootballBar, weights map[string]float64, currentPrice float64) (*FootballField, error) {
+	if len(bars) == 0 {
+		return nil, fmt.Errorf("football field needs at least one bar")
+	}
+
+	ff := &FootballField{CurrentPrice: currentPrice}
+	total := 0.0
+	for _, b := range bars {
+		// Keep Low <= Base <= High whatever the source ordering
+		vals := []float64{b.Low, b.Base, b.High}
+		sort.Float64s(vals)
+		b.Low, b.High = vals[0], vals[2]
+
+		b.Weight = 1
+		if weights != nil {
+			b.Weight = weights[b.Model]
+		}
+		if b.Weight < 0 {
+			return nil, fmt.Errorf("negative weight %.4f for model '%s'", b.Weight, b.Model)
+		}
+		total += b.Weight
+		ff.Bars = append(ff.Bars, b)
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("model weights sum to zero")
+	}
+
+	for i := range ff.Bars {
+		b := &ff.Bars[i]
+		b.Weight /= total
+		ff.BlendedLow += b.Weight * b.Low
+		ff.BlendedTarget += b.Weight * b.Base
+		ff.BlendedHigh += b.Weight * b.High
+	}
+	if currentPrice > 0 {
+		ff.Upside = ff.BlendedTarget/currentPrice - 1
+	}
+	return ff, nil
+}
@@ -0,0 +1,82 @@
+package valuation
+
+import (
+	"agentic_valuation/pkg/core/edgar"
+	"testing"
+)
+
+func TestBuildFootballField_WeightedBlendAndUpside(t *testing.T) {
+	bars := []FootballBar{
+		RangeBar(FootballDCF, 50, []float64{40, 45, 55, 70}),
+		PointBar(ValuationLineItem{ModelName: "Residual Income Valuation", SharePrice: 30}),
+		RelativeBar(FootballComps, RelativeValuationResult{CompositePrice: [2]float64{35, 45}}),
+	}
+	if bars[0].Low != 40 || bars[0].High != 70 {
+		t.Fatalf("DCF range = [%.2f, %.2f], want [40, 70]", bars[0].Low, bars[0].High)
+	}
+
+	price := 40.0
+	sd := edgar.SupplementalData{SharePriceYearEnd: &price}
+
+	ff, err := BuildFootballField(bars, map[string]float64{FootballDCF: 2, FootballComps: 2}, CurrentSharePrice(sd))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// RIM has no weight: shown, not blended. Target = (50 + 40) / 2
+	if len(ff.Bars) != 3 || ff.Bars[1].Weight != 0 {
+		t.Fatalf("expected RIM bar kept with zero weight, got %+v", ff.Bars)
+	}
+	if !almostEqual(ff.BlendedTarget, 45) || !almostEqual(ff.BlendedLow, 37.5) || !almostEqual(ff.BlendedHigh, 57.5) {
+		t.Errorf("blend = %.2f / %.2f / %.2f, want 37.5 / 45 / 57.5", ff.BlendedLow, ff.BlendedTarget, ff.BlendedHigh)
+	}
+	if !almostEqual(ff.Upside, 0.125) {
+		t.Errorf("upside = %.4f, want 0.125", ff.Upside)
+	}
+
+	equal, err := BuildFootballField(bars, nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !almostEqual(equal.BlendedTarget, 40) || equal.Upside != 0 {
+		t.Errorf("equal-weight target = %.2f (upside %.2f), want 40 and no upside without a price", equal.BlendedTarget, equal.Upside)
+	}
+
+	if _, err := BuildFootballField(bars, map[string]float64{"Unknown": 1}, 0); err == nil {
+		t.Error("expected error when no bar carries weight")
+	}
+}
+
+func TestLBOBar_UsesRunsForRange(t *testing.T) {
+	runs := []LBOResult{{EntryEV: 1000}, {EntryEV: 800}, {EntryEV: 1300}}
+	bar, err := LBOBar(runs, 200, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bar.Base != 80 || bar.Low != 60 || bar.High != 110 {
+		t.Errorf("LBO bar = %+v, want 60 / 80 / 110", bar)
+	}
+	if _, err := LBOBar(runs, 200, 0); err == nil {
+		t.Error("expected error for zero shares")
+	}
+}
+
+func TestLBOIRRBar_HigherIRRSetsLow(t *testing.T) {
+	input := lboInput()
+	input.TargetIRR = 0.20
+	bar, err := LBOIRRBar(input, 0.05, 200, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	input.Mode = LBOModeTargetIRR
+	base, err := CalculateLBO(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !almostEqual(bar.Base, (base.EntryEV-200)/10) {
+		t.Errorf("base %.4f, want the price at the target IRR %.4f", bar.Base, (base.EntryEV-200)/10)
+	}
+	if !(bar.Low < bar.Base && bar.Base < bar.High) {
+		t.Errorf("LBO bar = %+v, want low < base < high", bar)
+	}
+}
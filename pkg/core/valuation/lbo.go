@@ -1,98 +1,370 @@
 package valuation
 
 import (
+	"agentic_valuation/pkg/core/projection"
+	"fmt"
 	"math"
+	"sort"
 )
 
-// LBOInput parameters for Ability-To-Pay analysis
+// TrancheType classifies a layer of the acquisition debt
+type TrancheType string
+
+const (
+	TrancheRevolver    TrancheType = "revolver"     // Drawn to fund deficits, repaid first
+	TrancheTermLoan    TrancheType = "term_loan"    // TLA/TLB: amortizing, prepayable
+	TrancheSeniorNotes TrancheType = "senior_notes" // Bullet, usually not prepayable
+	TranchePIK         TrancheType = "pik"          // Interest accrues to principal
+)
+
+// DebtTranche is one layer of the capital structure
+type DebtTranche struct {
+	Name          string      `json:"name"`
+	Type          TrancheType `json:"type"`
+	Multiple      float64     `json:"multiple"`       // x entry EBITDA (used when Amount is 0)
+	Amount        float64     `json:"amount"`         // Funded at close
+	InterestRate  float64     `json:"interest_rate"`  // Cash rate (accrued rate for PIK)
+	Amortization  float64     `json:"amortization"`   // Mandatory repayment per year, % of original principal
+	SweepPriority int         `json:"sweep_priority"` // Optional prepayment order (1 = first, 0 = not swept)
+	Commitment    float64     `json:"commitment"`     // Revolver capacity
+	FeePercent    float64     `json:"fee_percent"`    // Financing fee, % of funded amount (or commitment for revolvers)
+}
+
+// LBOMode selects what the model solves for
+type LBOMode string
+
+const (
+	LBOModeTargetIRR     LBOMode = "target_irr"     // Max entry price that still earns TargetIRR
+	LBOModeEntryMultiple LBOMode = "entry_multiple" // IRR/MOIC at a given EntryMultiple
+)
+
+// LBOYear holds the operating forecast for one holding year
+type LBOYear struct {
+	Year      int     `json:"year"`
+	EBITDA    float64 `json:"ebitda"`
+	DandA     float64 `json:"d_and_a"`    // Tax-deductible depreciation & amortization
+	Capex     float64 `json:"capex"`      // Positive = cash outflow
+	ChangeNWC float64 `json:"change_nwc"` // Positive = investment in working capital
+}
+
+// LBOInput parameters for the sponsor model
 type LBOInput struct {
-	TargetEBITDA       float64
-	LeverageRatio      float64 // Debt / EBITDA (e.g. 5.0x)
-	InterestRate       float64 // Cost of Debt
-	TaxRate            float64
-	EntryMultiple      float64 // If 0, we solve for it based on TargetIRR? No, LBO usually outputs IRR or Price.
-	ExitMultiple       float64
-	HoldingPeriod      int       // Years (e.g. 5)
-	ProjectedEBITDA    []float64 // Stream of EBITDA for 5 years
-	ProjectedCapex     []float64
-	ProjectedChangeNWC []float64
-	TargetIRR          float64 // e.g. 0.20
+	Mode          LBOMode // "" = LBOModeTargetIRR, the ability-to-pay solve of earlier versions
+	TargetEBITDA  float64 // Entry (LTM) EBITDA
+	EntryMultiple float64 // Required for LBOModeEntryMultiple
+	ExitMultiple  float64
+	TargetIRR     float64 // Required for LBOModeTargetIRR, e.g. 0.20
+	HoldingPeriod int     // Years (e.g. 5)
+	TaxRate       float64
+	Years         []LBOYear // At least HoldingPeriod years (see LBOYearsFromProjections)
+
+	Tranches          []DebtTranche
+	TransactionFeePct float64 // Advisory/transaction fees, % of entry EV
+	MinimumCash       float64 // Funded at close and kept on balance sheet
+	SweepPercent      float64 // Share of excess cash used for optional prepayment (0 = 100%)
+
+	// Legacy single-tranche inputs, used when Tranches is empty
+	LeverageRatio float64 // Debt / EBITDA (e.g. 5.0x)
+	InterestRate  float64 // Cost of Debt
+}
+
+// TrancheYear is one tranche's roll-forward for one year
+type TrancheYear struct {
+	Name         string  `json:"name"`
+	Beginning    float64 `json:"beginning"`
+	CashInterest float64 `json:"cash_interest"`
+	PIKInterest  float64 `json:"pik_interest"`
+	Drawn        float64 `json:"drawn"`
+	Mandatory    float64 `json:"mandatory"`
+	Optional     float64 `json:"optional"`
+	Ending       float64 `json:"ending"`
+}
+
+// LBOScheduleYear is one row of the debt schedule
+type LBOScheduleYear struct {
+	Year         int           `json:"year"`
+	EBITDA       float64       `json:"ebitda"`
+	CashInterest float64       `json:"cash_interest"`
+	PIKInterest  float64       `json:"pik_interest"`
+	Taxes        float64       `json:"taxes"`
+	Capex        float64       `json:"capex"`
+	ChangeNWC    float64       `json:"change_nwc"`
+	CFADS        float64       `json:"cfads"` // Cash available for debt service after interest
+	Tranches     []TrancheYear `json:"tranches"`
+	TotalDebt    float64       `json:"total_debt"`
+	Cash         float64       `json:"cash"`
+	Leverage     float64       `json:"leverage"` // Total debt / EBITDA
 }
 
 // LBOResult
 type LBOResult struct {
-	MaxEntryEV           float64
+	Mode                 LBOMode
+	EntryEV              float64
+	MaxEntryEV           float64 // LBOModeTargetIRR only
 	ImpliedEntryMultiple float64
 	EquityCheck          float64
 	DebtRaised           float64
+	TransactionFees      float64
+	FinancingFees        float64
+	ExitEV               float64
+	ExitNetDebt          float64
 	ExitEquityValue      float64
-	ArchiveIRR           float64 // If entry multiple is fixed
+	ArchiveIRR           float64 // Sponsor IRR (equals TargetIRR when solving for price)
+	MOIC                 float64
+	Schedule             []LBOScheduleYear
 }
 
-// CalculateLBO determines the specific price a sponsor can pay to achieve TargetIRR
-func CalculateLBO(input LBOInput) LBOResult {
-	// 1. Calculate Debt Calculation
-	initialDebt := input.TargetEBITDA * input.LeverageRatio
+// CalculateLBO runs the sponsor model: sources & uses, a yearly debt schedule with
+// mandatory amortization and a prioritized cash sweep, then either the maximum
+// entry price for TargetIRR or the IRR/MOIC at EntryMultiple.
+// Debt is sized off entry EBITDA, so the schedule does not depend on the price paid.
+func CalculateLBO(input LBOInput) (LBOResult, error) {
+	if input.Mode == "" {
+		input.Mode = LBOModeTargetIRR
+	}
+	if err := validateLBOInput(input); err != nil {
+		return LBOResult{}, err
+	}
 
-	// 2. Build Cash Flow Waterfall to find Exit Equity
-	// Assume clean waterfall: FCF pays down debt.
-	currentDebt := initialDebt
+	tranches := input.Tranches
+	if len(tranches) == 0 {
+		tranches = []DebtTranche{{Name: "Term Loan", Type: TrancheTermLoan, Multiple: input.LeverageRatio, InterestRate: input.InterestRate, SweepPriority: 1}}
+	}
 
-	for i := 0; i < input.HoldingPeriod; i++ {
-		ebitda := input.ProjectedEBITDA[i]
-		capex := input.ProjectedCapex[i]
-		nwc := input.ProjectedChangeNWC[i]
+	// 1. Sources: funded debt and financing fees
+	balances := make([]float64, len(tranches))
+	original := make([]float64, len(tranches))
+	var debtRaised, financingFees float64
+	for i, t := range tranches {
+		amount := t.Amount
+		if amount == 0 {
+			amount = t.Multiple * input.TargetEBITDA
+		}
+		if t.Type == TrancheRevolver && t.Commitment > 0 && amount > t.Commitment {
+			return LBOResult{}, fmt.Errorf("revolver '%s' drawn %.2f at close exceeds commitment %.2f", t.Name, amount, t.Commitment)
+		}
+		balances[i], original[i] = amount, amount
+		debtRaised += amount
 
-		// Simplified Tax: (EBITDA - D&A - Interest) * t
-		// We approx Interest = currentDebt * Rate
-		interest := currentDebt * input.InterestRate
+		feeBase := amount
+		if t.Type == TrancheRevolver && t.Commitment > 0 {
+			feeBase = t.Commitment
+		}
+		financingFees += feeBase * t.FeePercent
+	}
 
-		// Pre-tax Cash Flow (Proxy)
-		// Usually: EBITDA - Interest - Taxes - CapEx - NWC
-		// Simplified Tax Base: EBITDA - Interest (Ignoring D&A tax shield for simplicity or assume D&A ~ Capex if not provided)
-		// Better: Input provides FCF directly? No, we have projected components.
-		// Let's assume D&A is roughly equal to CapEx for tax shield if not provided.
+	// 2. Debt schedule
+	schedule, cash, err := runDebtSchedule(input, tranches, balances, original)
+	if err != nil {
+		return LBOResult{}, err
+	}
 
-		taxableIncome := ebitda - interest // - D&A
-		taxes := taxableIncome * input.TaxRate
-		if taxes < 0 {
-			taxes = 0
-		}
+	// 3. Exit
+	totalDebt := 0.0
+	for _, b := range balances {
+		totalDebt += b
+	}
+	exitEV := input.Years[input.HoldingPeriod-1].EBITDA * input.ExitMultiple
+	exitNetDebt := totalDebt - cash
+	exitEquity := exitEV - exitNetDebt
 
-		fcf := ebitda - interest - taxes - capex - nwc
+	res := LBOResult{
+		Mode:            input.Mode,
+		DebtRaised:      debtRaised,
+		FinancingFees:   financingFees,
+		ExitEV:          exitEV,
+		ExitNetDebt:     exitNetDebt,
+		ExitEquityValue: exitEquity,
+		Schedule:        schedule,
+	}
 
-		// Debt Paydown (Sweep)
-		if fcf > 0 {
-			currentDebt -= fcf
-			if currentDebt < 0 {
-				currentDebt = 0
-			}
+	// 4. Uses = EV + transaction fees + financing fees + cash to balance sheet = Debt + Equity
+	years := float64(input.HoldingPeriod)
+	switch input.Mode {
+	case LBOModeTargetIRR:
+		res.EquityCheck = exitEquity / math.Pow(1.0+input.TargetIRR, years)
+		res.EntryEV = (res.EquityCheck + debtRaised - financingFees - input.MinimumCash) / (1 + input.TransactionFeePct)
+		res.MaxEntryEV = res.EntryEV
+		res.ArchiveIRR = input.TargetIRR
+	case LBOModeEntryMultiple:
+		res.EntryEV = input.EntryMultiple * input.TargetEBITDA
+		res.EquityCheck = res.EntryEV*(1+input.TransactionFeePct) + financingFees + input.MinimumCash - debtRaised
+		if res.EquityCheck <= 0 {
+			return LBOResult{}, fmt.Errorf("debt of %.2f funds the entire purchase; no sponsor equity required", debtRaised)
+		}
+		if exitEquity > 0 {
+			res.ArchiveIRR = math.Pow(exitEquity/res.EquityCheck, 1/years) - 1
 		} else {
-			// Deficit funded by revolver?
-			currentDebt += (-fcf)
+			res.ArchiveIRR = -1
 		}
 	}
+	res.TransactionFees = res.EntryEV * input.TransactionFeePct
+	res.ImpliedEntryMultiple = res.EntryEV / input.TargetEBITDA
+	if res.EquityCheck != 0 {
+		res.MOIC = exitEquity / res.EquityCheck
+	}
+	return res, nil
+}
 
-	// 3. Exit
-	finalEBITDA := input.ProjectedEBITDA[input.HoldingPeriod-1]
-	exitEV := finalEBITDA * input.ExitMultiple
-	exitEquity := exitEV - currentDebt
+// runDebtSchedule rolls the tranches forward over the holding period.
+// Interest is charged on beginning balances. balances is updated in place.
+func runDebtSchedule(input LBOInput, tranches []DebtTranche, balances, original []float64) ([]LBOScheduleYear, float64, error) {
+	sweepPct := input.SweepPercent
+	if sweepPct == 0 {
+		sweepPct = 1
+	}
 
-	// 4. Solve for Entry Equity (Backward Induction)
-	// targetIRR = (ExitEquity / EntryEquity)^(1/T) - 1
-	// (1+IRR)^T = Exit / Entry
-	// Entry = Exit / (1+IRR)^T
+	// Sweep order: revolver first, then by priority
+	order := make([]int, 0, len(tranches))
+	for i, t := range tranches {
+		if t.Type == TrancheRevolver || t.SweepPriority > 0 {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ta, tb := tranches[order[a]], tranches[order[b]]
+		if (ta.Type == TrancheRevolver) != (tb.Type == TrancheRevolver) {
+			return ta.Type == TrancheRevolver
+		}
+		return ta.SweepPriority < tb.SweepPriority
+	})
 
-	requiredEquity := exitEquity / math.Pow(1.0+input.TargetIRR, float64(input.HoldingPeriod))
+	cash := input.MinimumCash
+	schedule := make([]LBOScheduleYear, 0, input.HoldingPeriod)
+	for y := 0; y < input.HoldingPeriod; y++ {
+		op := input.Years[y]
+		row := LBOScheduleYear{Year: op.Year, EBITDA: op.EBITDA, Capex: op.Capex, ChangeNWC: op.ChangeNWC}
+		row.Tranches = make([]TrancheYear, len(tranches))
 
-	// 5. Total EV
-	maxEntryEV := requiredEquity + initialDebt
+		for i, t := range tranches {
+			ty := TrancheYear{Name: t.Name, Beginning: balances[i]}
+			interest := balances[i] * t.InterestRate
+			if t.Type == TranchePIK {
+				ty.PIKInterest = interest
+				balances[i] += interest
+			} else {
+				ty.CashInterest = interest
+			}
+			row.CashInterest += ty.CashInterest
+			row.PIKInterest += ty.PIKInterest
+			row.Tranches[i] = ty
+		}
+
+		taxable := op.EBITDA - op.DandA - row.CashInterest - row.PIKInterest
+		row.Taxes = math.Max(0, taxable*input.TaxRate)
+		row.CFADS = op.EBITDA - row.Taxes - op.Capex - op.ChangeNWC - row.CashInterest
+
+		// Mandatory amortization
+		available := cash + row.CFADS - input.MinimumCash
+		for i, t := range tranches {
+			if t.Type == TrancheRevolver || t.Amortization == 0 {
+				continue
+			}
+			due := math.Min(balances[i], original[i]*t.Amortization)
+			balances[i] -= due
+			row.Tranches[i].Mandatory = due
+			available -= due
+		}
 
-	return LBOResult{
-		MaxEntryEV:           maxEntryEV,
-		ImpliedEntryMultiple: maxEntryEV / input.TargetEBITDA,
-		EquityCheck:          requiredEquity,
-		DebtRaised:           initialDebt,
-		ExitEquityValue:      exitEquity,
+		if available < 0 {
+			// Deficit: draw on the revolver(s)
+			for i, t := range tranches {
+				if t.Type != TrancheRevolver || available >= 0 {
+					continue
+				}
+				capacity := math.Inf(1)
+				if t.Commitment > 0 {
+					capacity = t.Commitment - balances[i]
+				}
+				draw := math.Min(-available, math.Max(0, capacity))
+				balances[i] += draw
+				row.Tranches[i].Drawn = draw
+				available += draw
+			}
+			if available < -1e-9 {
+				return nil, 0, fmt.Errorf("year %d: cash shortfall of %.2f exceeds revolver capacity", op.Year, -available)
+			}
+		} else {
+			// Surplus: optional prepayment in priority order
+			sweep := available * sweepPct
+			for _, i := range order {
+				if sweep <= 0 {
+					break
+				}
+				pay := math.Min(sweep, balances[i])
+				balances[i] -= pay
+				row.Tranches[i].Optional = pay
+				sweep -= pay
+				available -= pay
+			}
+		}
+		cash = input.MinimumCash + available
+
+		for i := range tranches {
+			row.Tranches[i].Ending = balances[i]
+			row.TotalDebt += balances[i]
+		}
+		row.Cash = cash
+		if op.EBITDA != 0 {
+			row.Leverage = row.TotalDebt / op.EBITDA
+		}
+		schedule = append(schedule, row)
+	}
+	return schedule, cash, nil
+}
+
+func validateLBOInput(input LBOInput) error {
+	if input.HoldingPeriod <= 0 {
+		return fmt.Errorf("holding period must be positive")
+	}
+	if len(input.Years) < input.HoldingPeriod {
+		return fmt.Errorf("holding period of %d years needs %d projected years, got %d", input.HoldingPeriod, input.HoldingPeriod, len(input.Years))
+	}
+	if input.TargetEBITDA <= 0 {
+		return fmt.Errorf("entry EBITDA must be positive")
+	}
+	if input.ExitMultiple <= 0 {
+		return fmt.Errorf("exit multiple must be positive")
+	}
+	switch input.Mode {
+	case LBOModeTargetIRR:
+		if input.TargetIRR <= -1 {
+			return fmt.Errorf("target IRR must exceed -100%%")
+		}
+	case LBOModeEntryMultiple:
+		if input.EntryMultiple <= 0 {
+			return fmt.Errorf("entry multiple must be positive")
+		}
+	default:
+		return fmt.Errorf("unknown LBO mode '%s'", input.Mode)
+	}
+	return nil
+}
+
+// LBOYearsFromProjections extracts the operating lines an LBO needs from the
+// projection engine output (EBITDA = operating income + D&A)
+func LBOYearsFromProjections(projections []*projection.ProjectedFinancials) []LBOYear {
+	years := make([]LBOYear, 0, len(projections))
+	for _, p := range projections {
+		y := LBOYear{Year: p.Year}
+		var opIncome float64
+		if p.IncomeStatement != nil && p.IncomeStatement.OperatingCostSection != nil {
+			opIncome = getValSafe(p.IncomeStatement.OperatingCostSection.OperatingIncome)
+		}
+		if p.CashFlow != nil {
+			if ops := p.CashFlow.OperatingActivities; ops != nil {
+				y.DandA = getValSafe(ops.DepreciationAmortization)
+				// Working capital lines carry their cash effect (increase in AR is negative)
+				y.ChangeNWC = -(getValSafe(ops.ChangeReceivables) + getValSafe(ops.ChangeInventory) +
+					getValSafe(ops.ChangePayables) + getValSafe(ops.ChangeAccruedExpenses) +
+					getValSafe(ops.ChangeDeferredRevenue) + getValSafe(ops.OtherWorkingCapital))
+			}
+			if inv := p.CashFlow.InvestingActivities; inv != nil {
+				y.Capex = -getValSafe(inv.Capex)
+			}
+		}
+		y.EBITDA = opIncome + y.DandA
+		years = append(years, y)
 	}
+	return years
 }
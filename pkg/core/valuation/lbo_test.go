@@ -0,0 +1,137 @@
+package valuation
+
+import (
+	"math"
+	"testing"
+)
+
+func lboYears(n int) []LBOYear {
+	years := make([]LBOYear, n)
+	for i := range years {
+		ebitda := 100 * math.Pow(1.05, float64(i+1))
+		years[i] = LBOYear{Year: 2025 + i, EBITDA: ebitda, DandA: 20, Capex: 20, ChangeNWC: 5}
+	}
+	return years
+}
+
+func lboInput() LBOInput {
+	return LBOInput{
+		Mode:          LBOModeEntryMultiple,
+		TargetEBITDA:  100,
+		EntryMultiple: 10,
+		ExitMultiple:  10,
+		HoldingPeriod: 5,
+		TaxRate:       0.25,
+		Years:         lboYears(5),
+		Tranches: []DebtTranche{
+			{Name: "Revolver", Type: TrancheRevolver, Commitment: 50, FeePercent: 0.01},
+			{Name: "TLB", Type: TrancheTermLoan, Multiple: 3, InterestRate: 0.07, Amortization: 0.01, SweepPriority: 1, FeePercent: 0.02},
+			{Name: "Senior Notes", Type: TrancheSeniorNotes, Multiple: 1.5, InterestRate: 0.08},
+			{Name: "HoldCo PIK", Type: TranchePIK, Multiple: 0.5, InterestRate: 0.12},
+		},
+		TransactionFeePct: 0.02,
+		MinimumCash:       10,
+	}
+}
+
+func TestCalculateLBO_ShortProjectionsError(t *testing.T) {
+	input := lboInput()
+	input.Years = lboYears(3)
+	if _, err := CalculateLBO(input); err == nil {
+		t.Fatal("expected error when projections are shorter than the holding period")
+	}
+}
+
+func TestCalculateLBO_DebtSchedule(t *testing.T) {
+	res, err := CalculateLBO(lboInput())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Schedule) != 5 {
+		t.Fatalf("expected 5 schedule years, got %d", len(res.Schedule))
+	}
+	if res.DebtRaised != 500 {
+		t.Errorf("debt raised = %.2f, want 500", res.DebtRaised)
+	}
+
+	first := res.Schedule[0]
+	tlb, notes, pik := first.Tranches[1], first.Tranches[2], first.Tranches[3]
+	if !almostEqual(tlb.Mandatory, 3) {
+		t.Errorf("TLB mandatory amortization = %.2f, want 1%% of 300", tlb.Mandatory)
+	}
+	if tlb.Optional <= 0 {
+		t.Error("excess cash should be swept into the TLB")
+	}
+	if notes.Optional != 0 || notes.Ending != 150 {
+		t.Errorf("notes are not prepayable: %+v", notes)
+	}
+	if !almostEqual(pik.Ending, 50*1.12) || pik.CashInterest != 0 {
+		t.Errorf("PIK should accrue: %+v", pik)
+	}
+	for i := 1; i < len(res.Schedule); i++ {
+		if res.Schedule[i].Tranches[1].Ending >= res.Schedule[i-1].Tranches[1].Ending {
+			t.Errorf("TLB should amortize every year")
+		}
+	}
+	if res.ArchiveIRR <= 0 || res.MOIC <= 1 {
+		t.Errorf("expected positive returns, got IRR %.4f MOIC %.2f", res.ArchiveIRR, res.MOIC)
+	}
+}
+
+func TestCalculateLBO_ModesAreInverse(t *testing.T) {
+	atPrice, err := CalculateLBO(lboInput())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	input := lboInput()
+	input.Mode = LBOModeTargetIRR
+	input.TargetIRR = atPrice.ArchiveIRR
+	maxPrice, err := CalculateLBO(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !almostEqual(maxPrice.MaxEntryEV, 1000) {
+		t.Errorf("max entry EV at the achieved IRR = %.4f, want 1000", maxPrice.MaxEntryEV)
+	}
+	if !almostEqual(maxPrice.EquityCheck, atPrice.EquityCheck) {
+		t.Errorf("equity check %.4f != %.4f", maxPrice.EquityCheck, atPrice.EquityCheck)
+	}
+
+	// Inputs without a mode solve for price, as before modes existed
+	input.Mode = ""
+	legacy, err := CalculateLBO(input)
+	if err != nil {
+		t.Fatalf("unexpected error without a mode: %v", err)
+	}
+	if legacy.Mode != LBOModeTargetIRR || !almostEqual(legacy.MaxEntryEV, maxPrice.MaxEntryEV) {
+		t.Errorf("empty mode gave %s at %.4f, want target IRR at %.4f", legacy.Mode, legacy.MaxEntryEV, maxPrice.MaxEntryEV)
+	}
+}
+
+func TestCalculateLBO_RevolverFundsDeficit(t *testing.T) {
+	input := lboInput()
+	input.Years[0].Capex = 100 // Large first-year capex
+	res, err := CalculateLBO(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Schedule[0].Tranches[0].Drawn <= 0 {
+		t.Error("expected a revolver draw in the deficit year")
+	}
+	if res.Schedule[1].Tranches[0].Optional <= 0 {
+		t.Error("revolver should be repaid first once cash returns")
+	}
+
+	input.Years[0].Capex = 1000
+	if _, err := CalculateLBO(input); err == nil {
+		t.Error("expected error when the deficit exceeds the revolver commitment")
+	}
+}
+
+func TestLBOYearsFromProjections(t *testing.T) {
+	years := LBOYearsFromProjections(operatingProjections(2))
+	if len(years) != 2 || years[0].EBITDA != 100 || years[0].DandA != 20 {
+		t.Errorf("unexpected LBO years: %+v", years)
+	}
+}
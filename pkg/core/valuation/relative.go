@@ -1,6 +1,8 @@
 package valuation
 
 import (
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/projection"
 	"sort"
 )
 
@@ -13,6 +15,28 @@ type MetricInput struct {
 	SharesOut float64
 }
 
+// MetricInputFrom reads the multiples' denominators from a twelve-month period
+// (projection.LTM / projection.NTM or a fiscal year); net debt comes from the
+// valuation-date balance sheet, which for NTM is not the period's own
+func MetricInputFrom(period *projection.ProjectedFinancials, bs *edgar.BalanceSheet, sharesOut float64) MetricInput {
+	m := MetricInput{NetDebt: NewEquityBridge(bs).Total(), SharesOut: sharesOut}
+	if is := period.IncomeStatement; is != nil {
+		if is.GrossProfitSection != nil {
+			m.Revenue = getValSafe(is.GrossProfitSection.Revenues)
+		}
+		if is.OperatingCostSection != nil {
+			m.EBITDA = getValSafe(is.OperatingCostSection.OperatingIncome)
+		}
+		if is.NetIncomeSection != nil {
+			m.NetIncome = getValSafe(is.NetIncomeSection.NetIncomeToCommon)
+		}
+	}
+	if cf := period.CashFlow; cf != nil && cf.OperatingActivities != nil {
+		m.EBITDA += getValSafe(cf.OperatingActivities.DepreciationAmortization)
+	}
+	return m
+}
+
 // PeerComparable represents a comparable company or transaction
 type PeerComparable struct {
 	Name          string
@@ -61,31 +85,73 @@ func calculateMultiples(target MetricInput, peers []PeerComparable, onlyTransact
 
 	res := RelativeValuationResult{}
 
-	// Helper to get ranges (25th - 75th percentile)
+	// Helper to get ranges (interpolated 25th - 75th percentile)
 	getRange := func(mults []float64) (float64, float64) {
 		if len(mults) == 0 {
 			return 0, 0
 		}
 		sort.Float64s(mults)
-		lowIdx := int(float64(len(mults)) * 0.25)
-		highIdx := int(float64(len(mults)) * 0.75)
-		if highIdx >= len(mults) {
-			highIdx = len(mults) - 1
+		return Quantile(mults, 0.25), Quantile(mults, 0.75)
+	}
+
+	// Per-share price implied by an EV range
+	evToPrice := func(ev [2]float64) [2]float64 {
+		if target.SharesOut == 0 {
+			return [2]float64{}
 		}
-		return mults[lowIdx], mults[highIdx]
+		return [2]float64{(ev[0] - target.NetDebt) / target.SharesOut, (ev[1] - target.NetDebt) / target.SharesOut}
 	}
 
+	// Composite = equal-weighted average of the per-share prices of every multiple with peers
+	var prices [][2]float64
+
 	// EV/Revenue Implied EV
-	rLo, rHi := getRange(revMults)
-	res.ImpliedEV_Revenue = [2]float64{rLo * target.Revenue, rHi * target.Revenue}
+	if len(revMults) > 0 {
+		rLo, rHi := getRange(revMults)
+		res.ImpliedEV_Revenue = [2]float64{rLo * target.Revenue, rHi * target.Revenue}
+		prices = append(prices, evToPrice(res.ImpliedEV_Revenue))
+	}
 
 	// EV/EBITDA Implied EV
-	eLo, eHi := getRange(ebitdaMults)
-	res.ImpliedEV_EBITDA = [2]float64{eLo * target.EBITDA, eHi * target.EBITDA}
+	if len(ebitdaMults) > 0 {
+		eLo, eHi := getRange(ebitdaMults)
+		res.ImpliedEV_EBITDA = [2]float64{eLo * target.EBITDA, eHi * target.EBITDA}
+		prices = append(prices, evToPrice(res.ImpliedEV_EBITDA))
+	}
 
 	// P/E Implied Price (Direct Equity Value)
-	pLo, pHi := getRange(peMults)
-	res.ImpliedPE_Price = [2]float64{pLo * target.NetIncome / target.SharesOut, pHi * target.NetIncome / target.SharesOut}
+	if len(peMults) > 0 && target.SharesOut != 0 {
+		pLo, pHi := getRange(peMults)
+		res.ImpliedPE_Price = [2]float64{pLo * target.NetIncome / target.SharesOut, pHi * target.NetIncome / target.SharesOut}
+		prices = append(prices, res.ImpliedPE_Price)
+	}
+
+	for _, p := range prices {
+		res.CompositePrice[0] += p[0] / float64(len(prices))
+		res.CompositePrice[1] += p[1] / float64(len(prices))
+	}
 
 	return res
 }
+
+// Quantile returns the q-th quantile (0..1) of sorted data, interpolating
+// linearly between the closest ranks
+func Quantile(sorted []float64, q float64) float64 {
+	n := len(sorted)
+	if n == 0 {
+		return 0
+	}
+	if q <= 0 {
+		return sorted[0]
+	}
+	if q >= 1 {
+		return sorted[n-1]
+	}
+	pos := q * float64(n-1)
+	lo := int(pos)
+	if lo+1 >= n {
+		return sorted[n-1]
+	}
+	frac := pos - float64(lo)
+	return sorted[lo] + frac*(sorted[lo+1]-sorted[lo])
+}
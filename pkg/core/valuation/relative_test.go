@@ -0,0 +1,41 @@
+package valuation
+
+import (
+	"testing"
+
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/projection"
+)
+
+func TestMetricInputFrom(t *testing.T) {
+	ltm := &projection.ProjectedFinancials{
+		IncomeStatement: &edgar.IncomeStatement{
+			GrossProfitSection:   &edgar.GrossProfitSection{Revenues: fsap(1000)},
+			OperatingCostSection: &edgar.OperatingCostSection{OperatingIncome: fsap(150)},
+			NetIncomeSection:     &edgar.NetIncomeSection{NetIncomeToCommon: fsap(90)},
+		},
+		CashFlow: &edgar.CashFlowStatement{OperatingActivities: &edgar.CFOperatingSection{DepreciationAmortization: fsap(50)}},
+	}
+	bs := &edgar.BalanceSheet{
+		CurrentAssets:         edgar.CurrentAssets{CashAndEquivalents: fsap(100)},
+		NoncurrentLiabilities: edgar.NoncurrentLiabilities{LongTermDebt: fsap(300)},
+	}
+
+	m := MetricInputFrom(ltm, bs, 40)
+	if m.Revenue != 1000 || m.EBITDA != 200 || m.NetIncome != 90 || m.NetDebt != 200 || m.SharesOut != 40 {
+		t.Errorf("unexpected metrics: %+v", m)
+	}
+}
+
+func TestQuantile_Interpolates(t *testing.T) {
+	sorted := []float64{1, 2, 3, 4}
+	if got := Quantile(sorted, 0.5); got != 2.5 {
+		t.Errorf("expected median 2.5, got %v", got)
+	}
+	if got := Quantile(sorted, 1); got != 4 {
+		t.Errorf("expected max 4, got %v", got)
+	}
+	if got := Quantile([]float64{7}, 0.25); got != 7 {
+		t.Errorf("expected a single value to be every quantile, got %v", got)
+	}
+}
@@ -0,0 +1,248 @@
+package valuation
+
+import (
+	"agentic_valuation/pkg/core/projection"
+	"fmt"
+	"math"
+)
+
+// ReverseDriver is the assumption a reverse DCF solves for
+type ReverseDriver string
+
+const (
+	ReverseRevenueGrowth   ReverseDriver = "revenue_growth"   // Flat growth over the forecast
+	ReverseOperatingMargin ReverseDriver = "operating_margin" // EBIT margin, reached by moving COGS %
+	ReverseTerminalGrowth  ReverseDriver = "terminal_growth"
+	ReverseWACC            ReverseDriver = "wacc"
+	ReverseROIC            ReverseDriver = "ronic" // Return on new invested capital in the value-driver terminal value
+)
+
+// ReverseDCFInput describes what the market price is reconciled against
+type ReverseDCFInput struct {
+	Base        MasterValuationInput             // DCF inputs; Base.Projections sets the horizon length
+	Assumptions projection.ProjectionAssumptions // Template the projections were built from
+	Skeleton    *projection.StandardSkeleton     // Optional: strategies that drive the projections
+	History     projection.History               // Required for revenue_growth and operating_margin
+	MarketPrice float64
+	Driver      ReverseDriver
+	Low, High   float64 // Optional search bracket (defaults per driver)
+	Tolerance   float64 // Price tolerance, default 1e-6
+}
+
+// ReverseDCFResult is the driver value at which CalculateDCF reproduces the price
+type ReverseDCFResult struct {
+	Driver       ReverseDriver `json:"driver"`
+	ImpliedValue float64       `json:"implied_value"`
+	BaseValue    float64       `json:"base_value"` // Driver in the template
+	MarketPrice  float64       `json:"market_price"`
+	BasePrice    float64       `json:"base_price"` // DCF price at the template assumptions
+	Iterations   int           `json:"iterations"`
+}
+
+// Summary states the implied figure in one sentence (used as debate material)
+func (r ReverseDCFResult) Summary() string {
+	return fmt.Sprintf("At $%.2f the market implies %s of %.2f%% (base case %.2f%%, DCF value $%.2f)",
+		r.MarketPrice, r.Driver, r.ImpliedValue*100, r.BaseValue*100, r.BasePrice)
+}
+
+// SolveReverseDCF back-solves one driver so the FCFF share price equals the market price.
+// It uses Brent's method on a bracket; if the price is not reachable inside the
+// bracket it fails with the price range the bracket covers.
+func SolveReverseDCF(input ReverseDCFInput) (*ReverseDCFResult, error) {
+	if input.MarketPrice <= 0 {
+		return nil, fmt.Errorf("market price must be positive")
+	}
+	if len(input.Base.Projections) == 0 {
+		return nil, fmt.Errorf("reverse DCF needs base projections to set the horizon")
+	}
+
+	baseValue, err := reverseDriverValue(input)
+	if err != nil {
+		return nil, err
+	}
+	lo, hi := input.Low, input.High
+	if lo == 0 && hi == 0 {
+		lo, hi = reverseBracket(input)
+	}
+	if lo >= hi {
+		return nil, fmt.Errorf("invalid bracket [%.4f, %.4f] for %s", lo, hi, input.Driver)
+	}
+	tol := input.Tolerance
+	if tol <= 0 {
+		tol = 1e-6
+	}
+
+	price := func(x float64) (float64, error) { return reversePrice(input, x) }
+	basePrice, err := price(baseValue)
+	if err != nil {
+		return nil, err
+	}
+
+	gap := func(x float64) (float64, error) {
+		p, err := price(x)
+		return p - input.MarketPrice, err
+	}
+	x, iters, err := brentRoot(gap, lo, hi, tol)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", input.Driver, err)
+	}
+
+	return &ReverseDCFResult{
+		Driver:       input.Driver,
+		ImpliedValue: x,
+		BaseValue:    baseValue,
+		MarketPrice:  input.MarketPrice,
+		BasePrice:    basePrice,
+		Iterations:   iters,
+	}, nil
+}
+
+// reverseDriverValue reads the driver from the template
+func reverseDriverValue(input ReverseDCFInput) (float64, error) {
+	a := input.Assumptions
+	switch input.Driver {
+	case ReverseRevenueGrowth:
+		return a.RevenueGrowth, nil
+	case ReverseOperatingMargin:
+		return 1 - a.COGSPercent - operatingExpensePercent(a), nil
+	case ReverseTerminalGrowth:
+		return input.Base.TerminalGrowth, nil
+	case ReverseWACC:
+		return input.Base.WACC, nil
+	case ReverseROIC:
+		if input.Base.Terminal.RONIC != 0 {
+			return input.Base.Terminal.RONIC, nil
+		}
+		return input.Base.WACC, nil
+	}
+	return 0, fmt.Errorf("unsupported reverse DCF driver '%s'", input.Driver)
+}
+
+// reverseBracket returns a default search interval for the driver
+func reverseBracket(input ReverseDCFInput) (float64, float64) {
+	switch input.Driver {
+	case ReverseRevenueGrowth:
+		return -0.5, 1.0
+	case ReverseOperatingMargin:
+		return -0.5, 0.9
+	case ReverseTerminalGrowth:
+		return -0.1, input.Base.WACC - 1e-4
+	case ReverseWACC:
+		return input.Base.TerminalGrowth + 1e-4, 0.5
+	default: // ReverseROIC
+		return 0.01, 2.0
+	}
+}
+
+// reversePrice values the company with the driver set to x
+func reversePrice(input ReverseDCFInput, x float64) (float64, error) {
+	val := input.Base
+	a := input.Assumptions.Clone()
+	reproject := false
+
+	switch input.Driver {
+	case ReverseRevenueGrowth:
+		a.RevenueGrowth = x
+		reproject = true
+	case ReverseOperatingMargin:
+		a.COGSPercent = 1 - x - operatingExpensePercent(a)
+		reproject = true
+	case ReverseTerminalGrowth:
+		val.TerminalGrowth = x
+	case ReverseWACC:
+		val.WACC = x
+		val.PeriodWACCs = nil
+	case ReverseROIC:
+		val.Terminal.Method = TerminalValueDriver
+		val.Terminal.RONIC = x
+	}
+
+	if reproject {
+		projections, err := projection.NewProjectionEngine(input.Skeleton).ProjectHorizon(projection.HorizonInput{
+			History:     input.History,
+			Assumptions: a,
+			Years:       len(input.Base.Projections),
+		})
+		if err != nil {
+			return 0, err
+		}
+		val.Projections = projections
+	}
+	return CalculateDCF(val.DCFInput()).SharePrice, nil
+}
+
+// operatingExpensePercent mirrors the engine: granular S&M + G&A take priority over SG&A
+func operatingExpensePercent(a projection.ProjectionAssumptions) float64 {
+	sga := a.SGAPercent
+	if a.SellingMarketingPercent != 0 || a.GeneralAdminPercent != 0 {
+		sga = a.SellingMarketingPercent + a.GeneralAdminPercent
+	}
+	return sga + a.RDPercent
+}
+
+// brentRoot finds x in [a, b] with f(x) = 0 to within tol, requiring a sign change
+func brentRoot(f func(float64) (float64, error), a, b, tol float64) (float64, int, error) {
+	const maxIter = 200
+	fa, err := f(a)
+	if err != nil {
+		return 0, 0, err
+	}
+	fb, err := f(b)
+	if err != nil {
+		return 0, 0, err
+	}
+	if fa == 0 {
+		return a, 0, nil
+	}
+	if fb == 0 {
+		return b, 0, nil
+	}
+	if (fa > 0) == (fb > 0) {
+		return 0, 0, fmt.Errorf("no solution in [%.4f, %.4f]: price gap runs from %.2f to %.2f without crossing zero", a, b, fa, fb)
+	}
+
+	if math.Abs(fa) < math.Abs(fb) {
+		a, b, fa, fb = b, a, fb, fa
+	}
+	c, fc := a, fa
+	d := b - a
+	bisected := true
+	for i := 1; i <= maxIter; i++ {
+		var s float64
+		if fa != fc && fb != fc {
+			// Inverse quadratic interpolation
+			s = a*fb*fc/((fa-fb)*(fa-fc)) + b*fa*fc/((fb-fa)*(fb-fc)) + c*fa*fb/((fc-fa)*(fc-fb))
+		} else {
+			// Secant
+			s = b - fb*(b-a)/(fb-fa)
+		}
+
+		mid := (3*a + b) / 4
+		if (s-mid)*(s-b) >= 0 ||
+			(bisected && math.Abs(s-b) >= math.Abs(b-c)/2) ||
+			(!bisected && math.Abs(s-b) >= math.Abs(c-d)/2) {
+			s = (a + b) / 2
+			bisected = true
+		} else {
+			bisected = false
+		}
+
+		fs, err := f(s)
+		if err != nil {
+			return 0, i, err
+		}
+		d, c, fc = c, b, fb
+		if (fa > 0) == (fs > 0) {
+			a, fa = s, fs
+		} else {
+			b, fb = s, fs
+		}
+		if math.Abs(fa) < math.Abs(fb) {
+			a, b, fa, fb = b, a, fb, fa
+		}
+		if math.Abs(fb) < tol || math.Abs(b-a) < 1e-12 {
+			return b, i, nil
+		}
+	}
+	return 0, maxIter, fmt.Errorf("did not converge after %d iterations", maxIter)
+}
@@ -0,0 +1,123 @@
+package valuation_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"agentic_valuation/pkg/core/debate"
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/internal/testfixture"
+	"agentic_valuation/pkg/core/projection"
+	"agentic_valuation/pkg/core/valuation"
+)
+
+func reverseInput(t *testing.T) valuation.ReverseDCFInput {
+	t.Helper()
+	return valuation.ReverseDCFInput{
+		Base:        fixtureInput(t, 5),
+		Assumptions: testfixture.Assumptions(),
+		History:     testfixture.History(),
+	}
+}
+
+// priceAt values the template with one driver moved, independently of the solver
+func priceAt(t *testing.T, in valuation.ReverseDCFInput, driver valuation.ReverseDriver, x float64) float64 {
+	t.Helper()
+	p, err := valuation.ReversePrice(valuation.ReverseDCFInput{Base: in.Base, Assumptions: in.Assumptions, History: in.History, Driver: driver}, x)
+	if err != nil {
+		t.Fatalf("price at %s=%.4f: %v", driver, x, err)
+	}
+	return p
+}
+
+func TestSolveReverseDCF_RecoversDrivers(t *testing.T) {
+	targets := map[valuation.ReverseDriver]float64{
+		valuation.ReverseRevenueGrowth:   0.12,
+		valuation.ReverseOperatingMargin: 0.35,
+		valuation.ReverseTerminalGrowth:  0.03,
+		valuation.ReverseWACC:            0.075,
+		valuation.ReverseROIC:            0.20,
+	}
+	for driver, want := range targets {
+		in := reverseInput(t)
+		in.Driver = driver
+		in.MarketPrice = priceAt(t, in, driver, want)
+
+		res, err := valuation.SolveReverseDCF(in)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", driver, err)
+		}
+		if math.Abs(res.ImpliedValue-want) > 1e-5 {
+			t.Errorf("%s: implied %.6f, want %.6f", driver, res.ImpliedValue, want)
+		}
+		if !strings.Contains(res.Summary(), string(driver)) {
+			t.Errorf("%s: summary %q does not name the driver", driver, res.Summary())
+		}
+	}
+
+	// Even a 90% margin cannot justify this price
+	in := reverseInput(t)
+	in.Driver = valuation.ReverseOperatingMargin
+	in.MarketPrice = 10000
+	res, err := valuation.SolveReverseDCF(in)
+	if err == nil || !strings.Contains(err.Error(), "no solution") {
+		t.Fatalf("expected no-solution error, got %v (%+v)", err, res)
+	}
+}
+
+func TestSolveReverseDCF_InputErrors(t *testing.T) {
+	in := reverseInput(t)
+	in.Driver = valuation.ReverseRevenueGrowth
+	in.MarketPrice = 10
+	in.History = projection.History{}
+	if _, err := valuation.SolveReverseDCF(in); err == nil {
+		t.Error("expected error without history for a projection driver")
+	}
+
+	in = reverseInput(t)
+	in.Driver = "ebitda"
+	in.MarketPrice = 10
+	if _, err := valuation.SolveReverseDCF(in); err == nil {
+		t.Error("expected error for an unsupported driver")
+	}
+}
+
+func TestDebateExpectations(t *testing.T) {
+	hist := testfixture.History()
+	price := 25.0
+	year := edgar.YearData{
+		IncomeStatement: *hist.IncomeStatement,
+		BalanceSheet:    *hist.BalanceSheet,
+		SupplementalData: edgar.SupplementalData{
+			SharesOutstandingDiluted: testfixture.Value(100),
+			SharePriceYearEnd:        &price,
+		},
+	}
+	pool := &debate.MaterialPool{FinancialHistory: []*edgar.FSAPDataResponse{
+		{HistoricalData: map[int]edgar.YearData{2023: {}, 2024: year}},
+	}}
+	baselines := map[string]float64{"rev_growth": 0.05, "cogs_pct": 0.55, "sga_pct": 0.15, "rd_pct": 0, "tax_rate": 0.25}
+
+	got, err := valuation.DebateExpectations(pool, baselines)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != len(valuation.DebateReverseDrivers) {
+		t.Fatalf("expected %d implied drivers, got %+v", len(valuation.DebateReverseDrivers), got)
+	}
+	for _, ie := range got {
+		if ie.MarketPrice != price || ie.BasePrice == price {
+			t.Errorf("%s: market %.2f base %.2f", ie.Driver, ie.MarketPrice, ie.BasePrice)
+		}
+	}
+	if got[0].Driver != string(valuation.ReverseRevenueGrowth) || got[0].Base != 0.05 {
+		t.Errorf("growth should start from the Quant baseline, got %+v", got[0])
+	}
+
+	year.SupplementalData.SharePriceYearEnd = nil
+	pool.FinancialHistory[0].HistoricalData[2024] = year
+	if _, err := valuation.DebateExpectations(pool, baselines); err == nil {
+		t.Error("expected error without a year-end share price")
+	}
+}
@@ -0,0 +1,264 @@
+package valuation
+
+import (
+	"agentic_valuation/pkg/core/edgar"
+	"fmt"
+	"math"
+)
+
+// SegmentMethod selects how a business segment is valued
+type SegmentMethod string
+
+const (
+	SegmentDCF      SegmentMethod = "dcf"      // Segment operating income → NOPAT + D&A − CapEx, discounted
+	SegmentMultiple SegmentMethod = "multiple" // First projected year metric × peer multiple
+)
+
+// SegmentMetric is the denominator of a segment multiple
+type SegmentMetric string
+
+const (
+	SegmentRevenue SegmentMetric = "revenue"
+	SegmentEBIT    SegmentMetric = "ebit"
+	SegmentEBITDA  SegmentMetric = "ebitda"
+)
+
+// SegmentValuationConfig sets the method for one segment (matched by name).
+// Zero rates fall back to the consolidated DCF input.
+type SegmentValuationConfig struct {
+	Segment        string        `json:"segment"`
+	Method         SegmentMethod `json:"method"`
+	Metric         SegmentMetric `json:"metric,omitempty"`   // SegmentMultiple only (default EBITDA)
+	Multiple       float64       `json:"multiple,omitempty"` // SegmentMultiple only, e.g. a peer set median
+	WACC           float64       `json:"wacc,omitempty"`
+	TerminalGrowth float64       `json:"terminal_growth,omitempty"`
+}
+
+// SOTPInput values each projected segment separately and compares the sum with the consolidated DCF
+type SOTPInput struct {
+	Consolidated DCFInput                    // Consolidated model; also supplies default rates, timing and the bridge
+	BaseSegments []edgar.StandardizedSegment // T-0 segments: margins, D&A and CapEx intensity are held at these levels
+	BaseIncome   *edgar.IncomeStatement      // T-0 consolidated income statement (for unallocated overhead)
+	Segments     []SegmentValuationConfig    // Per-segment methods; unlisted segments use SegmentDCF
+}
+
+// SegmentValue is one segment's contribution to the sum of the parts
+type SegmentValue struct {
+	Segment         string        `json:"segment"`
+	Method          SegmentMethod `json:"method"`
+	Metric          SegmentMetric `json:"metric,omitempty"`
+	MetricValue     float64       `json:"metric_value,omitempty"`
+	Multiple        float64       `json:"multiple,omitempty"`
+	OperatingMargin float64       `json:"operating_margin"`
+	EnterpriseValue float64       `json:"enterprise_value"`
+	ShareOfTotal    float64       `json:"share_of_total"` // Of the gross segment sum
+}
+
+// SOTPResult reports the value per segment and the conglomerate discount
+type SOTPResult struct {
+	Segments             []SegmentValue `json:"segments"`
+	GrossSegmentValue    float64        `json:"gross_segment_value"`
+	BaseOverhead         float64        `json:"base_overhead"`  // Segment operating income sum − consolidated operating income
+	OverheadValue        float64        `json:"overhead_value"` // PV of after-tax corporate costs (negative)
+	EnterpriseValue      float64        `json:"enterprise_value"`
+	NetDebt              float64        `json:"net_debt"`
+	EquityValue          float64        `json:"equity_value"`
+	SharePrice           float64        `json:"share_price"`
+	ConsolidatedEV       float64        `json:"consolidated_ev"`
+	ConsolidatedPrice    float64        `json:"consolidated_price"`
+	ConglomerateDiscount float64        `json:"conglomerate_discount"` // 1 − consolidated EV / SOTP EV
+	Warnings             []string       `json:"warnings,omitempty"`
+}
+
+// CalculateSOTP performs a sum-of-the-parts valuation on the segment projections.
+// Projected segments only carry revenue, so each segment's operating income,
+// D&A and CapEx are scaled from its T-0 ratios to revenue. Corporate overhead
+// (the gap between segment and consolidated operating income) grows with
+// consolidated revenue and is valued as a negative after-tax cash flow.
+func CalculateSOTP(input SOTPInput) (SOTPResult, error) {
+	var res SOTPResult
+	dcf := input.Consolidated
+	if len(dcf.Projections) == 0 {
+		return res, fmt.Errorf("SOTP needs projections")
+	}
+	if len(dcf.Projections[0].Segments) == 0 {
+		return res, fmt.Errorf("projections carry no segments (set SegmentGrowth and pass T-0 segments to the engine)")
+	}
+
+	base := make(map[string]edgar.StandardizedSegment, len(input.BaseSegments))
+	for _, s := range input.BaseSegments {
+		base[s.Name] = s
+	}
+	configs := make(map[string]SegmentValuationConfig, len(input.Segments))
+	for _, c := range input.Segments {
+		configs[c.Segment] = c
+	}
+
+	segmentOpInc := 0.0
+	for _, seg := range dcf.Projections[0].Segments {
+		b, ok := base[seg.Name]
+		if !ok || getValSafe(b.Revenues) == 0 {
+			return res, fmt.Errorf("segment '%s' has no T-0 revenue to derive margins from", seg.Name)
+		}
+		segmentOpInc += getValSafe(b.OperatingIncome)
+
+		cfg, ok := configs[seg.Name]
+		if !ok {
+			cfg = SegmentValuationConfig{Segment: seg.Name, Method: SegmentDCF}
+		}
+		sv, err := valueSegment(dcf, b, cfg)
+		if err != nil {
+			return res, err
+		}
+		res.Segments = append(res.Segments, sv)
+		res.GrossSegmentValue += sv.EnterpriseValue
+	}
+
+	// Unallocated corporate overhead
+	if input.BaseIncome != nil && input.BaseIncome.OperatingCostSection != nil {
+		res.BaseOverhead = segmentOpInc - getValSafe(input.BaseIncome.OperatingCostSection.OperatingIncome)
+	} else {
+		res.Warnings = append(res.Warnings, "no T-0 income statement; corporate overhead not deducted")
+	}
+	if res.BaseOverhead != 0 {
+		ov, err := valueOverhead(dcf, input.BaseIncome, res.BaseOverhead)
+		if err != nil {
+			return res, err
+		}
+		res.OverheadValue = ov
+	}
+
+	for i := range res.Segments {
+		if res.GrossSegmentValue != 0 {
+			res.Segments[i].ShareOfTotal = res.Segments[i].EnterpriseValue / res.GrossSegmentValue
+		}
+	}
+	res.EnterpriseValue = res.GrossSegmentValue + res.OverheadValue
+
+	bridge := dcf.Bridge
+	if bridge == nil {
+		bridge = NewNetDebtBridge(dcf.NetDebt)
+	}
+	res.NetDebt = bridge.Total()
+	res.EquityValue = bridge.EquityValue(res.EnterpriseValue)
+	if dcf.SharesOutstanding != 0 {
+		res.SharePrice = res.EquityValue / dcf.SharesOutstanding
+	}
+
+	consolidated := CalculateDCF(dcf)
+	res.ConsolidatedEV = consolidated.EnterpriseValue
+	res.ConsolidatedPrice = consolidated.SharePrice
+	if res.EnterpriseValue != 0 {
+		res.ConglomerateDiscount = 1 - res.ConsolidatedEV/res.EnterpriseValue
+	}
+	return res, nil
+}
+
+// valueSegment applies the configured method to one segment
+func valueSegment(dcf DCFInput, b edgar.StandardizedSegment, cfg SegmentValuationConfig) (SegmentValue, error) {
+	baseRev := getValSafe(b.Revenues)
+	margin := getValSafe(b.OperatingIncome) / baseRev
+	daRatio := math.Abs(getValSafe(b.Depreciation)) / baseRev
+	capexRatio := math.Abs(getValSafe(b.CapEx)) / baseRev
+
+	sv := SegmentValue{Segment: b.Name, Method: cfg.Method, OperatingMargin: margin}
+	revenues := segmentRevenues(dcf, b.Name)
+
+	switch cfg.Method {
+	case SegmentMultiple:
+		if cfg.Multiple <= 0 {
+			return sv, fmt.Errorf("segment '%s': multiple must be positive", b.Name)
+		}
+		metric := cfg.Metric
+		if metric == "" {
+			metric = SegmentEBITDA
+		}
+		rev := revenues[0]
+		switch metric {
+		case SegmentRevenue:
+			sv.MetricValue = rev
+		case SegmentEBIT:
+			sv.MetricValue = rev * margin
+		case SegmentEBITDA:
+			sv.MetricValue = rev * (margin + daRatio)
+		default:
+			return sv, fmt.Errorf("segment '%s': unknown metric '%s'", b.Name, metric)
+		}
+		sv.Metric = metric
+		sv.Multiple = cfg.Multiple
+		sv.EnterpriseValue = sv.MetricValue * cfg.Multiple
+
+	case SegmentDCF, "":
+		sv.Method = SegmentDCF
+		wacc, g := cfg.WACC, cfg.TerminalGrowth
+		if wacc == 0 {
+			wacc = dcf.WACC
+		}
+		if g == 0 {
+			g = dcf.TerminalGrowth
+		}
+		flows := make([]float64, len(revenues))
+		for i, rev := range revenues {
+			flows[i] = rev*margin*(1-dcf.TaxRate) + rev*daRatio - rev*capexRatio
+		}
+		ev, err := discountFlows(flows, wacc, g, dcf.Timing)
+		if err != nil {
+			return sv, fmt.Errorf("segment '%s': %w", b.Name, err)
+		}
+		sv.EnterpriseValue = ev
+
+	default:
+		return sv, fmt.Errorf("segment '%s': unknown method '%s'", b.Name, cfg.Method)
+	}
+	return sv, nil
+}
+
+// valueOverhead discounts after-tax overhead growing with consolidated revenue at the consolidated WACC
+func valueOverhead(dcf DCFInput, baseIS *edgar.IncomeStatement, overhead float64) (float64, error) {
+	var baseRev float64
+	if baseIS.GrossProfitSection != nil {
+		baseRev = getValSafe(baseIS.GrossProfitSection.Revenues)
+	}
+	flows := make([]float64, len(dcf.Projections))
+	for i, p := range dcf.Projections {
+		scale := 1.0
+		if baseRev != 0 && p.IncomeStatement != nil && p.IncomeStatement.GrossProfitSection != nil {
+			scale = getValSafe(p.IncomeStatement.GrossProfitSection.Revenues) / baseRev
+		}
+		flows[i] = -overhead * scale * (1 - dcf.TaxRate)
+	}
+	ev, err := discountFlows(flows, dcf.WACC, dcf.TerminalGrowth, dcf.Timing)
+	if err != nil {
+		return 0, fmt.Errorf("corporate overhead: %w", err)
+	}
+	return ev, nil
+}
+
+// segmentRevenues collects a segment's projected revenue by year
+func segmentRevenues(dcf DCFInput, name string) []float64 {
+	out := make([]float64, len(dcf.Projections))
+	for i, p := range dcf.Projections {
+		for _, s := range p.Segments {
+			if s.Name == name {
+				out[i] = getValSafe(s.Revenues)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// discountFlows values explicit flows plus a growing perpetuity on the last one
+func discountFlows(flows []float64, wacc, g float64, timing DiscountTiming) (float64, error) {
+	disc := newDiscounter(timing)
+	pv := 0.0
+	for _, f := range flows {
+		fraction, factor := disc.next(wacc)
+		pv += f * fraction * factor
+	}
+	tv, _, err := terminalValue(TerminalValueConfig{}, terminalBase{UFCF: flows[len(flows)-1]}, wacc, g)
+	if err != nil {
+		return 0, err
+	}
+	return pv + tv*disc.terminal(wacc), nil
+}
@@ -0,0 +1,92 @@
+package valuation
+
+import (
+	"math"
+	"testing"
+
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/projection"
+)
+
+func segment(name string, revenue, opInc, da, capex float64) edgar.StandardizedSegment {
+	return edgar.StandardizedSegment{
+		Name:            name,
+		Revenues:        fsap(revenue),
+		OperatingIncome: fsap(opInc),
+		Depreciation:    fsap(da),
+		CapEx:           fsap(capex),
+	}
+}
+
+// sotpProjections holds segments flat at T-0 revenue; consolidated revenue is 1000
+func sotpProjections(n int, segs []edgar.StandardizedSegment) []*projection.ProjectedFinancials {
+	out := flatProjections(n, 150, 0)
+	for _, p := range out {
+		p.Segments = segs
+		p.IncomeStatement.GrossProfitSection = &edgar.GrossProfitSection{Revenues: fsap(1000)}
+	}
+	return out
+}
+
+func TestCalculateSOTP_SegmentsOverheadAndDiscount(t *testing.T) {
+	segs := []edgar.StandardizedSegment{
+		segment("Software", 400, 120, 0, 0),  // DCF: NOPAT 90 / 10% = 900
+		segment("Hardware", 600, 80, 20, 20), // 8x EBITDA of 100 = 800
+	}
+	baseIS := &edgar.IncomeStatement{
+		GrossProfitSection:   &edgar.GrossProfitSection{Revenues: fsap(1000)},
+		OperatingCostSection: &edgar.OperatingCostSection{OperatingIncome: fsap(160)}, // 40 of overhead
+	}
+
+	res, err := CalculateSOTP(SOTPInput{
+		Consolidated: DCFInput{
+			Projections:       sotpProjections(3, segs),
+			WACC:              0.10,
+			TaxRate:           0.25,
+			SharesOutstanding: 10,
+			NetDebt:           100,
+		},
+		BaseSegments: segs,
+		BaseIncome:   baseIS,
+		Segments: []SegmentValuationConfig{
+			{Segment: "Hardware", Method: SegmentMultiple, Metric: SegmentEBITDA, Multiple: 8},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(res.Segments) != 2 || !almostEqual(res.Segments[0].EnterpriseValue, 900) || !almostEqual(res.Segments[1].EnterpriseValue, 800) {
+		t.Fatalf("unexpected segment values %+v", res.Segments)
+	}
+	// Overhead: 40 × (1 - 25%) = 30 a year forever at 10%
+	if !almostEqual(res.BaseOverhead, 40) || !almostEqual(res.OverheadValue, -300) {
+		t.Errorf("overhead %.2f valued at %.2f, want 40 and -300", res.BaseOverhead, res.OverheadValue)
+	}
+	if !almostEqual(res.EnterpriseValue, 1400) || !almostEqual(res.SharePrice, 130) {
+		t.Errorf("SOTP EV %.2f price %.2f, want 1400 and 130", res.EnterpriseValue, res.SharePrice)
+	}
+
+	// Consolidated FCFF of 150 at 10% = 1500 => the parts trade below the whole
+	if !almostEqual(res.ConsolidatedEV, 1500) || math.Abs(res.ConglomerateDiscount-(1-1500.0/1400)) > 1e-12 {
+		t.Errorf("consolidated EV %.2f, discount %.4f", res.ConsolidatedEV, res.ConglomerateDiscount)
+	}
+}
+
+func TestCalculateSOTP_Errors(t *testing.T) {
+	segs := []edgar.StandardizedSegment{segment("A", 100, 10, 0, 0)}
+	if _, err := CalculateSOTP(SOTPInput{Consolidated: DCFInput{Projections: flatProjections(2, 10, 0)}}); err == nil {
+		t.Error("expected error for projections without segments")
+	}
+	if _, err := CalculateSOTP(SOTPInput{Consolidated: DCFInput{Projections: sotpProjections(2, segs), WACC: 0.1}}); err == nil {
+		t.Error("expected error for a segment without T-0 data")
+	}
+	_, err := CalculateSOTP(SOTPInput{
+		Consolidated: DCFInput{Projections: sotpProjections(2, segs), WACC: 0.1},
+		BaseSegments: segs,
+		Segments:     []SegmentValuationConfig{{Segment: "A", Method: SegmentMultiple}},
+	})
+	if err == nil {
+		t.Error("expected error for a multiple method without a multiple")
+	}
+}
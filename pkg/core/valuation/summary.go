@@ -2,6 +2,14 @@ package valuation
 
 import (
 	"agentic_valuation/pkg/core/projection"
+	"fmt"
+	"math"
+)
+
+// RunAllValuations line names looked up by callers
+const (
+	ModelFCFF = "Free Cash Flow for All Debt and Equity Valuation" // FCFF DCF
+	ModelAPV  = "Adjusted Present Value (APV)"                     // Omitted when Ku cannot be derived
 )
 
 // MasterValuationInput aggregates all inputs needed for the full suite of models
@@ -10,6 +18,8 @@ type MasterValuationInput struct {
 	CurrentBookValue  float64 // B_0 (Equity)
 	SharesOutstanding float64
 	NetDebt           float64
+	Bridge            *EquityBridge       // Optional: itemized EV-to-equity bridge (overrides NetDebt)
+	Dilution          *DilutiveSecurities // Optional: options, RSUs and convertibles for diluted per-share values
 
 	// Rates
 	WACC           float64
@@ -17,76 +27,201 @@ type MasterValuationInput struct {
 	CostOfEquity   float64
 	TerminalGrowth float64
 	TaxRate        float64
+
+	// APV: unlevered cost of capital (0 = unlever WACC via CostOfEquity and PreTaxCostOfDebt)
+	// and pre-tax cost of debt for the shields
+	UnleveredCost    float64
+	PreTaxCostOfDebt float64
+
+	// Timing: valuation date, stub period and mid-year convention (applied to all models)
+	Timing DiscountTiming
+
+	// Terminal value method for the FCFF model (equity models use perpetuity growth)
+	Terminal TerminalValueConfig
 }
 
 // ValuationLineItem represents one row in the summary table (like the user's image)
 type ValuationLineItem struct {
-	ModelName  string
-	SharePrice float64
+	ModelName         string
+	SharePrice        float64 // Per basic share
+	EquityValue       float64
+	DilutedSharePrice float64 // Per fully diluted share (treasury stock / if-converted)
+	DilutedShares     float64
+}
+
+// EquityInput derives the shared equity-model input (DDM, RI, FCFE)
+func (m MasterValuationInput) EquityInput() EquityModelInput {
+	return EquityModelInput{
+		Projections:       m.Projections,
+		CostOfEquity:      m.CostOfEquity,
+		TerminalGrowth:    m.TerminalGrowth,
+		SharesOutstanding: m.SharesOutstanding,
+		CurrentBookValue:  m.CurrentBookValue,
+		Timing:            m.Timing,
+		Shares:            projection.ShareRolls(m.Projections),
+	}
+}
+
+// DCFInput derives the FCFF (DCF) input
+func (m MasterValuationInput) DCFInput() DCFInput {
+	return DCFInput{
+		Projections:       m.Projections,
+		WACC:              m.WACC,
+		PeriodWACCs:       m.PeriodWACCs,
+		TerminalGrowth:    m.TerminalGrowth,
+		SharesOutstanding: m.SharesOutstanding,
+		NetDebt:           m.NetDebt,
+		Bridge:            m.Bridge,
+		TaxRate:           m.TaxRate,
+		Timing:            m.Timing,
+		Terminal:          m.Terminal,
+	}
+}
+
+// APVInput derives the Adjusted Present Value input. Without an explicit Ku the
+// WACC is unlevered at the debt weight it implies (see unleveredCost).
+func (m MasterValuationInput) APVInput() APVInput {
+	ku := m.UnleveredCost
+	if ku == 0 {
+		ku = m.unleveredCost()
+	}
+	return APVInput{
+		Projections:       m.Projections,
+		UnleveredCost:     ku,
+		PreTaxCostOfDebt:  m.PreTaxCostOfDebt,
+		TaxRate:           m.TaxRate,
+		TerminalGrowth:    m.TerminalGrowth,
+		OpeningDebt:       m.bridge().Debt(),
+		SharesOutstanding: m.SharesOutstanding,
+		NetDebt:           m.NetDebt,
+		Bridge:            m.Bridge,
+		Timing:            m.Timing,
+		Terminal:          m.Terminal,
+	}
+}
+
+// EconomicProfitInput derives the EVA input; opening invested capital uses the
+// same identity as the projected years (NOA = equity + debt − cash)
+func (m MasterValuationInput) EconomicProfitInput() EconomicProfitInput {
+	return EconomicProfitInput{
+		Projections:            m.Projections,
+		WACC:                   m.WACC,
+		PeriodWACCs:            m.PeriodWACCs,
+		TaxRate:                m.TaxRate,
+		TerminalGrowth:         m.TerminalGrowth,
+		OpeningInvestedCapital: m.CurrentBookValue + m.bridge().Debt() - m.bridge().Cash(),
+		SharesOutstanding:      m.SharesOutstanding,
+		NetDebt:                m.NetDebt,
+		Bridge:                 m.Bridge,
+		Timing:                 m.Timing,
+		Terminal:               m.Terminal,
+	}
+}
+
+// unleveredCost backs Ku out of WACC: the debt weight solves
+// WACC = (1 − w)·Ke + w·Kd·(1 − t), and Ku = (1 − w)·Ke + w·Kd = WACC + w·t·Kd
+// (Harris-Pringle, as in CalculateAPV). An unlevered firm's WACC is Ku. Without a
+// PreTaxCostOfDebt, Kd is the first projected year's interest over opening debt.
+// Levered, it is 0 without Ke and Kd, which CalculateAPV rejects, rather than
+// discounting at WACC and double-counting the shields.
+func (m MasterValuationInput) unleveredCost() float64 {
+	debt := m.bridge().Debt()
+	if debt <= 0 {
+		return m.WACC
+	}
+	preTaxKd := m.PreTaxCostOfDebt
+	if preTaxKd == 0 && len(m.Projections) > 0 {
+		preTaxKd = projectedInterest(m.Projections[0]) / debt
+	}
+	kd := preTaxKd * (1 - m.TaxRate)
+	if m.CostOfEquity <= 0 || preTaxKd <= 0 || m.CostOfEquity <= kd {
+		return 0
+	}
+	w := math.Min(math.Max((m.CostOfEquity-m.WACC)/(m.CostOfEquity-kd), 0), 1)
+	return m.WACC + w*m.TaxRate*preTaxKd
+}
+
+func (m MasterValuationInput) bridge() *EquityBridge {
+	if m.Bridge != nil {
+		return m.Bridge
+	}
+	return NewNetDebtBridge(m.NetDebt)
 }
 
-// RunAllExecution performs DDM, RI, FCFE, and FCFF
-func RunAllValuations(input MasterValuationInput) []ValuationLineItem {
+// RunAllValuations performs DDM, RI, FCFE, FCFF, APV and economic profit and
+// returns the models' warnings. APV is left out when it cannot be valued (no Ku).
+func RunAllValuations(input MasterValuationInput) ([]ValuationLineItem, []string) {
 	results := []ValuationLineItem{}
+	var warnings []string
 
 	// 1. Prepare Equity Inputs
-	eqInput := EquityModelInput{
-		Projections:       input.Projections,
-		CostOfEquity:      input.CostOfEquity,
-		TerminalGrowth:    input.TerminalGrowth,
-		SharesOutstanding: input.SharesOutstanding,
-		CurrentBookValue:  input.CurrentBookValue,
-	}
+	eqInput := input.EquityInput()
 
 	// 2. Prepare FCFF Input (DCF)
-	dcfInput := DCFInput{
-		Projections:       input.Projections,
-		WACC:              input.WACC,
-		PeriodWACCs:       input.PeriodWACCs,
-		TerminalGrowth:    input.TerminalGrowth,
-		SharesOutstanding: input.SharesOutstanding,
-		NetDebt:           input.NetDebt,
-		TaxRate:           input.TaxRate,
-	}
+	dcfInput := input.DCFInput()
 
 	// --- Execute Models ---
 
 	// 1. Dividend Based Valuation
 	ddmRes := CalculateDDM(eqInput)
-	results = append(results, ValuationLineItem{
-		ModelName:  "Dividend Based Valuation",
-		SharePrice: ddmRes.SharePrice,
-	})
+	results = append(results, input.lineItem("Dividend Based Valuation", ddmRes.EquityValue, ddmRes.SharePrice))
 
 	// 2. Free Cash Flow Valuation (FCFE)
 	// Note: User image lists "Free Cash Flow Valuation" separate from "All Debt and Equity"
 	fcfeRes := CalculateFCFE(eqInput)
-	results = append(results, ValuationLineItem{
-		ModelName:  "Free Cash Flow Valuation (FCFE)",
-		SharePrice: fcfeRes.SharePrice,
-	})
+	results = append(results, input.lineItem("Free Cash Flow Valuation (FCFE)", fcfeRes.EquityValue, fcfeRes.SharePrice))
 
 	// 3. Residual Income Valuation
 	riRes := CalculateResidualIncome(eqInput)
-	results = append(results, ValuationLineItem{
-		ModelName:  "Residual Income Valuation",
-		SharePrice: riRes.SharePrice,
-	})
+	results = append(results, input.lineItem("Residual Income Valuation", riRes.EquityValue, riRes.SharePrice))
 
 	// 4. Residual Income Market-to-Book Valuation
 	// (Using same logic as RI for now, per standard equivalence)
 	riMtbRes := CalculateMarketToBookRI(eqInput)
-	results = append(results, ValuationLineItem{
-		ModelName:  "Residual Income Market-to-Book Valuation",
-		SharePrice: riMtbRes.SharePrice,
-	})
+	results = append(results, input.lineItem("Residual Income Market-to-Book Valuation", riMtbRes.EquityValue, riMtbRes.SharePrice))
 
 	// 5. Free Cash Flow for All Debt and Equity Valuation (FCFF)
 	dcfRes := CalculateDCF(dcfInput)
-	results = append(results, ValuationLineItem{
-		ModelName:  "Free Cash Flow for All Debt and Equity Valuation",
-		SharePrice: dcfRes.SharePrice,
-	})
+	results = append(results, input.lineItem(ModelFCFF, dcfRes.EquityValue, dcfRes.SharePrice))
+	warnings = appendWarnings(warnings, ModelFCFF, dcfRes.Warnings)
 
-	return results
+	// 6. Adjusted Present Value (unlevered value + interest tax shields)
+	apvInput := input.APVInput()
+	apvRes := CalculateAPV(apvInput)
+	if apvInput.UnleveredCost > 0 {
+		results = append(results, input.lineItem(ModelAPV, apvRes.EquityValue, apvRes.SharePrice))
+	}
+	warnings = appendWarnings(warnings, ModelAPV, apvRes.Warnings)
+
+	// 7. Economic Profit (invested capital + PV of NOA × (ROIC - WACC))
+	epRes := CalculateEconomicProfit(input.EconomicProfitInput())
+	results = append(results, input.lineItem("Economic Profit Valuation (EVA)", epRes.EquityValue, epRes.SharePrice))
+	warnings = appendWarnings(warnings, "Economic Profit Valuation (EVA)", epRes.Warnings)
+
+	return results, warnings
+}
+
+// appendWarnings labels a model's warnings with its line item name
+func appendWarnings(warnings []string, model string, msgs []string) []string {
+	for _, msg := range msgs {
+		warnings = append(warnings, fmt.Sprintf("%s: %s", model, msg))
+	}
+	return warnings
+}
+
+// lineItem reports a model's equity value per basic and per fully diluted share
+func (m MasterValuationInput) lineItem(name string, equityValue, basicPrice float64) ValuationLineItem {
+	item := ValuationLineItem{
+		ModelName:         name,
+		SharePrice:        basicPrice,
+		EquityValue:       equityValue,
+		DilutedSharePrice: basicPrice,
+		DilutedShares:     m.SharesOutstanding,
+	}
+	if m.Dilution != nil {
+		d := SolveDilutedShares(equityValue, m.SharesOutstanding, *m.Dilution)
+		item.DilutedSharePrice = d.SharePrice
+		item.DilutedShares = d.DilutedShares
+	}
+	return item
 }
@@ -0,0 +1,56 @@
+package valuation_test
+
+import (
+	"math"
+	"testing"
+
+	"agentic_valuation/pkg/core/internal/testfixture"
+	"agentic_valuation/pkg/core/projection"
+	"agentic_valuation/pkg/core/valuation"
+)
+
+// fixtureInput values the fixture company projected over the given years
+func fixtureInput(t *testing.T, years int) valuation.MasterValuationInput {
+	t.Helper()
+	projections, err := projection.NewProjectionEngine(nil).ProjectHorizon(projection.HorizonInput{
+		History: testfixture.History(), Assumptions: testfixture.Assumptions(), Years: years,
+	})
+	if err != nil {
+		t.Fatalf("project: %v", err)
+	}
+	input := testfixture.Valuation()
+	input.Projections = projections
+	return input
+}
+
+func TestRunAllValuations_ReportsDilutedPrices(t *testing.T) {
+	input := fixtureInput(t, 3)
+	input.Dilution = &valuation.DilutiveSecurities{RSUs: 1}
+	items, _ := valuation.RunAllValuations(input)
+	for _, item := range items {
+		if item.DilutedShares != 101 {
+			t.Errorf("%s: diluted shares %.2f, want 101", item.ModelName, item.DilutedShares)
+		}
+		if math.Abs(item.DilutedSharePrice-item.EquityValue/101) > 1e-9 || math.Abs(item.SharePrice-item.EquityValue/100) > 1e-9 {
+			t.Errorf("%s: diluted %.4f vs basic %.4f", item.ModelName, item.DilutedSharePrice, item.SharePrice)
+		}
+	}
+}
+
+func TestRunAllValuations_TimingAppliesToEveryModel(t *testing.T) {
+	base := fixtureInput(t, 3)
+	midYear := base
+	midYear.Timing = valuation.DiscountTiming{MidYear: true}
+
+	endRes, _ := valuation.RunAllValuations(base)
+	midRes, _ := valuation.RunAllValuations(midYear)
+	if len(endRes) != len(midRes) {
+		t.Fatalf("timing changed the models valued: %d vs %d", len(endRes), len(midRes))
+	}
+	for i := range endRes {
+		if midRes[i].SharePrice <= endRes[i].SharePrice {
+			t.Errorf("%s: mid-year value %.4f should exceed end-of-year %.4f",
+				endRes[i].ModelName, midRes[i].SharePrice, endRes[i].SharePrice)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package valuation
+
+import "fmt"
+
+// TerminalMethod selects how CalculateDCF capitalizes the post-horizon cash flows
+type TerminalMethod string
+
+const (
+	TerminalPerpetuityGrowth TerminalMethod = "perpetuity_growth" // Gordon growth on UFCF (default)
+	TerminalExitEBITDA       TerminalMethod = "exit_ebitda"       // EV/EBITDA multiple on final-year EBITDA
+	TerminalExitEBIT         TerminalMethod = "exit_ebit"         // EV/EBIT multiple on final-year EBIT
+	TerminalValueDriver      TerminalMethod = "value_driver"      // NOPAT*(1-g/RONIC)/(WACC-g)
+)
+
+// DefaultMaxTerminalShare is the TV/EV ratio above which the cross-check warns
+const DefaultMaxTerminalShare = 0.75
+
+// TerminalValueConfig configures the terminal value of the DCF.
+// The zero value is perpetuity growth with the default cross-check threshold.
+type TerminalValueConfig struct {
+	Method       TerminalMethod `json:"method"`
+	ExitMultiple float64        `json:"exit_multiple,omitempty"`   // EV/EBITDA or EV/EBIT for exit methods
+	RONIC        float64        `json:"ronic,omitempty"`           // Return on new invested capital (0 = WACC, i.e. growth adds no value)
+	MaxShareOfEV float64        `json:"max_share_of_ev,omitempty"` // Warn when PV(TV)/EV exceeds this (0 = DefaultMaxTerminalShare)
+}
+
+// terminalBase holds the final-year metrics every method draws on
+type terminalBase struct {
+	UFCF   float64 // Final projection year unlevered FCF
+	EBITDA float64
+	EBIT   float64
+	NOPAT  float64 // EBIT * (1 - t)
+}
+
+// terminalValue computes TV under the configured method at the horizon.
+// perpetuity reports whether the value is a growing perpetuity (discounted with
+// the mid-year adjustment) rather than an exit price.
+func terminalValue(cfg TerminalValueConfig, base terminalBase, wacc, g float64) (tv float64, perpetuity bool, err error) {
+	switch cfg.Method {
+	case "", TerminalPerpetuityGrowth:
+		if wacc <= g {
+			return 0, true, fmt.Errorf("WACC %.4f must exceed terminal growth %.4f", wacc, g)
+		}
+		return base.UFCF * (1 + g) / (wacc - g), true, nil
+	case TerminalExitEBITDA:
+		if cfg.ExitMultiple <= 0 {
+			return 0, false, fmt.Errorf("exit EV/EBITDA multiple must be positive")
+		}
+		return base.EBITDA * cfg.ExitMultiple, false, nil
+	case TerminalExitEBIT:
+		if cfg.ExitMultiple <= 0 {
+			return 0, false, fmt.Errorf("exit EV/EBIT multiple must be positive")
+		}
+		return base.EBIT * cfg.ExitMultiple, false, nil
+	case TerminalValueDriver:
+		if wacc <= g {
+			return 0, true, fmt.Errorf("WACC %.4f must exceed terminal growth %.4f", wacc, g)
+		}
+		ronic := cfg.RONIC
+		if ronic == 0 {
+			ronic = wacc
+		}
+		if ronic <= 0 {
+			return 0, true, fmt.Errorf("RONIC must be positive (got %.4f)", ronic)
+		}
+		return base.NOPAT * (1 + g) * (1 - g/ronic) / (wacc - g), true, nil
+	default:
+		return 0, false, fmt.Errorf("unknown terminal value method '%s'", cfg.Method)
+	}
+}
+
+// impliedPerpetuityGrowth solves TV = UFCF*(1+g)/(WACC-g) for g
+func impliedPerpetuityGrowth(tv, ufcf, wacc float64) float64 {
+	if tv+ufcf == 0 {
+		return 0
+	}
+	return (tv*wacc - ufcf) / (tv + ufcf)
+}
@@ -0,0 +1,92 @@
+package valuation
+
+import (
+	"testing"
+
+	"agentic_valuation/pkg/core/edgar"
+	"agentic_valuation/pkg/core/projection"
+)
+
+// operatingProjections adds EBIT = 80 and D&A = 20 to flat CFO = 100 years
+func operatingProjections(n int) []*projection.ProjectedFinancials {
+	out := flatProjections(n, 100, 0)
+	for _, p := range out {
+		p.IncomeStatement.OperatingCostSection = &edgar.OperatingCostSection{OperatingIncome: fsap(80)}
+		p.CashFlow.OperatingActivities = &edgar.CFOperatingSection{DepreciationAmortization: fsap(20)}
+	}
+	return out
+}
+
+func TestCalculateDCF_ExitMultipleReportsImpliedGrowth(t *testing.T) {
+	input := DCFInput{
+		Projections:       operatingProjections(3),
+		WACC:              0.10,
+		TerminalGrowth:    0.02,
+		SharesOutstanding: 1,
+		TaxRate:           0.25,
+		Terminal:          TerminalValueConfig{Method: TerminalExitEBITDA, ExitMultiple: 10},
+	}
+	exit := CalculateDCF(input)
+	if !almostEqual(exit.TerminalValue, 1000) {
+		t.Fatalf("TV = %.4f, want 10x EBITDA of 100", exit.TerminalValue)
+	}
+	if !almostEqual(exit.ImpliedMultiple, 10) {
+		t.Errorf("implied EV/EBITDA = %.4f, want 10", exit.ImpliedMultiple)
+	}
+
+	// Capitalizing at the implied growth must reproduce the exit value
+	input.Terminal = TerminalValueConfig{}
+	input.TerminalGrowth = exit.ImpliedGrowth
+	growth := CalculateDCF(input)
+	if !almostEqual(growth.TerminalValue, exit.TerminalValue) {
+		t.Errorf("perpetuity at implied g %.4f gives TV %.4f, want %.4f", exit.ImpliedGrowth, growth.TerminalValue, exit.TerminalValue)
+	}
+	if !almostEqual(growth.ImpliedMultiple, 10) {
+		t.Errorf("perpetuity method should report implied multiple 10, got %.4f", growth.ImpliedMultiple)
+	}
+}
+
+func TestCalculateDCF_ValueDriver(t *testing.T) {
+	input := DCFInput{
+		Projections:       operatingProjections(2),
+		WACC:              0.10,
+		TerminalGrowth:    0.03,
+		SharesOutstanding: 1,
+		TaxRate:           0.25,
+		Terminal:          TerminalValueConfig{Method: TerminalValueDriver},
+	}
+	// RONIC defaults to WACC: growth creates no value, TV = NOPAT_{n+1} * (1 - g/WACC) / (WACC - g) = NOPAT*(1+g)/WACC
+	res := CalculateDCF(input)
+	want := 60 * 1.03 / 0.10
+	if !almostEqual(res.TerminalValue, want) {
+		t.Errorf("TV = %.4f, want %.4f", res.TerminalValue, want)
+	}
+
+	input.Terminal.RONIC = 0.20
+	if higher := CalculateDCF(input); higher.TerminalValue <= res.TerminalValue {
+		t.Errorf("RONIC above WACC should raise TV (%.4f vs %.4f)", higher.TerminalValue, res.TerminalValue)
+	}
+}
+
+func TestCalculateDCF_TerminalShareWarning(t *testing.T) {
+	input := DCFInput{
+		Projections:       operatingProjections(2),
+		WACC:              0.08,
+		TerminalGrowth:    0.03,
+		SharesOutstanding: 1,
+	}
+	res := CalculateDCF(input)
+	if !res.TerminalShareWarning || len(res.Warnings) == 0 {
+		t.Errorf("expected warning at TV share %.2f", res.TerminalShareOfEV)
+	}
+
+	input.Terminal.MaxShareOfEV = 0.99
+	if res := CalculateDCF(input); res.TerminalShareWarning {
+		t.Errorf("TV share %.2f should pass a 99%% threshold", res.TerminalShareOfEV)
+	}
+
+	input.Terminal = TerminalValueConfig{Method: TerminalExitEBIT}
+	if res := CalculateDCF(input); res.TerminalValue != 0 || len(res.Warnings) == 0 {
+		t.Error("exit method without a multiple should warn and drop the terminal value")
+	}
+}
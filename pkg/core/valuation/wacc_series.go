@@ -2,6 +2,9 @@ package valuation
 
 import (
 	"agentic_valuation/pkg/core/projection"
+	"fmt"
+	"math"
+	"strings"
 )
 
 // GenerateDynamicWACCSeries calculates WACC for each projection year based on projected capital structure
@@ -47,3 +50,206 @@ func GenerateDynamicWACCSeries(
 
 	return waccs
 }
+
+// RatingSpread maps a minimum interest coverage (EBIT / interest) to a
+// synthetic credit rating and its default spread over the risk-free rate
+type RatingSpread struct {
+	Rating      string  `json:"rating"`
+	MinCoverage float64 `json:"min_coverage"`
+	Spread      float64 `json:"spread"`
+}
+
+// DefaultRatingGrid is a large-cap synthetic rating table, best rating first
+var DefaultRatingGrid = []RatingSpread{
+	{"AAA", 8.5, 0.0059},
+	{"AA", 6.5, 0.0070},
+	{"A+", 5.5, 0.0092},
+	{"A", 4.25, 0.0107},
+	{"A-", 3.0, 0.0121},
+	{"BBB", 2.5, 0.0156},
+	{"BB+", 2.25, 0.0200},
+	{"BB", 2.0, 0.0240},
+	{"B+", 1.75, 0.0286},
+	{"B", 1.5, 0.0351},
+	{"B-", 1.25, 0.0421},
+	{"CCC", 0.8, 0.0515},
+	{"CC", 0.65, 0.0808},
+	{"C", 0.2, 0.1100},
+	{"D", math.Inf(-1), 0.1500},
+}
+
+// SpreadForCoverage returns the first grid row whose minimum coverage is met.
+// The grid must be sorted by MinCoverage, highest first.
+func SpreadForCoverage(grid []RatingSpread, coverage float64) RatingSpread {
+	for _, r := range grid {
+		if coverage >= r.MinCoverage {
+			return r
+		}
+	}
+	if len(grid) == 0 {
+		return RatingSpread{}
+	}
+	return grid[len(grid)-1]
+}
+
+// WACCTermStructureInput drives a per-year WACC consistent with the valuation:
+// each year's D/E uses projected book debt against the market value of equity
+// implied by discounting the remaining cash flows at the WACCs being solved for.
+type WACCTermStructureInput struct {
+	Base           WACCInput // Betas, rates and tax; DebtToEquityRatio is the fallback leverage
+	Projections    []*projection.ProjectedFinancials
+	TerminalGrowth float64
+	Terminal       TerminalValueConfig
+	RatingGrid     []RatingSpread // Optional: price debt off interest coverage (nil = Base.PreTaxCostOfDebt)
+	MaxIterations  int            // Default 50
+	Tolerance      float64        // Max WACC change between iterations, default 1e-7
+}
+
+// WACCYear explains one year of the term structure
+type WACCYear struct {
+	Year             int     `json:"year"`
+	Debt             float64 `json:"debt"` // Book debt from the projected balance sheet
+	Cash             float64 `json:"cash"`
+	EnterpriseValue  float64 `json:"enterprise_value"` // Value of remaining cash flows at year end
+	MarketEquity     float64 `json:"market_equity"`    // EnterpriseValue - (Debt - Cash)
+	DebtToEquity     float64 `json:"debt_to_equity"`
+	LeveredBeta      float64 `json:"levered_beta"`
+	CostOfEquity     float64 `json:"cost_of_equity"`
+	InterestCoverage float64 `json:"interest_coverage"` // EBIT / interest expense (0 when there is no interest)
+	Rating           string  `json:"rating,omitempty"`
+	PreTaxCostOfDebt float64 `json:"pre_tax_cost_of_debt"`
+	CostOfDebt       float64 `json:"cost_of_debt"` // After-tax
+	WeightDebt       float64 `json:"weight_debt"`
+	WACC             float64 `json:"wacc"`
+	Note             string  `json:"note,omitempty"`
+}
+
+// WACCTermStructure is the solved per-year WACC with its build-up
+type WACCTermStructure struct {
+	Years      []WACCYear `json:"years"`
+	Iterations int        `json:"iterations"`
+	Converged  bool       `json:"converged"`
+}
+
+// Rates returns the WACC series for DCFInput.PeriodWACCs
+func (ts *WACCTermStructure) Rates() []float64 {
+	rates := make([]float64, len(ts.Years))
+	for i, y := range ts.Years {
+		rates[i] = y.WACC
+	}
+	return rates
+}
+
+// Markdown renders the build-up so reviewers can see why the rate moves
+func (ts *WACCTermStructure) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString("**WACC Term Structure**\n\n")
+	sb.WriteString("| Year | Debt | Mkt Equity | D/E | Beta | Ke | Coverage | Rating | Kd (pre-tax) | WACC | Note |\n")
+	sb.WriteString("|---|---|---|---|---|---|---|---|---|---|---|\n")
+	for _, y := range ts.Years {
+		fmt.Fprintf(&sb, "| %d | %.1f | %.1f | %.3f | %.3f | %.2f%% | %.2fx | %s | %.2f%% | %.2f%% | %s |\n",
+			y.Year, y.Debt, y.MarketEquity, y.DebtToEquity, y.LeveredBeta, y.CostOfEquity*100,
+			y.InterestCoverage, y.Rating, y.PreTaxCostOfDebt*100, y.WACC*100, y.Note)
+	}
+	if !ts.Converged {
+		fmt.Fprintf(&sb, "\n_Not converged after %d iterations._\n", ts.Iterations)
+	}
+	return sb.String()
+}
+
+// SolveWACCTermStructure iterates WACC and market-value leverage to a fixed point.
+// It starts from book leverage (as GenerateDynamicWACCSeries does), values the
+// remaining cash flows at each year end, and re-levers beta on the resulting D/E.
+// Weights use year-end capital structure; the stub period and mid-year convention
+// only affect the DCF itself, not the leverage weights.
+func SolveWACCTermStructure(input WACCTermStructureInput) (*WACCTermStructure, error) {
+	n := len(input.Projections)
+	if n == 0 {
+		return nil, fmt.Errorf("WACC term structure needs projections")
+	}
+	maxIter := input.MaxIterations
+	if maxIter <= 0 {
+		maxIter = 50
+	}
+	tol := input.Tolerance
+	if tol <= 0 {
+		tol = 1e-7
+	}
+
+	ts := &WACCTermStructure{Years: make([]WACCYear, n)}
+	ufcf := make([]float64, n)
+	var terminal terminalBase
+	for i, proj := range input.Projections {
+		ufcf[i], terminal = dcfYearMetrics(proj, input.Base.TaxRate)
+
+		y := &ts.Years[i]
+		y.Year = proj.Year
+		y.Debt = projectedDebt(proj)
+		if proj.BalanceSheet != nil {
+			y.Cash = getValSafe(proj.BalanceSheet.CurrentAssets.CashAndEquivalents)
+		}
+
+		// Cost of debt: synthetic rating from coverage, or the flat input
+		y.PreTaxCostOfDebt = input.Base.PreTaxCostOfDebt
+		var interest float64
+		if proj.IncomeStatement != nil && proj.IncomeStatement.NonOperatingSection != nil {
+			interest = math.Abs(getValSafe(proj.IncomeStatement.NonOperatingSection.InterestExpense))
+		}
+		coverage := math.Inf(1)
+		if interest > 0 {
+			coverage = terminal.EBIT / interest
+			y.InterestCoverage = coverage
+		}
+		if len(input.RatingGrid) > 0 {
+			r := SpreadForCoverage(input.RatingGrid, coverage)
+			y.Rating = r.Rating
+			y.PreTaxCostOfDebt = input.Base.RiskFreeRate + r.Spread
+		}
+	}
+
+	// Seed with book leverage
+	rates := GenerateDynamicWACCSeries(input.Base, input.Projections)
+	for iter := 1; iter <= maxIter; iter++ {
+		ts.Iterations = iter
+
+		// Year-end value of the remaining cash flows, rolled back from the terminal value
+		tv, _, err := terminalValue(input.Terminal, terminal, rates[n-1], input.TerminalGrowth)
+		if err != nil {
+			return nil, fmt.Errorf("terminal value: %w", err)
+		}
+		ev := tv
+		maxChange := 0.0
+		for i := n - 1; i >= 0; i-- {
+			y := &ts.Years[i]
+			y.EnterpriseValue = ev
+			y.MarketEquity = ev - (y.Debt - y.Cash)
+
+			yearInput := input.Base
+			yearInput.PreTaxCostOfDebt = y.PreTaxCostOfDebt
+			y.Note = ""
+			if y.MarketEquity > 0 {
+				yearInput.DebtToEquityRatio = y.Debt / y.MarketEquity
+			} else {
+				y.Note = "non-positive equity value; target D/E used"
+			}
+
+			res := CalculateWACC(yearInput)
+			y.DebtToEquity = yearInput.DebtToEquityRatio
+			y.LeveredBeta = res.LeveredBeta
+			y.CostOfEquity = res.CostOfEquity
+			y.CostOfDebt = res.CostOfDebt
+			y.WeightDebt = res.WeightDebt
+			y.WACC = res.WACC
+
+			maxChange = math.Max(maxChange, math.Abs(res.WACC-rates[i]))
+			ev = (ev + ufcf[i]) / (1 + rates[i])
+		}
+
+		rates = ts.Rates()
+		if maxChange < tol {
+			ts.Converged = true
+			break
+		}
+	}
+	return ts, nil
+}
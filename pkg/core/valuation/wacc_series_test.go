@@ -0,0 +1,78 @@
+package valuation
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"agentic_valuation/pkg/core/edgar"
+)
+
+func TestSpreadForCoverage(t *testing.T) {
+	cases := map[float64]string{12: "AAA", 8: "AA", 2.6: "BBB", 0.1: "D", -3: "D"}
+	for coverage, want := range cases {
+		if got := SpreadForCoverage(DefaultRatingGrid, coverage).Rating; got != want {
+			t.Errorf("coverage %.2f: rating %s, want %s", coverage, got, want)
+		}
+	}
+}
+
+func TestSolveWACCTermStructure_MarketValueLeverage(t *testing.T) {
+	projections := operatingProjections(3)
+	for i, p := range projections {
+		// Deleveraging: debt 300 -> 200 -> 100, interest covering EBIT 80 at 8x
+		p.BalanceSheet = &edgar.BalanceSheet{
+			NoncurrentLiabilities: edgar.NoncurrentLiabilities{LongTermDebt: fsap(300 - 100*float64(i))},
+			CurrentAssets:         edgar.CurrentAssets{CashAndEquivalents: fsap(50)},
+		}
+		p.IncomeStatement.NonOperatingSection = &edgar.NonOperatingSection{InterestExpense: fsap(10)}
+	}
+	base := WACCInput{
+		UnleveredBeta:     1.0,
+		RiskFreeRate:      0.04,
+		MarketRiskPremium: 0.05,
+		PreTaxCostOfDebt:  0.06,
+		TaxRate:           0.25,
+		DebtToEquityRatio: 0.5,
+	}
+
+	ts, err := SolveWACCTermStructure(WACCTermStructureInput{
+		Base:           base,
+		Projections:    projections,
+		TerminalGrowth: 0.02,
+		RatingGrid:     DefaultRatingGrid,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ts.Converged {
+		t.Fatalf("expected convergence, stopped after %d iterations", ts.Iterations)
+	}
+
+	rates := ts.Rates()
+	for i, y := range ts.Years {
+		if y.Rating != "AA" || !almostEqual(y.PreTaxCostOfDebt, 0.047) {
+			t.Errorf("year %d: rating %s at %.4f, want AA at 4.70%%", y.Year, y.Rating, y.PreTaxCostOfDebt)
+		}
+		// Fixed point: each rate re-levers beta on the D/E implied by the rates themselves
+		in := base
+		in.PreTaxCostOfDebt = y.PreTaxCostOfDebt
+		in.DebtToEquityRatio = y.Debt / (y.EnterpriseValue - (y.Debt - y.Cash))
+		if math.Abs(CalculateWACC(in).WACC-rates[i]) > 1e-6 {
+			t.Errorf("year %d: WACC %.6f is not consistent with its market D/E", y.Year, rates[i])
+		}
+	}
+	if !(rates[0] < rates[1] && rates[1] < rates[2]) {
+		t.Errorf("expected WACC to rise as cheap debt is repaid, got %v", rates)
+	}
+
+	// The final year-end value is the terminal value the DCF capitalizes at the same rates
+	dcf := CalculateDCF(DCFInput{Projections: projections, PeriodWACCs: rates, TerminalGrowth: 0.02, TaxRate: 0.25, SharesOutstanding: 1})
+	if math.Abs(ts.Years[2].EnterpriseValue-dcf.TerminalValue) > 1e-3 {
+		t.Errorf("year-end EV %.4f, DCF terminal value %.4f", ts.Years[2].EnterpriseValue, dcf.TerminalValue)
+	}
+
+	if md := ts.Markdown(); !strings.Contains(md, "| 2027 |") {
+		t.Errorf("markdown missing final year:\n%s", md)
+	}
+}
@@ -0,0 +1,37 @@
+-- Migration: Add Projection Scenarios
+-- Version: 202610161000
+-- Description: Adds projection_scenarios for bull / base / bear overrides, probabilities,
+--              applied assumption sets and results. A scenario links to the
+--              projection_assumptions row it was projected from via assumptions_id.
+
+-- ============================================================
+-- Table: projection_scenarios
+-- Stores scenario.Scenario with its applied AssumptionSet and Outcome
+-- ============================================================
+CREATE TABLE IF NOT EXISTS projection_scenarios (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    case_id UUID REFERENCES cases(id) ON DELETE CASCADE,
+    scenario_id TEXT NOT NULL,            -- 'bull', 'base', 'bear', ...
+    name TEXT NOT NULL,
+    description TEXT DEFAULT '',
+    probability NUMERIC(10, 6) NOT NULL DEFAULT 0,
+
+    overrides JSONB DEFAULT '{}',         -- {"revenue_growth": 0.12, "wacc": 0.085}
+    assumption_set JSONB,                 -- assumption.AssumptionSet after overrides
+    results JSONB,                        -- scenario.Outcome (key items, valuations)
+
+    -- Link to the projection_assumptions row the scenario was projected from
+    assumptions_id UUID REFERENCES projection_assumptions(id) ON DELETE SET NULL,
+
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+
+    UNIQUE(case_id, scenario_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_projection_scenarios_case ON projection_scenarios(case_id);
+
+CREATE TRIGGER update_projection_scenarios_updated_at
+    BEFORE UPDATE ON projection_scenarios
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();